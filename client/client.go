@@ -0,0 +1,290 @@
+// Package client is a typed Go client for the TwentyFive HTTP API.
+//
+// Request and response types are the server's own, so they cannot drift from
+// what the API accepts. Errors returned by the server are translated back
+// into the package's sentinel errors, which match the server's with
+// errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"twentyfive/internal/app"
+)
+
+// Types shared with the server.
+type (
+	Board               = app.BoardState
+	Task                = app.Task
+	Category            = app.Category
+	BoardStats          = app.BoardStats
+	CreateTaskRequest   = app.CreateTaskRequest
+	MoveTaskRequest     = app.MoveTaskRequest
+	MoveCategoryRequest = app.MoveCategoryRequest
+	TaskPatch           = app.TaskPatch
+	TaskUpdate          = app.TaskUpdate
+)
+
+// Sentinel errors, identical to the server's.
+var (
+	ErrTaskNotFound      = app.ErrTaskNotFound
+	ErrCategoryNotFound  = app.ErrCategoryNotFound
+	ErrCapacityExceeded  = app.ErrCapacityExceeded
+	ErrInvalidState      = app.ErrInvalidState
+	ErrInvalidLocation   = app.ErrInvalidLocation
+	ErrInvalidTaskSize   = app.ErrInvalidTaskSize
+	ErrInvalidRequest    = app.ErrInvalidRequest
+	ErrDuplicateCategory = app.ErrDuplicateCategory
+	ErrCategoryLimit     = app.ErrCategoryLimit
+	ErrFileExists        = app.ErrFileExists
+	ErrInvalidTransition = app.ErrInvalidTransition
+	ErrStateInUse        = app.ErrStateInUse
+	ErrSizeInUse         = app.ErrSizeInUse
+	ErrNotFocusable      = app.ErrNotFocusable
+	ErrTaskLimit         = app.ErrTaskLimit
+	ErrBoardTooLarge     = app.ErrBoardTooLarge
+	ErrPatchConflict     = app.ErrPatchConflict
+)
+
+// sentinels are matched against error messages in the order listed; the
+// server formats wrapped errors as "<sentinel>: <detail>".
+var sentinels = []error{
+	ErrTaskNotFound,
+	ErrCategoryNotFound,
+	ErrCapacityExceeded,
+	ErrInvalidState,
+	ErrInvalidLocation,
+	ErrInvalidTaskSize,
+	ErrInvalidRequest,
+	ErrDuplicateCategory,
+	ErrCategoryLimit,
+	ErrFileExists,
+	ErrInvalidTransition,
+	ErrStateInUse,
+	ErrSizeInUse,
+	ErrNotFocusable,
+	ErrTaskLimit,
+	ErrBoardTooLarge,
+	ErrPatchConflict,
+}
+
+// APIError is a non-2xx response. It unwraps to the matching sentinel error
+// when the server reported one.
+type APIError struct {
+	StatusCode int
+	Message    string
+	sentinel   error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("twentyfive: %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.sentinel
+}
+
+// Client calls one TwentyFive server. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	token   string
+	actor   string
+	http    *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithToken sends token as a bearer credential, for servers behind an
+// authenticating proxy.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient replaces http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithActor names who is making changes, recorded in task history.
+func WithActor(actor string) Option {
+	return func(c *Client) {
+		c.actor = actor
+	}
+}
+
+// New returns a client for the server at baseURL, such as
+// "http://localhost:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/") + app.APIPrefix,
+		http:    http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetBoard fetches the whole board.
+func (c *Client) GetBoard(ctx context.Context) (Board, error) {
+	var board Board
+	err := c.do(ctx, http.MethodGet, "/board", nil, &board)
+	return board, err
+}
+
+// GetStats fetches board statistics.
+func (c *Client) GetStats(ctx context.Context) (BoardStats, error) {
+	var stats BoardStats
+	err := c.do(ctx, http.MethodGet, "/board/stats", nil, &stats)
+	return stats, err
+}
+
+// CreateTask adds a task and returns it as stored.
+func (c *Client) CreateTask(ctx context.Context, req CreateTaskRequest) (Task, error) {
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	err := c.do(ctx, http.MethodPost, "/tasks", req, &resp)
+	return resp.Task, err
+}
+
+// PatchTask applies patch to a task.
+func (c *Client) PatchTask(ctx context.Context, id string, patch TaskPatch) (TaskUpdate, error) {
+	var resp struct {
+		Task          Task    `json:"task"`
+		PreviousState *string `json:"previousState"`
+	}
+	if err := c.do(ctx, http.MethodPatch, "/tasks/"+url.PathEscape(id), patch, &resp); err != nil {
+		return TaskUpdate{}, err
+	}
+	// the server only reports previousState when the state changed
+	update := TaskUpdate{Task: resp.Task, PreviousState: resp.Task.State}
+	if resp.PreviousState != nil {
+		update.PreviousState = *resp.PreviousState
+	}
+	return update, nil
+}
+
+// MoveTask moves a task and returns it as placed.
+func (c *Client) MoveTask(ctx context.Context, id string, req MoveTaskRequest) (Task, error) {
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	err := c.do(ctx, http.MethodPost, "/tasks/"+url.PathEscape(id)+"/move", req, &resp)
+	return resp.Task, err
+}
+
+// DeleteTask permanently removes an archived task.
+func (c *Client) DeleteTask(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/tasks/"+url.PathEscape(id), nil, nil)
+}
+
+// SetFocus makes taskID the board's focused task.
+func (c *Client) SetFocus(ctx context.Context, taskID string) (Task, error) {
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	err := c.do(ctx, http.MethodPost, "/board/focus", app.FocusRequest{TaskID: taskID}, &resp)
+	return resp.Task, err
+}
+
+// CreateCategory adds an active category.
+func (c *Client) CreateCategory(ctx context.Context, name string) (Category, error) {
+	var resp struct {
+		Category Category `json:"category"`
+	}
+	err := c.do(ctx, http.MethodPost, "/categories", map[string]string{"name": name}, &resp)
+	return resp.Category, err
+}
+
+// MoveCategory moves a category between the board, backburner and archive.
+func (c *Client) MoveCategory(ctx context.Context, id string, req MoveCategoryRequest) (Category, error) {
+	var resp struct {
+		Category Category `json:"category"`
+	}
+	err := c.do(ctx, http.MethodPost, "/categories/"+url.PathEscape(id)+"/move", req, &resp)
+	return resp.Category, err
+}
+
+// do sends body as JSON and decodes a successful response into out. Mutation
+// responses are requested without the board to keep them small.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+	target := c.baseURL + path
+	if method != http.MethodGet {
+		target += "?includeBoard=false"
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.actor != "" {
+		req.Header.Set("X-Actor", c.actor)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return responseError(resp.StatusCode, data)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// responseError builds an APIError from an error body, which is usually
+// {"error": "..."} but may be plain text.
+func responseError(status int, data []byte) error {
+	var envelope struct {
+		Error string `json:"error"`
+	}
+	msg := strings.TrimSpace(string(data))
+	if err := json.Unmarshal(data, &envelope); err == nil && envelope.Error != "" {
+		msg = envelope.Error
+	}
+	apiErr := &APIError{StatusCode: status, Message: msg}
+	for _, sentinel := range sentinels {
+		if msg == sentinel.Error() || strings.HasPrefix(msg, sentinel.Error()+":") {
+			apiErr.sentinel = sentinel
+			break
+		}
+	}
+	return apiErr
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"twentyfive/internal/app"
+)
+
+const testBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"t1","name":"One","description":"","notes":"","state":"doing","size":2},
+			{"id":"t2","name":"Two","description":"","notes":"","state":"todo","size":3}
+		]},
+		{"id":"cat2","name":"Beta","tasks":[]}
+	],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func newTestClient(t *testing.T, opts ...Option) *Client {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "board.json")
+	if err := os.WriteFile(path, []byte(testBoard), 0o644); err != nil {
+		t.Fatalf("write data: %v", err)
+	}
+	store, err := app.NewStore(path)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	srv := httptest.NewServer(app.NewServer(store))
+	t.Cleanup(srv.Close)
+	return New(srv.URL+"/", opts...)
+}
+
+func TestClientTaskLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, WithActor("alice"))
+
+	task, err := c.CreateTask(ctx, CreateTaskRequest{Location: app.LocationCategory, CategoryID: "cat2", Task: Task{ID: "t3", Name: "Three", State: "todo", Size: 1}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if task.ID != "t3" || task.CreatedAt.IsZero() {
+		t.Fatalf("expected stored task back, got %+v", task)
+	}
+
+	state := "doing"
+	update, err := c.PatchTask(ctx, "t3", TaskPatch{State: &state})
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if update.PreviousState != "todo" || update.Task.State != "doing" {
+		t.Fatalf("expected todo -> doing, got %s -> %s", update.PreviousState, update.Task.State)
+	}
+	if got := update.Task.History[len(update.Task.History)-1].Actor; got != "alice" {
+		t.Fatalf("expected actor alice recorded, got %q", got)
+	}
+	name := "Three!"
+	if update, err = c.PatchTask(ctx, "t3", TaskPatch{Name: &name}); err != nil || update.PreviousState != "doing" {
+		t.Fatalf("expected unchanged state reported, got %q %v", update.PreviousState, err)
+	}
+
+	if _, err := c.SetFocus(ctx, "t3"); err != nil {
+		t.Fatalf("focus: %v", err)
+	}
+	moved, err := c.MoveTask(ctx, "t3", MoveTaskRequest{Location: app.LocationArchive})
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if moved.Focused || moved.SourceID != "cat2" {
+		t.Fatalf("expected archived task with source cat2, got %+v", moved)
+	}
+	if err := c.DeleteTask(ctx, "t3"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	board, err := c.GetBoard(ctx)
+	if err != nil {
+		t.Fatalf("get board: %v", err)
+	}
+	if len(board.Archives) != 0 || len(board.Categories[1].Tasks) != 0 {
+		t.Fatalf("expected t3 gone, got %+v", board)
+	}
+}
+
+func TestClientCategories(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	cat, err := c.CreateCategory(ctx, "Gamma")
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	if _, err := c.CreateCategory(ctx, "Gamma"); !errors.Is(err, ErrDuplicateCategory) {
+		t.Fatalf("expected ErrDuplicateCategory, got %v", err)
+	}
+	moved, err := c.MoveCategory(ctx, cat.ID, MoveCategoryRequest{Location: app.LocationBackburner})
+	if err != nil {
+		t.Fatalf("move category: %v", err)
+	}
+	if moved.ID != cat.ID {
+		t.Fatalf("expected %s moved, got %s", cat.ID, moved.ID)
+	}
+	stats, err := c.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.CategoryCounts.Backburner != 1 || stats.ActiveTasks != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestClientTranslatesErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	_, err := c.MoveTask(ctx, "missing", MoveTaskRequest{Location: app.LocationArchive})
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a 404 APIError, got %v", err)
+	}
+
+	// Alpha keeps 3 points once t1 leaves, so a size 3 task overflows it
+	_, err = c.MoveTask(ctx, "t1", MoveTaskRequest{Location: app.LocationCategory, CategoryID: "cat2"})
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	_, err = c.CreateTask(ctx, CreateTaskRequest{Location: app.LocationCategory, CategoryID: "cat1", Task: Task{Name: "Big", State: "todo", Size: 3}})
+	if !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected ErrCapacityExceeded, got %v", err)
+	}
+	if _, err := c.SetFocus(ctx, "missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestSentinelsCoverServerErrors(t *testing.T) {
+	// returned by the migrate command only, never over HTTP
+	skip := map[string]bool{"ErrMigrationMismatch": true}
+
+	mapped := map[string]bool{}
+	for _, sentinel := range sentinels {
+		mapped[sentinel.Error()] = true
+	}
+	files, err := filepath.Glob("../internal/app/*.go")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	fset := token.NewFileSet()
+	found := 0
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, ident := range vs.Names {
+					if !strings.HasPrefix(ident.Name, "Err") || !ident.IsExported() || skip[ident.Name] || i >= len(vs.Values) {
+						continue
+					}
+					call, ok := vs.Values[i].(*ast.CallExpr)
+					if !ok || len(call.Args) != 1 {
+						t.Fatalf("%s: expected errors.New(...)", ident.Name)
+					}
+					lit, ok := call.Args[0].(*ast.BasicLit)
+					if !ok {
+						t.Fatalf("%s: expected a literal message", ident.Name)
+					}
+					msg, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						t.Fatalf("%s: %v", ident.Name, err)
+					}
+					found++
+					if !mapped[msg] {
+						t.Errorf("app.%s is missing from sentinels", ident.Name)
+					}
+				}
+			}
+		}
+	}
+	if found == 0 {
+		t.Fatalf("found no app.Err* sentinels to check")
+	}
+}
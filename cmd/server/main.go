@@ -1,28 +1,91 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"twentyfive/internal/app"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	var (
-		port     = flag.Int("port", 8080, "port to listen on")
-		dataFile = flag.String("data-file", filepath.Join("data", "board.json"), "path to board data file")
+		port       = flag.Int("port", 8080, "port to listen on")
+		dataFile   = flag.String("data-file", filepath.Join("data", "board.json"), "path to board data file; a {date} token rolls to a new file each day")
+		resetEmpty = flag.Bool("reset-empty", false, "reset the board to empty instead of the demo seed")
+		maxNotes   = flag.Int("max-notes", 0, "maximum task notes length in characters (0 for no limit)")
+		trimNotes  = flag.Bool("truncate-notes", false, "truncate notes over -max-notes instead of rejecting them")
+		debugBody  = flag.Bool("debug-body-logging", false, "log API request bodies at debug level")
+		schemes    = flag.String("link-schemes", "http,https", "comma-separated URL schemes allowed in task links")
+		timezone   = flag.String("timezone", "", "IANA timezone used for calendar-day queries (defaults to local time)")
+		lenient    = flag.Bool("lenient-json", false, "ignore unknown fields in request bodies instead of rejecting them")
+		restorePos = flag.Bool("restore-category-position", false, "return restored categories to their previous board slot instead of the end")
+		destroy    = flag.Bool("allow-destructive", false, "enable DELETE /api/v1/board, which wipes the board data")
+		fileAdmin  = flag.Bool("allow-file-admin", false, "enable the admin endpoints that clone, open, restore and reload boards inside the data directory")
+		heartbeat  = flag.Duration("event-heartbeat", app.DefaultHeartbeatInterval, "interval between keep-alive pings on the board event stream")
+		hookTries  = flag.Int("webhook-max-attempts", 5, "delivery attempts per webhook event before it is dropped")
+		maxFile    = flag.Int64("max-file-size", 0, "maximum size of the board data file in bytes; writes that would exceed it are refused (0 for no limit)")
+		webhooks   webhookFlag
 	)
+	flag.Var(&webhooks, "webhook", "URL to POST board events to, repeatable; a #fragment is used as the signing secret and not sent")
 	flag.Parse()
 
-	store, err := app.NewStore(*dataFile)
+	location := time.Local
+	if *timezone != "" {
+		loc, err := time.LoadLocation(*timezone)
+		if err != nil {
+			log.Fatalf("load timezone: %v", err)
+		}
+		location = loc
+	}
+
+	store, err := app.NewStore(*dataFile,
+		app.WithEmptyReset(*resetEmpty),
+		app.WithNotesLimit(*maxNotes, *trimNotes),
+		app.WithLocation(location),
+		app.WithLinkSchemes(strings.Split(*schemes, ",")...),
+		app.WithRestorePosition(*restorePos),
+		app.WithMaxFileSize(*maxFile),
+	)
 	if err != nil {
 		log.Fatalf("initialize store: %v", err)
 	}
+	if strings.Contains(*dataFile, app.DateToken) {
+		go rollDaily(store)
+	}
 
-	server := app.NewServer(store)
+	serverOpts := []app.ServerOption{app.WithHeartbeat(*heartbeat)}
+	if len(webhooks) > 0 {
+		dispatcher := app.NewWebhookDispatcher(webhooks, app.WebhookConfig{MaxAttempts: *hookTries})
+		go dispatcher.Run(context.Background(), store)
+		serverOpts = append(serverOpts, app.WithWebhooks(dispatcher))
+	}
+	if *lenient {
+		serverOpts = append(serverOpts, app.WithLenientJSON())
+	}
+	if *destroy {
+		serverOpts = append(serverOpts, app.WithDestructive())
+	}
+	if *fileAdmin {
+		serverOpts = append(serverOpts, app.WithFileAdmin())
+	}
+	if *debugBody {
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		serverOpts = append(serverOpts, app.WithAPIMiddleware(app.BodyLoggingMiddleware(logger, 2048)))
+	}
+	server := app.NewServer(store, serverOpts...)
 
 	addr := fmt.Sprintf(":%d", *port)
 	log.Printf("TwentyFive backend listening on %s", addr)
@@ -30,3 +93,38 @@ func main() {
 		log.Fatalf("serve: %v", err)
 	}
 }
+
+// rollDaily checks once a minute whether the day has changed so an idle
+// server still starts the new day's file shortly after midnight.
+func rollDaily(store *app.Store) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		if rolled, err := store.Roll(); err != nil {
+			log.Printf("roll data file: %v", err)
+		} else if rolled {
+			log.Printf("rolled board to %s", store.Path())
+		}
+	}
+}
+
+// webhookFlag collects -webhook endpoints.
+type webhookFlag []app.WebhookEndpoint
+
+func (f *webhookFlag) String() string {
+	return fmt.Sprint(len(*f), " webhooks")
+}
+
+func (f *webhookFlag) Set(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook %q must be an http or https URL", value)
+	}
+	secret := u.Fragment
+	u.Fragment = ""
+	*f = append(*f, app.WebhookEndpoint{URL: u.String(), Secret: secret})
+	return nil
+}
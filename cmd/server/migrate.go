@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"twentyfive/internal/app"
+)
+
+// runMigrate implements the migrate subcommand and returns the exit status.
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	var (
+		from  = fs.String("from", "", "source board, as file:path")
+		to    = fs.String("to", "", "destination board, as file:path")
+		force = fs.Bool("force", false, "replace a destination that already holds data")
+	)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *from == "" || *to == "" {
+		fmt.Fprintln(os.Stderr, "migrate: -from and -to are required")
+		fs.Usage()
+		return 2
+	}
+
+	summary, err := app.MigrateBoard(*from, *to, *force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+	fmt.Printf("migrated %s to %s\n", *from, *to)
+	fmt.Printf("  categories: %d active, %d backburnered, %d archived\n", summary.Categories, summary.CategoryBackburner, summary.CategoryArchives)
+	fmt.Printf("  tasks: %d (%d backburner, %d archived, %d inbox)\n", summary.Tasks, summary.Backburner, summary.Archives, summary.Inbox)
+	fmt.Printf("  audit events: %d\n", summary.AuditEvents)
+	return 0
+}
@@ -0,0 +1,193 @@
+package app
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	BundleSchemaVersion = 1
+	bundleManifestName  = "manifest.json"
+	bundleBoardName     = "board.json"
+
+	// maxBundleEntrySize bounds how much of a single bundle entry is read
+	// into memory during restore.
+	maxBundleEntrySize = 32 << 20
+)
+
+// BundleManifest describes the contents of an export bundle.
+type BundleManifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	Files         []BundleFile `json:"files"`
+}
+
+type BundleFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// WriteBundle streams a tar.gz of every persisted artifact, followed by a
+// manifest recording each file's checksum.
+func (s *Store) WriteBundle(w io.Writer) error {
+	s.mu.RLock()
+	board := s.state.Clone()
+	s.mu.RUnlock()
+	normalizeBoardState(&board)
+	data, err := encodeBoardFile(board)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest := BundleManifest{SchemaVersion: BundleSchemaVersion, CreatedAt: now}
+	sum := sha256.Sum256(data)
+	manifest.Files = append(manifest.Files, BundleFile{
+		Name:   bundleBoardName,
+		Size:   int64(len(data)),
+		SHA256: hex.EncodeToString(sum[:]),
+	})
+	if err := writeTarEntry(tw, bundleBoardName, data, now); err != nil {
+		return err
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+	if err := writeTarEntry(tw, bundleManifestName, manifestData, now); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("close gzip: %w", err)
+	}
+	return nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write %s header: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// RestoreBundle unpacks a bundle produced by WriteBundle into dir, a
+// subdirectory of the data directory that must be empty or absent, and
+// returns the directory written. Checksums, the schema version and the board
+// itself are verified before anything is written.
+func (s *Store) RestoreBundle(r io.Reader, dir string) (string, error) {
+	dir, err := s.dataFilePath(dir)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("read target dir: %w", err)
+	}
+	if len(entries) > 0 {
+		return "", fmt.Errorf("%w: target directory %s is not empty", ErrInvalidRequest, dir)
+	}
+	if err := restoreBundle(r, dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func restoreBundle(r io.Reader, dir string) error {
+	files, err := readBundle(r)
+	if err != nil {
+		return err
+	}
+	manifestData, ok := files[bundleManifestName]
+	if !ok {
+		return fmt.Errorf("%w: bundle missing %s", ErrInvalidRequest, bundleManifestName)
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return fmt.Errorf("%w: decode manifest: %v", ErrInvalidRequest, err)
+	}
+	if manifest.SchemaVersion != BundleSchemaVersion {
+		return fmt.Errorf("%w: unsupported bundle schema version %d", ErrInvalidRequest, manifest.SchemaVersion)
+	}
+	for _, f := range manifest.Files {
+		data, ok := files[f.Name]
+		if !ok {
+			return fmt.Errorf("%w: bundle missing %s", ErrInvalidRequest, f.Name)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != f.SHA256 || int64(len(data)) != f.Size {
+			return fmt.Errorf("%w: checksum mismatch for %s", ErrInvalidRequest, f.Name)
+		}
+	}
+
+	boardData, ok := files[bundleBoardName]
+	if !ok {
+		return fmt.Errorf("%w: bundle missing %s", ErrInvalidRequest, bundleBoardName)
+	}
+	board, err := decodeBoardDocument(boardData)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create target dir: %w", err)
+	}
+	return writeBoardFile(filepath.Join(dir, bundleBoardName), board)
+}
+
+func readBundle(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: open gzip: %v", ErrInvalidRequest, err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: read tar: %v", ErrInvalidRequest, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxBundleEntrySize {
+			return nil, fmt.Errorf("%w: bundle entry %s too large", ErrInvalidRequest, hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleEntrySize))
+		if err != nil {
+			return nil, fmt.Errorf("%w: read %s: %v", ErrInvalidRequest, hdr.Name, err)
+		}
+		files[filepath.Base(hdr.Name)] = data
+	}
+	return files, nil
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	var buf bytes.Buffer
+	if err := store.WriteBundle(&buf); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+
+	dir, err := store.RestoreBundle(bytes.NewReader(buf.Bytes()), "restored")
+	if err != nil {
+		t.Fatalf("restore bundle: %v", err)
+	}
+	if want := filepath.Join(store.DataDir(), "restored"); dir != want {
+		t.Fatalf("expected the bundle restored to %s, got %s", want, dir)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "board.json"))
+	if err != nil {
+		t.Fatalf("read restored board: %v", err)
+	}
+	board := store.state.Clone()
+	normalizeBoardState(&board)
+	encoded, err := encodeBoardFile(board)
+	if err != nil {
+		t.Fatalf("encode board: %v", err)
+	}
+	if string(raw) != string(encoded) {
+		t.Fatalf("expected the bundled board in the data file encoding")
+	}
+	restored, err := NewStore(filepath.Join(dir, "board.json"))
+	if err != nil {
+		t.Fatalf("open restored board: %v", err)
+	}
+	if got := restored.GetState().Categories[0].ID; got != "active" {
+		t.Fatalf("expected restored board, got first category %q", got)
+	}
+
+	if _, err := store.RestoreBundle(bytes.NewReader(buf.Bytes()), dir); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected non-empty directory to be rejected, got %v", err)
+	}
+	outside := filepath.Join(t.TempDir(), "restored")
+	for _, target := range []string{outside, "../restored", "", "."} {
+		if _, err := store.RestoreBundle(bytes.NewReader(buf.Bytes()), target); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%q: expected ErrInvalidRequest, got %v", target, err)
+		}
+	}
+	if _, err := os.Stat(outside); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected nothing restored outside the data dir")
+	}
+}
+
+func TestImportBundleEndpoint(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	var buf bytes.Buffer
+	if err := store.WriteBundle(&buf); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+
+	rec := doRequest(t, NewServer(store), http.MethodPost, "/admin/import/bundle?dir=restored", buf.String())
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without -allow-file-admin, got %d", rec.Code)
+	}
+	rec = doRequest(t, NewServer(store, WithFileAdmin()), http.MethodPost, "/admin/import/bundle?dir=restored", buf.String())
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, err := os.Stat(filepath.Join(store.DataDir(), "restored", "board.json")); err != nil {
+		t.Fatalf("expected the board restored in the data dir: %v", err)
+	}
+}
+
+func TestRestoreBundleRejectsChecksumMismatch(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	var buf bytes.Buffer
+	if err := store.WriteBundle(&buf); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+
+	// rewrite the bundle with a tampered board but the original manifest
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("open bundle: %v", err)
+	}
+	var out bytes.Buffer
+	gzw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gzw)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read bundle: %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		if hdr.Name == "board.json" {
+			data = bytes.Replace(data, []byte("Active"), []byte("Evil!!"), 1)
+		}
+		hdr.Size = int64(len(data))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		tw.Write(data)
+	}
+	tw.Close()
+	gzw.Close()
+
+	dir := filepath.Join(store.DataDir(), "restored")
+	if _, err := store.RestoreBundle(&out, dir); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected checksum mismatch to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected nothing written on failed restore")
+	}
+}
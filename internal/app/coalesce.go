@@ -0,0 +1,35 @@
+package app
+
+// boardFlight is one in-progress GetStateSF snapshot that concurrent callers
+// wait on instead of taking their own.
+type boardFlight struct {
+	done  chan struct{}
+	board BoardState
+}
+
+// GetStateSF is GetState with concurrent calls coalesced: callers that arrive
+// while a snapshot is being taken share its result rather than cloning the
+// board again. The returned board may be shared with other callers and must
+// be treated as read-only; clone it before changing anything.
+//
+// This does the job of golang.org/x/sync/singleflight for the single "board"
+// key without taking on the dependency.
+func (s *Store) GetStateSF() (BoardState, error) {
+	s.flightMu.Lock()
+	if f := s.flight; f != nil {
+		s.flightMu.Unlock()
+		<-f.done
+		return f.board, nil
+	}
+	f := &boardFlight{done: make(chan struct{})}
+	s.flight = f
+	s.flightMu.Unlock()
+
+	f.board = s.GetState()
+
+	s.flightMu.Lock()
+	s.flight = nil
+	s.flightMu.Unlock()
+	close(f.done)
+	return f.board, nil
+}
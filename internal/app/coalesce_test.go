@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestGetStateSFMatchesGetState(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	var wg sync.WaitGroup
+	boards := make([]BoardState, 16)
+	for i := range boards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			board, err := store.GetStateSF()
+			if err != nil {
+				t.Errorf("get state: %v", err)
+			}
+			boards[i] = board
+		}(i)
+	}
+	wg.Wait()
+	want := categoryIDs(store.GetState().Categories)
+	for i, board := range boards {
+		if got := categoryIDs(board.Categories); got != want {
+			t.Fatalf("caller %d saw %s, want %s", i, got, want)
+		}
+	}
+
+	// a later call sees later writes rather than a cached snapshot
+	if _, err := store.SwapCategories("cat1", "cat2"); err != nil {
+		t.Fatalf("swap: %v", err)
+	}
+	board, _ := store.GetStateSF()
+	if got := categoryIDs(board.Categories); got != "cat2,cat1" {
+		t.Fatalf("expected fresh snapshot after write, got %s", got)
+	}
+}
+
+func largeBoardStore(b *testing.B) *Store {
+	b.Helper()
+	store, err := NewStore(b.TempDir() + "/board.json")
+	if err != nil {
+		b.Fatalf("new store: %v", err)
+	}
+	store.mu.Lock()
+	for i := 0; i < 2000; i++ {
+		store.state.Archives = append(store.state.Archives, Task{ID: fmt.Sprintf("task-%d", i), Name: "Archived", State: "done", Size: 1, Tags: []string{"a", "b"}})
+	}
+	store.mu.Unlock()
+	return store
+}
+
+// BenchmarkConcurrentGetState compares N concurrent readers each cloning the
+// board against the same readers coalesced onto shared snapshots. Run with
+// -benchmem to see the allocation difference.
+func BenchmarkConcurrentGetState(b *testing.B) {
+	const readers = 32
+	store := largeBoardStore(b)
+	run := func(b *testing.B, get func()) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var wg sync.WaitGroup
+			wg.Add(readers)
+			for r := 0; r < readers; r++ {
+				go func() {
+					defer wg.Done()
+					get()
+				}()
+			}
+			wg.Wait()
+		}
+	}
+	b.Run("GetState", func(b *testing.B) {
+		run(b, func() { store.GetState() })
+	})
+	b.Run("GetStateSF", func(b *testing.B) {
+		run(b, func() {
+			if _, err := store.GetStateSF(); err != nil {
+				b.Error(err)
+			}
+		})
+	})
+}
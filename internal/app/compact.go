@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// boardFile is the layout a BoardState is persisted in. It carries the same
+// fields, but a nil task or category list is left out of the file instead of
+// being written as null; an empty, non-nil list is still written as [].
+type boardFile struct {
+	BoardState
+	Categories         *[]categoryFile `json:"categories,omitempty"`
+	Backburner         *[]Task         `json:"backburner,omitempty"`
+	Archives           *[]Task         `json:"archives,omitempty"`
+	Inbox              *[]Task         `json:"inbox,omitempty"`
+	CategoryBackburner *[]categoryFile `json:"categoryBackburner,omitempty"`
+	CategoryArchives   *[]categoryFile `json:"categoryArchives,omitempty"`
+}
+
+type categoryFile struct {
+	Category
+	Tasks *[]Task `json:"tasks,omitempty"`
+}
+
+func newBoardFile(state BoardState) boardFile {
+	tasks := func(list []Task) *[]Task {
+		if list == nil {
+			return nil
+		}
+		return &list
+	}
+	categories := func(list []Category) *[]categoryFile {
+		if list == nil {
+			return nil
+		}
+		out := make([]categoryFile, len(list))
+		for i, cat := range list {
+			out[i] = categoryFile{Category: cat, Tasks: tasks(cat.Tasks)}
+		}
+		return &out
+	}
+	return boardFile{
+		BoardState:         state,
+		Categories:         categories(state.Categories),
+		Backburner:         tasks(state.Backburner),
+		Archives:           tasks(state.Archives),
+		Inbox:              tasks(state.Inbox),
+		CategoryBackburner: categories(state.CategoryBackburner),
+		CategoryArchives:   categories(state.CategoryArchives),
+	}
+}
+
+// compactLists replaces every empty task and category list on the board
+// with nil so the board file leaves them out.
+func compactLists(state *BoardState) {
+	compactTasks := func(list *[]Task) {
+		if len(*list) == 0 {
+			*list = nil
+		}
+	}
+	compactCategories := func(list *[]Category) {
+		if len(*list) == 0 {
+			*list = nil
+		}
+		for i := range *list {
+			compactTasks(&(*list)[i].Tasks)
+		}
+	}
+	compactCategories(&state.Categories)
+	compactCategories(&state.CategoryBackburner)
+	compactCategories(&state.CategoryArchives)
+	compactTasks(&state.Backburner)
+	compactTasks(&state.Archives)
+	compactTasks(&state.Inbox)
+}
+
+// Compact rewrites the board file without its empty task and category
+// lists. The board itself is unchanged, so the revision stays where it is
+// and no event is published. A board that fails validation is left as it
+// was.
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := validateBoardState(s.state); err != nil {
+		return fmt.Errorf("board failed validation, not compacting: %w", err)
+	}
+	prior := s.state
+	next := s.state.Clone()
+	compactLists(&next)
+	if err := validateBoardState(next); err != nil {
+		return fmt.Errorf("compacted board failed validation: %w", err)
+	}
+	s.state = next
+	s.taskIndex, _ = buildTaskIndex(&s.state)
+	if err := s.saveLocked(); err != nil {
+		s.state = prior
+		s.taskIndex, _ = buildTaskIndex(&s.state)
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleCompact(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	if err := s.store.Compact(); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{}, s.store.GetState())
+}
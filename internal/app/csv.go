@@ -0,0 +1,128 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns maps each exportable column to how it is rendered.
+var csvColumns = map[string]func(r SearchResult) string{
+	"id":            func(r SearchResult) string { return r.Task.ID },
+	"name":          func(r SearchResult) string { return r.Task.Name },
+	"description":   func(r SearchResult) string { return r.Task.Description },
+	"notes":         func(r SearchResult) string { return r.Task.Notes },
+	"state":         func(r SearchResult) string { return r.Task.State },
+	"size":          func(r SearchResult) string { return strconv.Itoa(r.Task.Size) },
+	"tags":          func(r SearchResult) string { return strings.Join(r.Task.Tags, ";") },
+	"blockedReason": func(r SearchResult) string { return r.Task.BlockedReason },
+	"delegatedTo":   func(r SearchResult) string { return r.Task.DelegatedTo },
+	"urgent":        func(r SearchResult) string { return strconv.FormatBool(r.Task.Urgent) },
+	"focused":       func(r SearchResult) string { return strconv.FormatBool(r.Task.Focused) },
+	"pool":          func(r SearchResult) string { return r.Pool },
+	"categoryId":    func(r SearchResult) string { return r.CategoryID },
+	"categoryName":  func(r SearchResult) string { return r.CategoryName },
+	"createdAt":     func(r SearchResult) string { return formatCSVTime(r.Task.CreatedAt) },
+	"updatedAt":     func(r SearchResult) string { return formatCSVTime(r.Task.UpdatedAt) },
+	"completedAt": func(r SearchResult) string {
+		if r.Task.CompletedAt == nil {
+			return ""
+		}
+		return formatCSVTime(*r.Task.CompletedAt)
+	},
+	"dueDate": func(r SearchResult) string {
+		if r.Task.DueDate == nil {
+			return ""
+		}
+		return formatCSVTime(*r.Task.DueDate)
+	},
+}
+
+// DefaultCSVColumns is used when an export does not choose its columns.
+var DefaultCSVColumns = []string{"id", "name", "state", "size", "pool", "categoryId", "tags"}
+
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// FilterTasks returns the tasks matching filter across the active categories,
+// the backburner and the archive, in board order.
+func (s *Store) FilterTasks(filter TaskFilter) ([]SearchResult, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if filter.State != "" {
+		if err := s.state.ValidateTaskState(filter.State); err != nil {
+			return nil, err
+		}
+	}
+	results := []SearchResult{}
+	forEachTask(&s.state, func(task *Task, loc taskLocation) bool {
+		if !filter.Matches(*task, loc, &s.state) {
+			return true
+		}
+		result := SearchResult{Task: task.Clone()}
+		switch loc.Kind {
+		case LocationCategory:
+			cat := s.state.Categories[loc.CategoryIndex]
+			result.Pool, result.CategoryID, result.CategoryName = PoolActive, cat.ID, cat.Name
+		case LocationBackburner:
+			result.Pool = PoolBackburner
+		case LocationArchive:
+			result.Pool = PoolArchive
+		case LocationInbox:
+			result.Pool = PoolInbox
+		}
+		results = append(results, result)
+		return true
+	})
+	return results, nil
+}
+
+// ExportTasksCSV renders the tasks matching filter as CSV with a header row,
+// writing the given columns in order. No columns means DefaultCSVColumns.
+func (s *Store) ExportTasksCSV(filter TaskFilter, columns []string) ([]byte, error) {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	render := make([]func(SearchResult) string, len(columns))
+	for i, col := range columns {
+		fn, ok := csvColumns[col]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidRequest, col)
+		}
+		render[i] = fn
+	}
+	results, err := s.FilterTasks(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	row := make([]string, len(columns))
+	for _, result := range results {
+		for i, fn := range render {
+			row[i] = fn(result)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,301 @@
+package app
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxCSVImportBytes bounds an uploaded CSV file.
+const maxCSVImportBytes = 4 << 20
+
+// CSVMapping names the header of the column holding each task field. Only
+// Name is required; unmapped fields take the board's defaults, and rows
+// without a category land in the inbox.
+type CSVMapping struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Size        string `json:"size,omitempty"`
+	State       string `json:"state,omitempty"`
+	Category    string `json:"category,omitempty"`
+	// Tags are separated by semicolons, as in the CSV export.
+	Tags string `json:"tags,omitempty"`
+}
+
+// CSVRowError reports why one row was not imported. Line is the row's line
+// in the file, counting the header as line 1.
+type CSVRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// CSVImportResult summarises a CSV import. Overflowed lists the lines of
+// rows whose category was full and which went to the backburner instead.
+type CSVImportResult struct {
+	DryRun     bool          `json:"dryRun"`
+	Rows       int           `json:"rows"`
+	Imported   int           `json:"imported"`
+	Overflowed []int         `json:"overflowed"`
+	Errors     []CSVRowError `json:"errors"`
+}
+
+type csvRow struct {
+	line     int
+	category string
+	task     Task
+}
+
+// ImportTasksCSV creates a task per CSV row. Each row is checked against the
+// same rules as a created task; rows that fail are reported by line and the
+// rest are still imported. With dryRun the board is left untouched and the
+// result reports what an import would do.
+func (s *Store) ImportTasksCSV(data io.Reader, mapping CSVMapping, dryRun bool) (CSVImportResult, BoardState, error) {
+	rows, result, err := parseCSVRows(data, mapping)
+	if err != nil {
+		return CSVImportResult{}, BoardState{}, err
+	}
+	result.DryRun = dryRun
+
+	if dryRun {
+		s.mu.RLock()
+		next := s.state.Clone()
+		s.mu.RUnlock()
+		s.placeCSVRows(&next, rows, &result)
+		return result, s.GetState(), nil
+	}
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		s.placeCSVRows(state, rows, &result)
+		return nil
+	})
+	if err != nil {
+		return CSVImportResult{}, BoardState{}, err
+	}
+	return result, updatedState, nil
+}
+
+// parseCSVRows reads the header and turns each row into a candidate task.
+// Only a missing header or an unknown mapped column fails the whole file.
+func parseCSVRows(data io.Reader, mapping CSVMapping) ([]csvRow, CSVImportResult, error) {
+	result := CSVImportResult{Overflowed: []int{}, Errors: []CSVRowError{}}
+	if strings.TrimSpace(mapping.Name) == "" {
+		return nil, result, fmt.Errorf("%w: a name column mapping is required", ErrInvalidRequest)
+	}
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, result, fmt.Errorf("%w: csv has no header row", ErrInvalidRequest)
+		}
+		return nil, result, fmt.Errorf("%w: csv header: %v", ErrInvalidRequest, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	column := func(field, name string) (int, error) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return -1, nil
+		}
+		i, ok := columns[name]
+		if !ok {
+			return -1, fmt.Errorf("%w: %s column %q is not in the csv header", ErrInvalidRequest, field, name)
+		}
+		return i, nil
+	}
+	var idx [6]int
+	for i, m := range []struct{ field, name string }{
+		{"name", mapping.Name},
+		{"description", mapping.Description},
+		{"size", mapping.Size},
+		{"state", mapping.State},
+		{"category", mapping.Category},
+		{"tags", mapping.Tags},
+	} {
+		if idx[i], err = column(m.field, m.name); err != nil {
+			return nil, result, err
+		}
+	}
+	cell := func(record []string, i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := []csvRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Rows++
+			result.Errors = append(result.Errors, CSVRowError{Line: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, result, err
+		}
+		line, _ := reader.FieldPos(0)
+		result.Rows++
+		row := csvRow{line: line, category: cell(record, idx[4])}
+		row.task = Task{
+			Name:        cell(record, idx[0]),
+			Description: cell(record, idx[1]),
+			State:       cell(record, idx[3]),
+			Tags:        trimTags(strings.Split(cell(record, idx[5]), ";")),
+		}
+		if len(row.task.Tags) == 0 {
+			row.task.Tags = nil
+		}
+		if size := cell(record, idx[2]); size != "" {
+			n, err := strconv.Atoi(size)
+			if err != nil {
+				result.Errors = append(result.Errors, CSVRowError{Line: line, Error: fmt.Sprintf("size %q is not a number", size)})
+				continue
+			}
+			row.task.Size = n
+		}
+		if row.task.Name == "" {
+			result.Errors = append(result.Errors, CSVRowError{Line: line, Error: "name is empty"})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, result, nil
+}
+
+// placeCSVRows adds each row's task to state, recording rows that fail
+// instead of stopping. A failed row leaves state as it was.
+func (s *Store) placeCSVRows(state *BoardState, rows []csvRow, result *CSVImportResult) {
+	now := s.now()
+	rowErr := func(row csvRow, err error) {
+		result.Errors = append(result.Errors, CSVRowError{Line: row.line, Error: err.Error()})
+	}
+	for _, row := range rows {
+		task := row.task
+		task.ID = NewID()
+		if task.State == "" {
+			task.State = state.StateList()[0].ID
+		}
+		if err := state.ValidateTaskState(task.State); err != nil {
+			rowErr(row, err)
+			continue
+		}
+		if err := checkStateDetails(&task, ""); err != nil {
+			rowErr(row, err)
+			continue
+		}
+		if task.Size == 0 {
+			task.Size = state.Sizes()[0]
+		}
+		size, err := state.NormalizeSize(task.Size)
+		if err != nil {
+			rowErr(row, err)
+			continue
+		}
+		task.Size = size
+		if err := s.checkLinks(task.Links); err != nil {
+			rowErr(row, err)
+			continue
+		}
+		task.CreatedAt, task.UpdatedAt = now, now
+		stampCompletion(&task, "", now)
+
+		if row.category == "" {
+			recordEvent(state, &task, AuditEvent{At: now, Action: "import"})
+			state.Inbox = append(state.Inbox, task)
+			result.Imported++
+			continue
+		}
+		id, err := resolveCategoryName(state.Categories, row.category)
+		if err != nil {
+			rowErr(row, err)
+			continue
+		}
+		cat := &state.Categories[findCategoryIndex(state.Categories, id)]
+		if cat.Locked {
+			rowErr(row, errCategoryLocked)
+			continue
+		}
+		recordEvent(state, &task, AuditEvent{At: now, Action: "import"})
+		sizeBefore := categorySize(*cat)
+		cat.Tasks = append(cat.Tasks, task)
+		if state.checkPlacement(*cat, sizeBefore, false) != nil {
+			cat.Tasks = cat.Tasks[:len(cat.Tasks)-1]
+			task.SourceID, task.Source = cat.ID, cat.Name
+			state.Backburner = append(state.Backburner, task)
+			result.Overflowed = append(result.Overflowed, row.line)
+		}
+		result.Imported++
+	}
+	// parse errors were collected first; report everything in file order
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Line < result.Errors[j].Line })
+}
+
+// handleImportCSV takes the CSV either as a multipart upload in a "file"
+// field or as a raw text/csv body. The column mapping and dryRun come from
+// form fields or the query string.
+func (s *Server) handleImportCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxCSVImportBytes)
+	var data io.Reader = r.Body
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxCSVImportBytes); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("file field: %w", err))
+			return
+		}
+		defer file.Close()
+		data = file
+	}
+	mapping := CSVMapping{
+		Name:        r.FormValue("name"),
+		Description: r.FormValue("description"),
+		Size:        r.FormValue("size"),
+		State:       r.FormValue("state"),
+		Category:    r.FormValue("category"),
+		Tags:        r.FormValue("tags"),
+	}
+	dryRun := false
+	if raw := r.FormValue("dryRun"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid dryRun %q", raw))
+			return
+		}
+		dryRun = parsed
+	}
+
+	result, board, err := s.store.ImportTasksCSV(data, mapping, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"summary": result,
+	}, board)
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExportTasksCSVColumns(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	filter := TaskFilter{State: "doing"}
+
+	data, err := store.ExportTasksCSV(filter, []string{"state", "id", "name"})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if strings.Join(rows[0], ",") != "state,id,name" {
+		t.Fatalf("expected header in requested order, got %v", rows[0])
+	}
+	for _, row := range rows {
+		if len(row) != 3 {
+			t.Fatalf("expected exactly three columns, got %v", row)
+		}
+	}
+	matched, err := store.FilterTasks(filter)
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if len(rows)-1 != len(matched) || len(matched) != 3 {
+		t.Fatalf("expected one row per matching task (%d), got %d", len(matched), len(rows)-1)
+	}
+	if strings.Join(rows[1], ",") != "doing,t1,One" {
+		t.Fatalf("unexpected first row %v", rows[1])
+	}
+}
+
+func TestExportTasksCSVRejectsUnknownColumn(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, err := store.ExportTasksCSV(TaskFilter{}, []string{"id", "priority"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if _, err := store.ExportTasksCSV(TaskFilter{State: "someday"}, nil); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState, got %v", err)
+	}
+}
+
+func TestExportTasksCSVEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	rec := doRequest(t, srv, http.MethodGet, "/api/v1/tasks/export.csv?state=todo&columns=id,name,state", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="tasks.csv"` {
+		t.Fatalf("unexpected Content-Disposition %q", got)
+	}
+	if got := rec.Body.String(); got != "id,name,state\nt3,Three,todo\n" {
+		t.Fatalf("unexpected body %q", got)
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/tasks/export.csv?columns=secret", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown column, got %d", rec.Code)
+	}
+}
+
+const importCSV = `Title,Points,Status,List,Labels
+Write intro,1,todo,alpha,docs;draft
+,1,todo,Alpha,
+Big one,2,todo,Alpha,
+Odd size,x,todo,Beta,
+Wrong state,1,nope,Beta,
+Unsorted,,,,
+Missing list,1,todo,Gamma,
+`
+
+func TestImportTasksCSV(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	mapping := CSVMapping{Name: "Title", Size: "Points", State: "Status", Category: "List", Tags: "Labels"}
+
+	dry, board, err := store.ImportTasksCSV(strings.NewReader(importCSV), mapping, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !dry.DryRun || dry.Imported != 3 || board.Revision != store.GetState().Revision || len(store.GetState().Inbox) != 0 {
+		t.Fatalf("expected a dry run to report without writing, got %+v", dry)
+	}
+
+	result, board, err := store.ImportTasksCSV(strings.NewReader(importCSV), mapping, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Rows != 7 || result.Imported != 3 {
+		t.Fatalf("expected 3 of 7 rows imported, got %+v", result)
+	}
+	lines := []int{}
+	for _, rowErr := range result.Errors {
+		lines = append(lines, rowErr.Line)
+	}
+	if fmt.Sprint(lines) != "[3 5 6 8]" {
+		t.Fatalf("expected errors on lines 3, 5, 6 and 8, got %+v", result.Errors)
+	}
+	// Alpha holds 4 of 5 points after the first row, so line 4 overflows
+	if fmt.Sprint(result.Overflowed) != "[4]" {
+		t.Fatalf("expected line 4 overflowed, got %v", result.Overflowed)
+	}
+
+	alpha := board.Categories[0].Tasks
+	if last := alpha[len(alpha)-1]; last.Name != "Write intro" || strings.Join(last.Tags, ",") != "docs,draft" {
+		t.Fatalf("expected Write intro in Alpha with tags, got %+v", last)
+	}
+	if len(board.Backburner) != 1 || board.Backburner[0].Name != "Big one" || board.Backburner[0].SourceID != "cat1" {
+		t.Fatalf("expected Big one parked from cat1, got %+v", board.Backburner)
+	}
+	if len(board.Inbox) != 1 || board.Inbox[0].Name != "Unsorted" || board.Inbox[0].State != "todo" || board.Inbox[0].Size != 1 {
+		t.Fatalf("expected Unsorted in the inbox with defaults, got %+v", board.Inbox)
+	}
+}
+
+func TestImportTasksCSVEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/import/csv?name=Title&category=List&includeBoard=false", "Title,List\nFrom body,Beta\n")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Summary CSVImportResult `json:"summary"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Summary.Imported != 1 {
+		t.Fatalf("expected one row imported, got %+v", resp.Summary)
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("name", "Title")
+	_ = form.WriteField("dryRun", "true")
+	part, _ := form.CreateFormFile("file", "tasks.csv")
+	_, _ = part.Write([]byte("Title\nUploaded\n"))
+	form.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/import/csv", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for upload, got %d: %s", rec.Code, rec.Body.String())
+	}
+	decodeBody(t, rec, &resp)
+	if !resp.Summary.DryRun || resp.Summary.Imported != 1 {
+		t.Fatalf("expected a dry run of one row, got %+v", resp.Summary)
+	}
+
+	if rec := doRequest(t, srv, http.MethodPost, "/api/v1/import/csv?name=Nope", "Title\nx\n"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown column, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// dependencyGraph maps every task on the board to the IDs in its BlockedBy,
+// keeping board order in ids so cycles are reported deterministically.
+func dependencyGraph(board *BoardState) (ids []string, blockers map[string][]string) {
+	blockers = map[string][]string{}
+	forEachPoolTask(board, func(task *Task, _ bool) {
+		ids = append(ids, task.ID)
+		blockers[task.ID] = task.BlockedBy
+	})
+	return ids, blockers
+}
+
+// findCycles walks the graph depth first and returns one cycle per back
+// edge, each listed from the first task reached to the task that closes it.
+// Blockers that are not tasks on the board are ignored.
+func findCycles(ids []string, blockers map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	cycles := [][]string{}
+	mark := map[string]int{}
+	path := []string{}
+	var visit func(id string)
+	visit = func(id string) {
+		mark[id] = onPath
+		path = append(path, id)
+		for _, next := range blockers[id] {
+			if _, ok := blockers[next]; !ok {
+				continue
+			}
+			switch mark[next] {
+			case unvisited:
+				visit(next)
+			case onPath:
+				start := len(path) - 1
+				for path[start] != next {
+					start--
+				}
+				cycles = append(cycles, append([]string{}, path[start:]...))
+			}
+		}
+		path = path[:len(path)-1]
+		mark[id] = done
+	}
+	for _, id := range ids {
+		if mark[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// checkNoCycle rejects a dependency graph in which id, following its
+// blockers, leads back to itself. Cycles elsewhere on the board are left for
+// FindCircularDependencies to report.
+func checkNoCycle(blockers map[string][]string, id string) error {
+	// breadth first from id, remembering how each task was reached so the
+	// cycle can be spelled out
+	via := map[string]string{}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range blockers[current] {
+			if _, ok := blockers[next]; !ok {
+				continue
+			}
+			if next == id {
+				cycle := []string{}
+				for step := current; step != id; step = via[step] {
+					cycle = append(cycle, step)
+				}
+				cycle = append(cycle, id)
+				slices.Reverse(cycle)
+				return fmt.Errorf("%w: blockedBy would create a cycle: %s -> %s", ErrInvalidRequest, strings.Join(cycle, " -> "), id)
+			}
+			if _, seen := via[next]; !seen {
+				via[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// checkBoardCycle is checkNoCycle for the board as it stands.
+func checkBoardCycle(board *BoardState, id string) error {
+	_, blockers := dependencyGraph(board)
+	return checkNoCycle(blockers, id)
+}
+
+// trimBlockers trims blocker IDs and rejects blanks and repeats.
+func trimBlockers(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, fmt.Errorf("%w: blockedBy contains an empty id", ErrInvalidRequest)
+		}
+		if hasTag(out, id) {
+			return nil, fmt.Errorf("%w: blockedBy lists %s more than once", ErrInvalidRequest, id)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// FindCircularDependencies reports every cycle in the tasks' BlockedBy
+// links, each as the task IDs in blocking order. It is empty, not nil, when
+// there are none.
+func (s *Store) FindCircularDependencies() ([][]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids, blockers := dependencyGraph(&s.state)
+	return findCycles(ids, blockers), nil
+}
+
+func (s *Server) handleBoardCycles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	cycles, err := s.store.FindCircularDependencies()
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"cycles": cycles,
+	})
+}
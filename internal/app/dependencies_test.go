@@ -0,0 +1,98 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const cycleBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"self","name":"Self","description":"","notes":"","state":"todo","size":1,"blockedBy":["self"]},
+			{"id":"a","name":"A","description":"","notes":"","state":"todo","size":1,"blockedBy":["b"]},
+			{"id":"b","name":"B","description":"","notes":"","state":"todo","size":1,"blockedBy":["a","gone"]}
+		]}
+	],
+	"backburner": [
+		{"id":"x","name":"X","description":"","notes":"","state":"todo","size":1,"blockedBy":["y"]},
+		{"id":"y","name":"Y","description":"","notes":"","state":"todo","size":1,"blockedBy":["z"]}
+	],
+	"archives": [
+		{"id":"z","name":"Z","description":"","notes":"","state":"done","size":1,"blockedBy":["x"]},
+		{"id":"free","name":"Free","description":"","notes":"","state":"done","size":1,"blockedBy":["a"]}
+	],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestFindCircularDependencies(t *testing.T) {
+	cycles, err := newTestStore(t, cycleBoard).FindCircularDependencies()
+	if err != nil {
+		t.Fatalf("find cycles: %v", err)
+	}
+	if got := fmt.Sprint(cycles); got != "[[self] [a b] [x y z]]" {
+		t.Fatalf("expected self, two and three node cycles, got %s", got)
+	}
+
+	cycles, err = newTestStore(t, bulkBoard).FindCircularDependencies()
+	if err != nil || cycles == nil || len(cycles) != 0 {
+		t.Fatalf("expected an empty non-nil slice, got %#v %v", cycles, err)
+	}
+}
+
+func TestBlockedByRejectsCycles(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	self := []string{"t1"}
+	if _, _, err := store.PatchTask("t1", TaskPatch{BlockedBy: &self}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a self reference rejected, got %v", err)
+	}
+	blockers := []string{"t2"}
+	if _, _, err := store.PatchTask("t1", TaskPatch{BlockedBy: &blockers}); err != nil {
+		t.Fatalf("t1 blocked by t2: %v", err)
+	}
+	blockers = []string{"t3"}
+	if _, _, err := store.PatchTask("t2", TaskPatch{BlockedBy: &blockers}); err != nil {
+		t.Fatalf("t2 blocked by t3: %v", err)
+	}
+	blockers = []string{"t1"}
+	_, _, err := store.PatchTask("t3", TaskPatch{BlockedBy: &blockers})
+	if !errors.Is(err, ErrInvalidRequest) || err.Error() != "invalid request: blockedBy would create a cycle: t3 -> t1 -> t2 -> t3" {
+		t.Fatalf("expected the closing link rejected, got %v", err)
+	}
+	board := store.GetState()
+	if task, _, _ := findTask(&board, "t3", nil); len(task.BlockedBy) != 0 {
+		t.Fatalf("expected t3 left alone, got %v", task.BlockedBy)
+	}
+
+	_, _, err = store.CreateTask(CreateTaskRequest{Location: LocationBackburner, Task: Task{ID: "new", Name: "New", State: "todo", Size: 1, BlockedBy: []string{"new"}}})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a self-blocked create rejected, got %v", err)
+	}
+	created, _, err := store.CreateTask(CreateTaskRequest{Location: LocationBackburner, Task: Task{Name: "New", State: "todo", Size: 1, BlockedBy: []string{" t1 "}}})
+	if err != nil || len(created.BlockedBy) != 1 || created.BlockedBy[0] != "t1" {
+		t.Fatalf("expected create blocked by t1, got %+v %v", created.BlockedBy, err)
+	}
+}
+
+func TestBoardCyclesEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, cycleBoard))
+	rec := doRequest(t, srv, http.MethodGet, "/api/v1/board/cycles", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Cycles [][]string `json:"cycles"`
+	}
+	decodeBody(t, rec, &resp)
+	if len(resp.Cycles) != 3 {
+		t.Fatalf("expected 3 cycles, got %v", resp.Cycles)
+	}
+
+	rec = doRequest(t, NewServer(newTestStore(t, bulkBoard)), http.MethodGet, "/api/v1/board/cycles", "")
+	if rec.Body.String() != "{\"cycles\":[]}\n" {
+		t.Fatalf("expected an empty list, got %s", rec.Body.String())
+	}
+}
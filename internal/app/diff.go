@@ -0,0 +1,114 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BoardDiff lists the tasks touched since a point in time, reconstructed
+// from the board audit log.
+type BoardDiff struct {
+	Since    time.Time `json:"since"`
+	Revision int64     `json:"revision"`
+	// Changed holds the current copy of every task created, updated or moved
+	// since Since that still exists, in the order it was first touched.
+	Changed []Task `json:"changed"`
+	// Deleted lists IDs of tasks removed since Since.
+	Deleted []string     `json:"deleted"`
+	Events  []AuditEvent `json:"events"`
+}
+
+// GetBoardDiff reports the tasks changed at or after since. The audit log
+// only reaches back so far, and not every write is logged; a since before
+// its earliest entry or the last unlogged change, or an empty log, returns
+// ErrInvalidRequest and the client must fetch the full board.
+func (s *Store) GetBoardDiff(since time.Time) (BoardDiff, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	log := s.state.AuditLog
+	if len(log) == 0 || since.Before(log[0].At) {
+		return BoardDiff{}, fmt.Errorf("%w: changes since %s are no longer in the log, fetch the full board", ErrInvalidRequest, since.Format(time.RFC3339))
+	}
+	if since.Before(s.unloggedAt) {
+		return BoardDiff{}, fmt.Errorf("%w: changes since %s were not all logged, fetch the full board", ErrInvalidRequest, since.Format(time.RFC3339))
+	}
+	board := s.snapshotLocked()
+	diff := BoardDiff{Since: since, Revision: board.Revision, Changed: []Task{}, Deleted: []string{}, Events: []AuditEvent{}}
+
+	seen := map[string]bool{}
+	order := []string{}
+	for _, ev := range log {
+		// inclusive, so a change stamped at the client's last sync is
+		// repeated rather than missed
+		if ev.At.Before(since) {
+			continue
+		}
+		diff.Events = append(diff.Events, ev)
+		if !seen[ev.TaskID] {
+			seen[ev.TaskID] = true
+			order = append(order, ev.TaskID)
+		}
+	}
+	for _, id := range order {
+		task, _, err := findTask(&board, id, nil)
+		if err != nil {
+			diff.Deleted = append(diff.Deleted, id)
+			continue
+		}
+		diff.Changed = append(diff.Changed, task.Clone())
+	}
+	return diff, nil
+}
+
+// StateDiff lists, by ID, the tasks that differ between two boards.
+type StateDiff struct {
+	Revision int64    `json:"revision"`
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	// Changed holds tasks on both boards that were edited or moved.
+	Changed []string `json:"changed"`
+}
+
+// Diff compares state with next task by task, reporting next's revision.
+// Tasks are listed in next's board order, removed ones in state's.
+func (state BoardState) Diff(next BoardState) StateDiff {
+	diff := StateDiff{Revision: next.Revision, Added: []string{}, Removed: []string{}, Changed: []string{}}
+	// a task is compared with where it sits, but not with its category's
+	// name, so renaming a category does not list every task in it
+	type placed struct {
+		Task       Task
+		Pool       string
+		CategoryID string
+	}
+	before := map[string]placed{}
+	for _, result := range collectSearchResults(&state) {
+		before[result.Task.ID] = placed{result.Task, result.Pool, result.CategoryID}
+	}
+	after := map[string]bool{}
+	for _, result := range collectSearchResults(&next) {
+		after[result.Task.ID] = true
+		prev, ok := before[result.Task.ID]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, result.Task.ID)
+		case !sameJSON(prev, placed{result.Task, result.Pool, result.CategoryID}):
+			diff.Changed = append(diff.Changed, result.Task.ID)
+		}
+	}
+	for _, result := range collectSearchResults(&state) {
+		if !after[result.Task.ID] {
+			diff.Removed = append(diff.Removed, result.Task.ID)
+		}
+	}
+	return diff
+}
+
+// sameJSON reports whether a and b encode alike, so a nil list and an empty
+// one left out by omitempty compare equal.
+func sameJSON(a, b any) bool {
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
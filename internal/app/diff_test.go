@@ -0,0 +1,117 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetBoardDiff(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	store := newTestStore(t, bulkBoard, WithClock(func() time.Time { return now }))
+	start := now
+
+	name := "Renamed"
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Name: &name}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	now = now.Add(time.Minute)
+	since := now
+	now = now.Add(time.Minute)
+	if _, _, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat2", Task: Task{ID: "t5", Name: "Five", State: "todo", Size: 1}}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if _, _, err := store.MoveTask("t2", MoveTaskRequest{Location: LocationBackburner}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationArchive}); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := store.DeleteTask("t3"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	diff, err := store.GetBoardDiff(since)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	changed := []string{}
+	for _, task := range diff.Changed {
+		changed = append(changed, task.ID)
+	}
+	if got := strings.Join(changed, ","); got != "t5,t2" {
+		t.Fatalf("expected t5,t2 changed, got %s", got)
+	}
+	if got := strings.Join(diff.Deleted, ","); got != "t3" {
+		t.Fatalf("expected t3 deleted, got %s", got)
+	}
+	if len(diff.Events) != 4 {
+		t.Fatalf("expected 4 events since, got %d", len(diff.Events))
+	}
+	if diff.Changed[1].Name != "Two" || len(store.GetState().Backburner) != 1 {
+		t.Fatalf("expected current copy of t2")
+	}
+
+	if _, err := store.GetBoardDiff(start.Add(-time.Second)); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest before the log starts, got %v", err)
+	}
+}
+
+func TestBoardDiffStopsAtUnloggedWrites(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	store := newTestStore(t, bulkBoard, WithClock(func() time.Time { return now }))
+
+	now = now.Add(time.Minute)
+	name := "Renamed"
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Name: &name}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	since := now
+	now = now.Add(time.Minute)
+	// urgency changes are not in the audit log
+	if _, _, err := store.SetTaskUrgent("t1", true); err != nil {
+		t.Fatalf("urgent: %v", err)
+	}
+	if _, err := store.GetBoardDiff(since); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest across an unlogged write, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	since = now
+	if _, _, err := store.UpdateTask("t2", TaskPatch{Name: &name}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	diff, err := store.GetBoardDiff(since)
+	if err != nil {
+		t.Fatalf("diff after the unlogged write: %v", err)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].ID != "t2" {
+		t.Fatalf("expected t2 changed, got %+v", diff.Changed)
+	}
+}
+
+func TestBoardDiffEndpoint(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	srv := NewServer(newTestStore(t, bulkBoard, WithClock(func() time.Time { return now })))
+
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/board/diff?since=2025-03-10T09:00:00Z", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 with an empty log, got %d", rec.Code)
+	}
+	if rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks/t1/move", `{"location":"archive"}`); rec.Code != http.StatusOK {
+		t.Fatalf("move: %d %s", rec.Code, rec.Body.String())
+	}
+	rec := doRequest(t, srv, http.MethodGet, "/api/v1/board/diff?since=2025-03-10T09:00:00Z", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"deleted":[]`) || strings.Contains(body, `"changed":[]`) {
+		t.Fatalf("unexpected diff body %s", body)
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/board/diff?since=yesterday", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad timestamp, got %d", rec.Code)
+	}
+}
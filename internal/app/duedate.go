@@ -0,0 +1,175 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DueDateError reports due date text the parser could not read, carrying the
+// text as given so clients can show it back.
+type DueDateError struct {
+	Text string
+}
+
+func (e *DueDateError) Error() string {
+	return fmt.Sprintf("%v: cannot read due date %q", ErrInvalidRequest, e.Text)
+}
+
+func (e *DueDateError) Unwrap() error {
+	return ErrInvalidRequest
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseDueDate resolves due date text against now in loc. It reads ISO dates
+// and RFC 3339 timestamps, "today", "tomorrow", weekday names (the next such
+// day after today), "next week" (the coming Monday), "next friday" (Friday
+// of next week, weeks starting on Monday) and "in 3 days", "in a week" or
+// "in 2 months". A leading "due" is ignored. Any of these may end with a
+// time of day such as "5pm", "5:30 pm", "17:00", "at noon" or "midnight";
+// without one the due date is the start of the day.
+func parseDueDate(text string, now time.Time, loc *time.Location) (time.Time, error) {
+	fail := &DueDateError{Text: text}
+	trimmed := strings.TrimSpace(text)
+	if at, err := time.Parse(time.RFC3339, trimmed); err == nil {
+		return at.In(loc), nil
+	}
+	words := strings.Fields(strings.ToLower(trimmed))
+	if len(words) > 0 && words[0] == "due" {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return time.Time{}, fail
+	}
+
+	hour, minute := 0, 0
+	if n := len(words); n >= 2 && (words[n-1] == "am" || words[n-1] == "pm") {
+		words = append(words[:n-2], words[n-2]+words[n-1])
+	}
+	if h, m, ok := parseClock(words[len(words)-1]); ok {
+		hour, minute = h, m
+		words = words[:len(words)-1]
+		if n := len(words); n > 0 && words[n-1] == "at" {
+			words = words[:n-1]
+		}
+	}
+
+	today := now.In(loc)
+	y, m, d := today.Date()
+	day := func(offset int) (time.Time, error) {
+		return time.Date(y, m, d+offset, hour, minute, 0, 0, loc), nil
+	}
+	// daysUntil counts days from today to the next weekday after today
+	daysUntil := func(wd time.Weekday) int {
+		n := (int(wd) - int(today.Weekday()) + 7) % 7
+		if n == 0 {
+			n = 7
+		}
+		return n
+	}
+	// fromMonday is how far a weekday sits from Monday in a Monday-first week
+	fromMonday := func(wd time.Weekday) int {
+		return (int(wd) + 6) % 7
+	}
+
+	phrase := strings.Join(words, " ")
+	switch {
+	case phrase == "" || phrase == "today":
+		// a time of day on its own means today
+		return day(0)
+	case phrase == "tomorrow":
+		return day(1)
+	case phrase == "next week":
+		return day(7 - fromMonday(today.Weekday()))
+	}
+	if wd, ok := weekdays[phrase]; ok {
+		return day(daysUntil(wd))
+	}
+	if len(words) == 2 && words[0] == "next" {
+		if wd, ok := weekdays[words[1]]; ok {
+			return day(7 - fromMonday(today.Weekday()) + fromMonday(wd))
+		}
+	}
+	if len(words) == 3 && words[0] == "in" {
+		count, err := strconv.Atoi(words[1])
+		switch words[1] {
+		case "a", "an", "one":
+			count, err = 1, nil
+		}
+		if err != nil || count < 0 {
+			return time.Time{}, fail
+		}
+		switch strings.TrimSuffix(words[2], "s") {
+		case "day":
+			return day(count)
+		case "week":
+			return day(7 * count)
+		case "month":
+			return time.Date(y, m+time.Month(count), d, hour, minute, 0, 0, loc), nil
+		}
+		return time.Time{}, fail
+	}
+	if len(words) == 1 {
+		if date, err := time.ParseInLocation("2006-01-02", words[0], loc); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc), nil
+		}
+	}
+	return time.Time{}, fail
+}
+
+// parseClock reads a time of day: "5pm", "5:30pm", "17:00", "noon" or
+// "midnight".
+func parseClock(word string) (hour, minute int, ok bool) {
+	switch word {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+	meridiem := ""
+	if strings.HasSuffix(word, "am") || strings.HasSuffix(word, "pm") {
+		meridiem = word[len(word)-2:]
+		word = word[:len(word)-2]
+	}
+	hours, minutes, hasMinutes := strings.Cut(word, ":")
+	if meridiem == "" && !hasMinutes {
+		return 0, 0, false
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, 0, false
+	}
+	if hasMinutes {
+		if len(minutes) != 2 {
+			return 0, 0, false
+		}
+		if minute, err = strconv.Atoi(minutes); err != nil || minute > 59 {
+			return 0, 0, false
+		}
+	}
+	switch meridiem {
+	case "":
+		if h > 23 {
+			return 0, 0, false
+		}
+	default:
+		if h < 1 || h > 12 {
+			return 0, 0, false
+		}
+		h %= 12
+		if meridiem == "pm" {
+			h += 12
+		}
+	}
+	return h, minute, true
+}
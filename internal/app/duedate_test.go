@@ -0,0 +1,104 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseDueDate(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	cases := []struct {
+		now  string
+		text string
+		want string
+	}{
+		// Saturday 2025-03-08; clocks spring forward early on the 9th
+		{"2025-03-08T15:00:00Z", "tomorrow", "2025-03-09T05:00:00Z"},
+		{"2025-03-08T15:00:00Z", "tomorrow 5pm", "2025-03-09T21:00:00Z"},
+		{"2025-03-08T15:00:00Z", "due tomorrow at 5:30 pm", "2025-03-09T21:30:00Z"},
+		{"2025-03-08T15:00:00Z", "today", "2025-03-08T05:00:00Z"},
+		{"2025-03-08T15:00:00Z", "17:00", "2025-03-08T22:00:00Z"},
+		{"2025-03-08T15:00:00Z", "monday", "2025-03-10T04:00:00Z"},
+		{"2025-03-08T15:00:00Z", "Sat noon", "2025-03-15T16:00:00Z"},
+		{"2025-03-08T15:00:00Z", "next week", "2025-03-10T04:00:00Z"},
+		{"2025-03-08T15:00:00Z", "next friday", "2025-03-14T04:00:00Z"},
+		{"2025-03-08T15:00:00Z", "in 3 days", "2025-03-11T04:00:00Z"},
+		{"2025-03-08T15:00:00Z", "in a week midnight", "2025-03-15T04:00:00Z"},
+		{"2025-03-08T15:00:00Z", "in 2 months", "2025-05-08T04:00:00Z"},
+		{"2025-03-08T15:00:00Z", "2025-04-01", "2025-04-01T04:00:00Z"},
+		{"2025-03-08T15:00:00Z", "2025-04-01T09:00:00Z", "2025-04-01T09:00:00Z"},
+		// Saturday 2025-11-01; clocks fall back early on the 2nd
+		{"2025-11-01T15:00:00Z", "tomorrow noon", "2025-11-02T17:00:00Z"},
+		{"2025-11-01T15:00:00Z", "tomorrow", "2025-11-02T04:00:00Z"},
+		// late evening locally is already the next day in UTC
+		{"2025-03-13T03:00:00Z", "today", "2025-03-12T04:00:00Z"},
+	}
+	for _, tc := range cases {
+		got, err := parseDueDate(tc.text, fixedClock(tc.now)(), ny)
+		if err != nil {
+			t.Errorf("%q at %s: %v", tc.text, tc.now, err)
+			continue
+		}
+		if s := got.UTC().Format(time.RFC3339); s != tc.want {
+			t.Errorf("%q at %s: expected %s, got %s", tc.text, tc.now, tc.want, s)
+		}
+	}
+
+	now := fixedClock("2025-03-08T15:00:00Z")()
+	for _, text := range []string{"", "due", "someday", "in x days", "next", "tomorrow 25pm", "2025-13-01", "friday 5pm please"} {
+		_, err := parseDueDate(text, now, ny)
+		var dueErr *DueDateError
+		if !errors.As(err, &dueErr) || dueErr.Text != text || !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%q: expected a DueDateError, got %v", text, err)
+		}
+	}
+}
+
+func TestPatchTaskDueDateText(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard, WithClock(fixedClock("2025-03-12T15:00:00Z")), WithLocation(time.UTC)))
+
+	rec := doRequest(t, srv, http.MethodPatch, "/api/tasks/t1", `{"dueDateText":"friday 9am"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	decodeBody(t, rec, &resp)
+	if due := resp.Task.DueDate; due == nil || due.Format(time.RFC3339) != "2025-03-14T09:00:00Z" {
+		t.Fatalf("expected t1 due friday 9am, got %v", due)
+	}
+
+	rec = doRequest(t, srv, http.MethodPatch, "/api/tasks/t1", `{"dueDateText":"whenever"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	var bad struct {
+		Text string `json:"text"`
+	}
+	decodeBody(t, rec, &bad)
+	if bad.Text != "whenever" {
+		t.Fatalf("expected the due text echoed back, got %q", bad.Text)
+	}
+
+	rec = doRequest(t, srv, http.MethodPatch, "/api/tasks/t1", `{"dueDate":"2025-04-01T00:00:00Z","dueDateText":"friday"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for both dueDate and dueDateText, got %d", rec.Code)
+	}
+
+	rec = doRequest(t, srv, http.MethodPatch, "/api/tasks/t1", `{"dueDateText":""}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	resp.Task = Task{}
+	decodeBody(t, rec, &resp)
+	if resp.Task.ID != "t1" || resp.Task.DueDate != nil {
+		t.Fatalf("expected an empty dueDateText to clear the date, got %+v", resp.Task)
+	}
+}
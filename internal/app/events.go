@@ -0,0 +1,116 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultHeartbeatInterval is how often the event stream sends a keep-alive
+// comment when no events are flowing.
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// BoardEvent is what subscribers receive after each write.
+type BoardEvent struct {
+	Revision int64 `json:"revision"`
+}
+
+// Subscribe registers for board events. Each write sends an event on the
+// returned channel; a subscriber that falls behind only sees the latest one.
+// Call the returned func to unsubscribe.
+func (s *Store) Subscribe() (<-chan BoardEvent, func()) {
+	ch := make(chan BoardEvent, 1)
+	s.subMu.Lock()
+	if s.subs == nil {
+		s.subs = map[chan BoardEvent]struct{}{}
+	}
+	s.subs[ch] = struct{}{}
+	s.subMu.Unlock()
+	return ch, func() {
+		s.subMu.Lock()
+		delete(s.subs, ch)
+		s.subMu.Unlock()
+	}
+}
+
+// NotifyObservers sends event to every subscriber as if a write had
+// produced it. It is a test and debugging tool for exercising the event
+// stream without touching the board; production code paths must not call it.
+func (s *Store) NotifyObservers(event BoardEvent) {
+	s.publish(event)
+}
+
+// ObserverCount reports how many subscribers are registered, for tests.
+func (s *Store) ObserverCount() int {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	return len(s.subs)
+}
+
+// publish hands event to every subscriber without blocking the writer,
+// replacing any event a slow subscriber has not picked up yet.
+func (s *Store) publish(event BoardEvent) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	for ch := range s.subs {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- event
+	}
+}
+
+// handleBoardEvents streams board revisions as server-sent events, with a
+// comment line every heartbeat interval so proxies keep the connection open.
+func (s *Server) handleBoardEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+	events, unsubscribe := s.store.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := writeRevisionEvent(w, BoardEvent{Revision: s.store.GetState().Revision}); err != nil {
+		logWriteError("board events", err)
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			err = writeRevisionEvent(w, event)
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": ping\n\n")
+		}
+		if err != nil {
+			logWriteError("board events", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func writeRevisionEvent(w http.ResponseWriter, event BoardEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: revision\ndata: %s\n\n", data)
+	return err
+}
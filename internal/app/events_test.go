@@ -0,0 +1,144 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// readEventLines reads lines from an event stream until want returns true or
+// the timeout passes.
+func readEventLines(t *testing.T, url string, timeout time.Duration, after func(), want func(lines []string) bool) []string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+	if after != nil {
+		after()
+	}
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if want(lines) {
+			return lines
+		}
+	}
+	t.Fatalf("stream ended before expected lines arrived, got %q", lines)
+	return nil
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBoardEventsHeartbeat(t *testing.T) {
+	srv := httptest.NewServer(NewServer(newTestStore(t, bulkBoard), WithHeartbeat(20*time.Millisecond)))
+	defer srv.Close()
+
+	lines := readEventLines(t, srv.URL+"/api/v1/board/events", 2*time.Second, nil, func(lines []string) bool {
+		return containsLine(lines, ": ping")
+	})
+	if lines[0] != "event: revision" {
+		t.Fatalf("expected the current revision first, got %q", lines[0])
+	}
+}
+
+func TestBoardEventsDeliversRevisions(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	srv := httptest.NewServer(NewServer(store, WithHeartbeat(time.Hour)))
+	defer srv.Close()
+
+	var want string
+	readEventLines(t, srv.URL+"/api/v1/board/events", 2*time.Second, func() {
+		board, err := store.SwapCategories("cat1", "cat2")
+		if err != nil {
+			t.Fatalf("swap: %v", err)
+		}
+		want = fmt.Sprintf(`data: {"revision":%d}`, board.Revision)
+	}, func(lines []string) bool {
+		return containsLine(lines, want)
+	})
+}
+
+func TestPublishDoesNotBlockOnSlowSubscriber(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	revisions, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+
+	var last BoardState
+	for i := 0; i < 3; i++ {
+		board, err := store.SwapCategories("cat1", "cat2")
+		if err != nil {
+			t.Fatalf("swap: %v", err)
+		}
+		last = board
+	}
+	if got := (<-revisions).Revision; got != last.Revision {
+		t.Fatalf("expected only the latest revision %d, got %d", last.Revision, got)
+	}
+}
+
+func TestObserverCountTracksSubscribers(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if got := store.ObserverCount(); got != 0 {
+		t.Fatalf("expected no observers, got %d", got)
+	}
+	_, first := store.Subscribe()
+	_, second := store.Subscribe()
+	if got := store.ObserverCount(); got != 2 {
+		t.Fatalf("expected 2 observers, got %d", got)
+	}
+	first()
+	second()
+	if got := store.ObserverCount(); got != 0 {
+		t.Fatalf("expected observers removed, got %d", got)
+	}
+}
+
+func TestBoardEventsSyntheticThenReal(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	srv := httptest.NewServer(NewServer(store, WithHeartbeat(time.Hour)))
+	defer srv.Close()
+
+	var want string
+	lines := readEventLines(t, srv.URL+"/api/v1/board/events", 2*time.Second, func() {
+		if got := store.ObserverCount(); got != 1 {
+			t.Fatalf("expected the stream subscribed, got %d observers", got)
+		}
+		// a synthetic event proves the stream is live before any write
+		store.NotifyObservers(BoardEvent{Revision: 999})
+	}, func(lines []string) bool {
+		if want == "" && containsLine(lines, `data: {"revision":999}`) {
+			board, err := store.SwapCategories("cat1", "cat2")
+			if err != nil {
+				t.Fatalf("swap: %v", err)
+			}
+			want = fmt.Sprintf(`data: {"revision":%d}`, board.Revision)
+		}
+		return want != "" && containsLine(lines, want)
+	})
+	if store.GetState().Revision == 999 || len(lines) < 4 {
+		t.Fatalf("expected the synthetic event to leave the board alone, got %q", lines)
+	}
+}
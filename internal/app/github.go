@@ -0,0 +1,296 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// defaultGitHubAPI is where issues are fetched when the request does not
+	// carry them.
+	defaultGitHubAPI = "https://api.github.com"
+	// githubPageLimit caps how many pages of 100 issues one import fetches.
+	githubPageLimit = 10
+)
+
+// GitHubIssue is the part of a GitHub REST API issue the importer reads. A
+// pre-fetched payload can be the API's response as-is.
+type GitHubIssue struct {
+	Number  int           `json:"number"`
+	Title   string        `json:"title"`
+	Body    string        `json:"body"`
+	HTMLURL string        `json:"html_url"`
+	State   string        `json:"state"`
+	Labels  []GitHubLabel `json:"labels"`
+	// PullRequest is set on pull requests, which the issues API also lists.
+	PullRequest json.RawMessage `json:"pull_request,omitempty"`
+}
+
+type GitHubLabel struct {
+	Name string `json:"name"`
+}
+
+func (i GitHubIssue) labelNames() []string {
+	names := make([]string, 0, len(i.Labels))
+	for _, label := range i.Labels {
+		if name := strings.TrimSpace(label.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GitHubImportRequest imports a repository's open issues into an active
+// category. Issues are fetched from GitHub unless the request carries them.
+type GitHubImportRequest struct {
+	// Repo is "owner/name".
+	Repo string `json:"repo,omitempty"`
+	// Labels keeps only issues carrying every listed label.
+	Labels []string `json:"labels,omitempty"`
+	// Token is a personal access token, needed for private repositories.
+	Token string `json:"token,omitempty"`
+	// Category names the active category to fill; it is created when no
+	// category has that name.
+	Category string        `json:"category"`
+	Issues   []GitHubIssue `json:"issues,omitempty"`
+}
+
+func (r *GitHubImportRequest) Normalize() {
+	r.Repo = strings.Trim(strings.TrimSpace(r.Repo), "/")
+	r.Category = strings.TrimSpace(r.Category)
+	r.Labels = trimTags(r.Labels)
+}
+
+func (r GitHubImportRequest) Validate() error {
+	if r.Category == "" {
+		return fmt.Errorf("%w: category required", ErrInvalidRequest)
+	}
+	if r.Issues != nil {
+		return nil
+	}
+	owner, name, ok := strings.Cut(r.Repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("%w: repo must be owner/name when issues are not supplied", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// GitHubImportResult summarises an import. Overflowed lists IDs of new tasks
+// that did not fit the category and went to the backburner.
+type GitHubImportResult struct {
+	Created    int      `json:"created"`
+	Updated    int      `json:"updated"`
+	Skipped    int      `json:"skipped"`
+	Overflowed []string `json:"overflowed"`
+}
+
+// ImportGitHubIssues creates a task per open issue, or updates the task
+// already linked to the issue's URL wherever it sits on the board, so a
+// repeated import does not duplicate anything. Closed issues, pull requests,
+// issues missing a filter label and unchanged tasks are skipped.
+func (s *Store) ImportGitHubIssues(req GitHubImportRequest) (GitHubImportResult, BoardState, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return GitHubImportResult{}, BoardState{}, err
+	}
+	links := make([]TaskLink, 0, len(req.Issues))
+	for _, issue := range req.Issues {
+		links = append(links, TaskLink{URL: issue.HTMLURL})
+	}
+	if err := s.checkLinks(links); err != nil {
+		return GitHubImportResult{}, BoardState{}, err
+	}
+
+	result := GitHubImportResult{Overflowed: []string{}}
+	updatedState, err := s.withWrite(func(current *BoardState) error {
+		next := current.Clone()
+		normalizeBoardState(&next)
+		state := &next
+		now := s.now()
+
+		catIdx := findCategoryIndexByName(state.Categories, req.Category)
+		if catIdx == -1 {
+			for _, pool := range [][]Category{state.CategoryBackburner, state.CategoryArchives} {
+				if findCategoryIndexByName(pool, req.Category) != -1 {
+					return fmt.Errorf("%w: category %q is not on the board", ErrInvalidRequest, req.Category)
+				}
+			}
+			if len(state.Categories) >= CategoryLimit {
+				return ErrCategoryLimit
+			}
+			state.Categories = append(state.Categories, Category{ID: NewID(), Name: req.Category, Tasks: []Task{}})
+			catIdx = len(state.Categories) - 1
+		}
+		if state.Categories[catIdx].Locked {
+			return errCategoryLocked
+		}
+
+		linked := map[string]string{}
+		for _, found := range collectSearchResults(state) {
+			for _, link := range found.Task.Links {
+				linked[link.URL] = found.Task.ID
+			}
+		}
+		for _, issue := range req.Issues {
+			labels := issue.labelNames()
+			if issue.PullRequest != nil || (issue.State != "" && issue.State != "open") || issue.HTMLURL == "" || !hasAllTags(labels, req.Labels) {
+				result.Skipped++
+				continue
+			}
+			notes, err := state.notes.apply(issue.Body)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSpace(issue.Title)
+			if name == "" {
+				name = fmt.Sprintf("#%d", issue.Number)
+			}
+
+			if id, ok := linked[issue.HTMLURL]; ok {
+				task, _, err := findTask(state, id, nil)
+				if err != nil {
+					return err
+				}
+				if task.Name == name && task.Notes == notes && equalTags(task.Tags, labels) {
+					result.Skipped++
+					continue
+				}
+				task.Name, task.Notes, task.Tags = name, notes, labels
+				task.UpdatedAt = now
+				recordEvent(state, task, AuditEvent{At: now, Action: "import"})
+				result.Updated++
+				continue
+			}
+
+			task := Task{
+				ID:        NewID(),
+				Name:      name,
+				Notes:     notes,
+				State:     state.StateList()[0].ID,
+				Size:      state.Sizes()[0],
+				Links:     []TaskLink{{Text: fmt.Sprintf("#%d", issue.Number), URL: issue.HTMLURL}},
+				Tags:      labels,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			recordEvent(state, &task, AuditEvent{At: now, Action: "import"})
+			linked[issue.HTMLURL] = task.ID
+			result.Created++
+
+			cat := &state.Categories[catIdx]
+			sizeBefore := categorySize(*cat)
+			cat.Tasks = append(cat.Tasks, task)
+			if state.checkPlacement(*cat, sizeBefore, false) != nil {
+				cat.Tasks = cat.Tasks[:len(cat.Tasks)-1]
+				task.SourceID, task.Source = cat.ID, cat.Name
+				state.Backburner = append(state.Backburner, task)
+				result.Overflowed = append(result.Overflowed, task.ID)
+			}
+		}
+		*current = next
+		return nil
+	})
+	if err != nil {
+		return GitHubImportResult{}, BoardState{}, err
+	}
+	return result, updatedState, nil
+}
+
+func hasAllTags(tags, want []string) bool {
+	for _, tag := range want {
+		if !hasTag(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchGitHubIssues lists a repository's open issues carrying labels.
+func fetchGitHubIssues(ctx context.Context, client *http.Client, apiBase, repo string, labels []string, token string) ([]GitHubIssue, error) {
+	issues := []GitHubIssue{}
+	for page := 1; page <= githubPageLimit; page++ {
+		query := url.Values{}
+		query.Set("state", "open")
+		query.Set("per_page", "100")
+		query.Set("page", fmt.Sprint(page))
+		if len(labels) > 0 {
+			query.Set("labels", strings.Join(labels, ","))
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(apiBase, "/")+"/repos/"+repo+"/issues?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var batch []GitHubIssue
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("github responded %s", resp.Status)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&batch)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode github issues: %w", err)
+		}
+		issues = append(issues, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return issues, nil
+}
+
+func (s *Server) handleImportGitHub(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req GitHubImportRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if req.Issues == nil {
+		issues, err := fetchGitHubIssues(r.Context(), http.DefaultClient, s.githubAPI, req.Repo, req.Labels, req.Token)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		req.Issues = issues
+	}
+	result, board, err := s.store.ImportGitHubIssues(req)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"summary": result,
+	}, board)
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func githubIssue(n int, title string, labels ...string) GitHubIssue {
+	issue := GitHubIssue{Number: n, Title: title, Body: "body " + title, State: "open", HTMLURL: fmt.Sprintf("https://github.com/acme/app/issues/%d", n)}
+	for _, l := range labels {
+		issue.Labels = append(issue.Labels, GitHubLabel{Name: l})
+	}
+	return issue
+}
+
+func TestImportGitHubIssuesCreatesAndUpdates(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	pr := githubIssue(9, "A pull request", "bug")
+	pr.PullRequest = json.RawMessage(`{}`)
+	req := GitHubImportRequest{Category: "GitHub", Labels: []string{"bug"}, Issues: []GitHubIssue{
+		githubIssue(1, "Crash on start", "bug", "p1"),
+		githubIssue(2, "Docs typo", "docs"),
+		pr,
+	}}
+
+	result, board, err := store.ImportGitHubIssues(req)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 0 || result.Skipped != 2 {
+		t.Fatalf("expected 1 created, 2 skipped, got %+v", result)
+	}
+	cat := board.Categories[len(board.Categories)-1]
+	if cat.Name != "GitHub" || len(cat.Tasks) != 1 {
+		t.Fatalf("expected new GitHub category with one task, got %+v", cat)
+	}
+	task := cat.Tasks[0]
+	if task.Name != "Crash on start" || task.Notes != "body Crash on start" || strings.Join(task.Tags, ",") != "bug,p1" {
+		t.Fatalf("unexpected task %+v", task)
+	}
+	if len(task.Links) != 1 || task.Links[0].URL != "https://github.com/acme/app/issues/1" {
+		t.Fatalf("expected link back to the issue, got %+v", task.Links)
+	}
+
+	// the task moves and the issue is retitled; the rerun follows it
+	if _, _, err := store.MoveTask(task.ID, MoveTaskRequest{Location: LocationBackburner}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	req.Issues[0].Title = "Crash on startup"
+	result, board, err = store.ImportGitHubIssues(req)
+	if err != nil {
+		t.Fatalf("reimport: %v", err)
+	}
+	if result.Created != 0 || result.Updated != 1 || result.Skipped != 2 {
+		t.Fatalf("expected 1 updated, got %+v", result)
+	}
+	if len(board.Backburner) != 1 || board.Backburner[0].Name != "Crash on startup" || board.Backburner[0].ID != task.ID {
+		t.Fatalf("expected the backburnered task updated in place, got %+v", board.Backburner)
+	}
+
+	result, _, err = store.ImportGitHubIssues(req)
+	if err != nil || result.Skipped != 3 || result.Updated != 0 {
+		t.Fatalf("expected an unchanged rerun to skip everything, got %+v %v", result, err)
+	}
+}
+
+func TestImportGitHubIssuesOverflowsToBackburner(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	issues := []GitHubIssue{}
+	for n := 1; n <= 7; n++ {
+		issues = append(issues, githubIssue(n, fmt.Sprintf("Issue %d", n)))
+	}
+	result, board, err := store.ImportGitHubIssues(GitHubImportRequest{Category: "Alpha", Issues: issues})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	// Alpha holds 3 of 5 points already
+	if result.Created != 7 || len(result.Overflowed) != 5 {
+		t.Fatalf("expected 5 of 7 overflowed, got %+v", result)
+	}
+	if len(board.Categories[0].Tasks) != 5 || len(board.Backburner) != 5 || board.Backburner[0].SourceID != "cat1" {
+		t.Fatalf("expected Alpha full and the rest parked from it, got %d and %+v", len(board.Categories[0].Tasks), board.Backburner)
+	}
+
+	if _, _, err := store.ImportGitHubIssues(GitHubImportRequest{Category: "Alpha"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected repo required without issues, got %v", err)
+	}
+}
+
+func TestImportGitHubEndpointFetchesIssues(t *testing.T) {
+	var gotAuth, gotLabels string
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/app/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		gotLabels = r.URL.Query().Get("labels")
+		writeJSON(w, http.StatusOK, []GitHubIssue{githubIssue(4, "Fetched", "bug")})
+	}))
+	defer github.Close()
+
+	srv := NewServer(newTestStore(t, bulkBoard))
+	srv.githubAPI = github.URL
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/import/github?includeBoard=false", `{"repo":"acme/app","labels":["bug"],"token":"pat","category":"Beta"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Summary GitHubImportResult `json:"summary"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Summary.Created != 1 || gotAuth != "Bearer pat" || gotLabels != "bug" {
+		t.Fatalf("unexpected import %+v auth %q labels %q", resp.Summary, gotAuth, gotLabels)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/v1/import/github", `{"repo":"acme/missing","category":"Beta"}`)
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502 when GitHub fails, got %d", rec.Code)
+	}
+}
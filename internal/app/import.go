@@ -0,0 +1,578 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ImportModeReplace = "replace"
+	ImportModeMerge   = "merge"
+
+	// Conflict strategies for merge imports. Without one, categories that
+	// share a name are merged and colliding task IDs are regenerated.
+	ConflictSkip      = "skip"
+	ConflictDuplicate = "duplicate"
+	ConflictOverwrite = "overwrite"
+)
+
+type ImportRequest struct {
+	Mode      string     `json:"mode,omitempty"`
+	Conflicts string     `json:"conflicts,omitempty"`
+	Relocate  bool       `json:"relocate,omitempty"`
+	Board     BoardState `json:"board"`
+}
+
+func (r *ImportRequest) Normalize() {
+	if r.Mode == "" {
+		r.Mode = ImportModeReplace
+	}
+	normalizeBoardState(&r.Board)
+}
+
+func (r ImportRequest) Validate() error {
+	switch r.Mode {
+	case ImportModeReplace, ImportModeMerge:
+	default:
+		return fmt.Errorf("%w: unknown import mode %q", ErrInvalidRequest, r.Mode)
+	}
+	switch r.Conflicts {
+	case "", ConflictSkip, ConflictDuplicate, ConflictOverwrite:
+	default:
+		return fmt.Errorf("%w: unknown conflict strategy %q", ErrInvalidRequest, r.Conflicts)
+	}
+	if err := validateBoardState(r.Board); err != nil {
+		return err
+	}
+	return checkBoardCapacity(r.Board)
+}
+
+// ImportSummary reports what an import did to the board.
+type ImportSummary struct {
+	Mode               string            `json:"mode"`
+	CategoriesMerged   []string          `json:"categoriesMerged"`
+	CategoriesAdded    []string          `json:"categoriesAdded"`
+	CategoriesParked   []string          `json:"categoriesParked"`
+	TasksAdded         int               `json:"tasksAdded"`
+	TasksOverflowed    []string          `json:"tasksOverflowed"`
+	RegeneratedTaskIDs map[string]string `json:"regeneratedTaskIds"`
+	RegeneratedCatIDs  map[string]string `json:"regeneratedCategoryIds"`
+	Conflicts          []ImportConflict  `json:"conflicts"`
+}
+
+// ImportConflict records an incoming entity that collided with an existing
+// one and the strategy applied to it.
+type ImportConflict struct {
+	Kind     string `json:"kind"`
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Strategy string `json:"strategy"`
+}
+
+func newImportSummary(mode string) ImportSummary {
+	return ImportSummary{
+		Mode:               mode,
+		CategoriesMerged:   []string{},
+		CategoriesAdded:    []string{},
+		CategoriesParked:   []string{},
+		TasksOverflowed:    []string{},
+		RegeneratedTaskIDs: map[string]string{},
+		RegeneratedCatIDs:  map[string]string{},
+		Conflicts:          []ImportConflict{},
+	}
+}
+
+// Import replaces the board with the incoming one, or merges the incoming
+// board into the current one, in a single write.
+func (s *Store) Import(req ImportRequest) (ImportSummary, BoardState, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return ImportSummary{}, BoardState{}, err
+	}
+	if err := s.checkBoardLinks(&req.Board); err != nil {
+		return ImportSummary{}, BoardState{}, err
+	}
+
+	summary := newImportSummary(req.Mode)
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		if req.Mode == ImportModeReplace {
+			next := req.Board.Clone()
+			if err := state.notes.applyBoard(&next); err != nil {
+				return err
+			}
+			replaceBoard(state, next)
+			normalizeBoardState(state)
+			for _, cat := range state.Categories {
+				summary.CategoriesAdded = append(summary.CategoriesAdded, cat.Name)
+				summary.TasksAdded += len(cat.Tasks)
+			}
+			return nil
+		}
+		next := state.Clone()
+		normalizeBoardState(&next)
+		// merged tasks are numbered after this board's own
+		incoming := req.Board.Clone()
+		clearTaskRefs(&incoming)
+		if err := mergeBoard(&next, incoming, req.Conflicts, req.Relocate, &summary); err != nil {
+			return err
+		}
+		*state = next
+		return nil
+	})
+	if err != nil {
+		return ImportSummary{}, BoardState{}, err
+	}
+	return summary, updatedState, nil
+}
+
+// mergeBoard folds incoming into state. Categories are matched by name and
+// task collisions by ID, with the conflict strategy deciding what happens to
+// each collision. Unmatched categories are added while CategoryLimit allows
+// and parked in the category backburner otherwise; tasks that do not fit an
+// active category overflow to the backburner.
+func mergeBoard(state *BoardState, incoming BoardState, strategy string, relocate bool, summary *ImportSummary) error {
+	if err := state.checkMergedTasks(&incoming); err != nil {
+		return err
+	}
+	if err := state.notes.applyBoard(&incoming); err != nil {
+		return err
+	}
+	taskIDs := map[string]struct{}{}
+	categoryIDs := map[string]struct{}{}
+	categoryNames := map[string]struct{}{}
+	// added holds the final IDs of incoming tasks that were placed
+	added := map[string]struct{}{}
+	type extent struct{ size, count int }
+	sizesBefore := map[string]extent{}
+	for _, cat := range state.Categories {
+		sizesBefore[cat.ID] = extent{categorySize(cat), len(cat.Tasks)}
+	}
+	for _, result := range collectSearchResults(state) {
+		taskIDs[result.Task.ID] = struct{}{}
+	}
+	for _, pool := range [][]Category{state.Categories, state.CategoryBackburner, state.CategoryArchives} {
+		for _, cat := range pool {
+			categoryIDs[cat.ID] = struct{}{}
+			categoryNames[cat.Name] = struct{}{}
+		}
+	}
+
+	// prepareTask resolves an ID collision and reports whether the caller
+	// should still place the task.
+	prepareTask := func(task Task) (Task, bool) {
+		task.Focused = false
+		if _, clash := taskIDs[task.ID]; clash {
+			switch strategy {
+			case ConflictSkip:
+				summary.Conflicts = append(summary.Conflicts, ImportConflict{Kind: "task", ID: task.ID, Strategy: ConflictSkip})
+				return Task{}, false
+			case ConflictOverwrite:
+				summary.Conflicts = append(summary.Conflicts, ImportConflict{Kind: "task", ID: task.ID, Strategy: ConflictOverwrite})
+				summary.TasksAdded++
+				added[task.ID] = struct{}{}
+				for _, pool := range taskPools {
+					tasks, idx, _ := locateTaskInPool(state, pool, task.ID)
+					if tasks == nil {
+						continue
+					}
+					if relocate {
+						*tasks = append((*tasks)[:idx], (*tasks)[idx+1:]...)
+						return task, true
+					}
+					existing := (*tasks)[idx]
+					task.SourceID, task.Source = existing.SourceID, existing.Source
+					if pool != PoolActive {
+						task.Urgent = false
+					}
+					(*tasks)[idx] = task
+					return Task{}, false
+				}
+				return task, true
+			default:
+				if strategy == ConflictDuplicate {
+					summary.Conflicts = append(summary.Conflicts, ImportConflict{Kind: "task", ID: task.ID, Strategy: ConflictDuplicate})
+				}
+				id := NewID()
+				summary.RegeneratedTaskIDs[task.ID] = id
+				task.ID = id
+			}
+		}
+		taskIDs[task.ID] = struct{}{}
+		added[task.ID] = struct{}{}
+		summary.TasksAdded++
+		return task, true
+	}
+	prepareTasks := func(tasks []Task) []Task {
+		out := make([]Task, 0, len(tasks))
+		for _, task := range tasks {
+			if prepared, ok := prepareTask(task); ok {
+				out = append(out, prepared)
+			}
+		}
+		return out
+	}
+	prepareCategory := func(cat Category) Category {
+		if _, clash := categoryIDs[cat.ID]; clash {
+			id := NewID()
+			summary.RegeneratedCatIDs[cat.ID] = id
+			cat.ID = id
+		}
+		categoryIDs[cat.ID] = struct{}{}
+		categoryNames[cat.Name] = struct{}{}
+		cat.Tasks = prepareTasks(cat.Tasks)
+		return cat
+	}
+	place := func(target *Category, task Task) {
+		if !state.placeOrOverflow(target, task) {
+			summary.TasksOverflowed = append(summary.TasksOverflowed, task.ID)
+		}
+	}
+	// findByName locates an existing category with the same name in any pool.
+	findByName := func(name string) (*Category, bool) {
+		if idx := findCategoryIndexByName(state.Categories, name); idx != -1 {
+			return &state.Categories[idx], true
+		}
+		for _, pool := range []*[]Category{&state.CategoryBackburner, &state.CategoryArchives} {
+			if idx := findCategoryIndexByName(*pool, name); idx != -1 {
+				return &(*pool)[idx], false
+			}
+		}
+		return nil, false
+	}
+
+	// resolveCategory handles a name collision and reports whether the
+	// incoming category still needs to be placed.
+	resolveCategory := func(cat Category) (Category, bool) {
+		target, active := findByName(cat.Name)
+		if target == nil {
+			return cat, true
+		}
+		switch strategy {
+		case ConflictSkip:
+			summary.Conflicts = append(summary.Conflicts, ImportConflict{Kind: "category", ID: cat.ID, Name: cat.Name, Strategy: ConflictSkip})
+			return Category{}, false
+		case ConflictDuplicate:
+			summary.Conflicts = append(summary.Conflicts, ImportConflict{Kind: "category", ID: cat.ID, Name: cat.Name, Strategy: ConflictDuplicate})
+			cat.Name = uniqueCategoryName(cat.Name, categoryNames)
+			return cat, true
+		case ConflictOverwrite:
+			summary.Conflicts = append(summary.Conflicts, ImportConflict{Kind: "category", ID: target.ID, Name: cat.Name, Strategy: ConflictOverwrite})
+			summary.CategoriesMerged = append(summary.CategoriesMerged, cat.Name)
+			// a locked category keeps its tasks, and the incoming ones
+			// overflow below
+			if !target.Locked {
+				for _, task := range target.Tasks {
+					delete(taskIDs, task.ID)
+				}
+				target.Tasks = []Task{}
+			}
+			for _, task := range prepareTasks(cat.Tasks) {
+				if !active {
+					task.Urgent = false
+					target.Tasks = append(target.Tasks, task)
+					continue
+				}
+				place(target, task)
+			}
+			return Category{}, false
+		}
+
+		summary.CategoriesMerged = append(summary.CategoriesMerged, cat.Name)
+		for _, task := range prepareTasks(cat.Tasks) {
+			if !active {
+				task.Urgent = false
+				target.Tasks = append(target.Tasks, task)
+				continue
+			}
+			if task.Urgent && hasUrgent(*target) {
+				task.Urgent = false
+			}
+			place(target, task)
+		}
+		return Category{}, false
+	}
+
+	for _, cat := range incoming.Categories {
+		cat, place := resolveCategory(cat)
+		if !place {
+			continue
+		}
+		cat = prepareCategory(cat)
+		if len(state.Categories) < CategoryLimit {
+			state.Categories = append(state.Categories, cat)
+			summary.CategoriesAdded = append(summary.CategoriesAdded, cat.Name)
+			continue
+		}
+		clearCategoryFocus(&cat)
+		state.CategoryBackburner = append(state.CategoryBackburner, cat)
+		summary.CategoriesParked = append(summary.CategoriesParked, cat.Name)
+	}
+	for _, cat := range incoming.CategoryBackburner {
+		cat, place := resolveCategory(cat)
+		if !place {
+			continue
+		}
+		state.CategoryBackburner = append(state.CategoryBackburner, prepareCategory(cat))
+		summary.CategoriesParked = append(summary.CategoriesParked, cat.Name)
+	}
+	for _, cat := range incoming.CategoryArchives {
+		cat, place := resolveCategory(cat)
+		if !place {
+			continue
+		}
+		state.CategoryArchives = append(state.CategoryArchives, prepareCategory(cat))
+	}
+
+	placeLoose := func(tasks []Task) []Task {
+		out := []Task{}
+		for _, task := range tasks {
+			if id, ok := summary.RegeneratedCatIDs[task.SourceID]; ok {
+				task.SourceID = id
+			}
+			task.Urgent = false
+			if prepared, ok := prepareTask(task); ok {
+				out = append(out, prepared)
+			}
+		}
+		return out
+	}
+	state.Backburner = append(state.Backburner, placeLoose(incoming.Backburner)...)
+	state.Archives = append(state.Archives, placeLoose(incoming.Archives)...)
+	for _, task := range placeLoose(incoming.Inbox) {
+		task.SourceID, task.Source = "", ""
+		state.Inbox = append(state.Inbox, task)
+	}
+
+	// incoming tasks keep pointing at their incoming blockers across
+	// regenerated IDs, and must not close a loop with the board's own
+	forEachPoolTask(state, func(task *Task, _ bool) {
+		if _, ok := added[task.ID]; !ok || len(task.BlockedBy) == 0 {
+			return
+		}
+		blockedBy := make([]string, len(task.BlockedBy))
+		for i, blocker := range task.BlockedBy {
+			if id, ok := summary.RegeneratedTaskIDs[blocker]; ok {
+				blocker = id
+			}
+			blockedBy[i] = blocker
+		}
+		task.BlockedBy = blockedBy
+	})
+	ids, blockers := dependencyGraph(state)
+	for _, id := range ids {
+		if _, ok := added[id]; !ok {
+			continue
+		}
+		if err := checkNoCycle(blockers, id); err != nil {
+			return err
+		}
+	}
+
+	// in-place overwrites and added categories can break an active
+	// category's limits; ones already over them may keep what they had
+	for i := range state.Categories {
+		cat := state.Categories[i]
+		before := sizesBefore[cat.ID]
+		if err := state.checkCapacity(cat, before.size, false); err != nil {
+			return fmt.Errorf("category %s: %w", cat.Name, err)
+		}
+		if err := state.checkTaskLimit(cat, before.count); err != nil {
+			return err
+		}
+		urgentSeen := false
+		for j := range state.Categories[i].Tasks {
+			if state.Categories[i].Tasks[j].Urgent {
+				state.Categories[i].Tasks[j].Urgent = !urgentSeen
+				urgentSeen = true
+			}
+		}
+	}
+	return nil
+}
+
+// checkMergedTasks rejects a board to be merged into state when any of its
+// tasks is in a state or has a size the target board does not define.
+// Incoming boards are validated against their own settings, which a merge
+// leaves behind.
+func (state *BoardState) checkMergedTasks(incoming *BoardState) error {
+	var err error
+	forEachPoolTask(incoming, func(task *Task, _ bool) {
+		if err != nil {
+			return
+		}
+		if stateErr := state.ValidateTaskState(task.State); stateErr != nil {
+			err = fmt.Errorf("%w: task %s: %v", ErrInvalidRequest, task.ID, stateErr)
+			return
+		}
+		if _, sizeErr := state.NormalizeSize(task.Size); sizeErr != nil {
+			err = fmt.Errorf("%w: task %s: %v", ErrInvalidRequest, task.ID, sizeErr)
+		}
+	})
+	return err
+}
+
+// uniqueCategoryName suffixes name until it no longer collides.
+func uniqueCategoryName(name string, taken map[string]struct{}) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		if _, clash := taken[candidate]; !clash {
+			return candidate
+		}
+	}
+}
+
+func findCategoryIndexByName(categories []Category, name string) int {
+	for i := range categories {
+		if categories[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasUrgent(cat Category) bool {
+	for _, task := range cat.Tasks {
+		if task.Urgent {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeBoardFromFile folds the board at path into the current one in a
+// single write. Every category and task from the file gets a fresh ID, so
+// nothing collides; categories are appended to their pools, renamed when a
+// name is taken, and pool tasks are appended to the backburner, archive and
+// inbox. Focus does not carry over.
+//
+// When the file's active categories would push the board past
+// CategoryLimit, nothing is merged and ErrCategoryLimit is returned;
+// MergeBoardTasksFromFile merges such a file without adding them.
+func (s *Store) MergeBoardFromFile(path string) (BoardState, error) {
+	return s.mergeBoardFile(path, false)
+}
+
+// MergeBoardTasksFromFile is MergeBoardFromFile for a board with no room for
+// the file's active categories. Their tasks join the active category of the
+// same name instead, overflowing to the backburner when it is locked or
+// full, and tasks of categories with no match are left out.
+func (s *Store) MergeBoardTasksFromFile(path string) (BoardState, error) {
+	return s.mergeBoardFile(path, true)
+}
+
+func (s *Store) mergeBoardFile(path string, tasksOnly bool) (BoardState, error) {
+	source, err := loadBoardFile(strings.TrimSpace(path))
+	if err != nil {
+		return BoardState{}, err
+	}
+	rehashBoardIDs(&source)
+	if err := s.checkBoardLinks(&source); err != nil {
+		return BoardState{}, err
+	}
+
+	return s.withWrite(func(current *BoardState) error {
+		if !tasksOnly && len(current.Categories)+len(source.Categories) > CategoryLimit {
+			return fmt.Errorf("%w: %d active categories in %s do not fit; merge only their tasks instead", ErrCategoryLimit, len(source.Categories), path)
+		}
+		next := current.Clone()
+		normalizeBoardState(&next)
+		if err := next.checkMergedTasks(&source); err != nil {
+			return err
+		}
+		if err := next.notes.applyBoard(&source); err != nil {
+			return err
+		}
+		names := map[string]struct{}{}
+		for _, pool := range [][]Category{next.Categories, next.CategoryBackburner, next.CategoryArchives} {
+			for _, cat := range pool {
+				names[cat.Name] = struct{}{}
+			}
+		}
+		rename := func(cat Category) Category {
+			if _, taken := names[cat.Name]; taken {
+				cat.Name = uniqueCategoryName(cat.Name, names)
+			}
+			names[cat.Name] = struct{}{}
+			return cat
+		}
+
+		if tasksOnly {
+			for _, cat := range source.Categories {
+				idx := findCategoryIndexByName(next.Categories, cat.Name)
+				if idx == -1 {
+					continue
+				}
+				target := &next.Categories[idx]
+				for _, task := range cat.Tasks {
+					task.Urgent = false
+					next.placeOrOverflow(target, task)
+				}
+			}
+		} else {
+			for _, cat := range source.Categories {
+				next.Categories = append(next.Categories, rename(cat))
+			}
+		}
+		for _, cat := range source.CategoryBackburner {
+			next.CategoryBackburner = append(next.CategoryBackburner, rename(cat))
+		}
+		for _, cat := range source.CategoryArchives {
+			next.CategoryArchives = append(next.CategoryArchives, rename(cat))
+		}
+		next.Backburner = append(next.Backburner, source.Backburner...)
+		next.Archives = append(next.Archives, source.Archives...)
+		next.Inbox = append(next.Inbox, source.Inbox...)
+		*current = next
+		return nil
+	})
+}
+
+// placeOrOverflow appends task to the active category target, or parks it in
+// the backburner when target is locked or the task would break its capacity
+// or task limit. It reports whether the task was placed in target.
+func (state *BoardState) placeOrOverflow(target *Category, task Task) bool {
+	if !target.Locked {
+		sizeBefore := categorySize(*target)
+		target.Tasks = append(target.Tasks, task)
+		if state.checkPlacement(*target, sizeBefore, false) == nil {
+			return true
+		}
+		target.Tasks = target.Tasks[:len(target.Tasks)-1]
+	}
+	task.Urgent = false
+	task.Focused = false
+	task.SourceID, task.Source = target.ID, target.Name
+	state.Backburner = append(state.Backburner, task)
+	return false
+}
+
+// rehashBoardIDs gives every category and task on board a new ID, carrying
+// the new IDs into SourceID and BlockedBy references, and clears focus.
+func rehashBoardIDs(board *BoardState) {
+	categoryIDs := map[string]string{}
+	taskIDs := map[string]string{}
+	for _, pool := range [][]Category{board.Categories, board.CategoryBackburner, board.CategoryArchives} {
+		for i := range pool {
+			id := NewID()
+			categoryIDs[pool[i].ID] = id
+			pool[i].ID = id
+		}
+	}
+	forEachPoolTask(board, func(task *Task, _ bool) {
+		id := NewID()
+		taskIDs[task.ID] = id
+		task.ID = id
+		task.Ref = ""
+	})
+	forEachPoolTask(board, func(task *Task, _ bool) {
+		task.Focused = false
+		if id, ok := categoryIDs[task.SourceID]; ok {
+			task.SourceID = id
+		}
+		for i, blocker := range task.BlockedBy {
+			if id, ok := taskIDs[blocker]; ok {
+				task.BlockedBy[i] = id
+			}
+		}
+	})
+}
@@ -0,0 +1,476 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mergeIncoming = `{
+	"categories": [
+		{"id":"other-alpha","name":"Alpha","tasks":[
+			{"id":"t1","name":"Clashing id","description":"","notes":"","state":"todo","size":1},
+			{"id":"n2","name":"Too big","description":"","notes":"","state":"todo","size":3}
+		]},
+		{"id":"cat2","name":"Delta","tasks":[
+			{"id":"n3","name":"Fresh","description":"","notes":"","state":"todo","size":1}
+		]}
+	],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func decodeBoard(t *testing.T, raw string) BoardState {
+	t.Helper()
+	var board BoardState
+	if err := json.Unmarshal([]byte(raw), &board); err != nil {
+		t.Fatalf("decode board: %v", err)
+	}
+	return board
+}
+
+func TestImportMergeCombinesBoards(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	summary, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, mergeIncoming)})
+	if err != nil {
+		t.Fatalf("merge import: %v", err)
+	}
+	if len(summary.CategoriesMerged) != 1 || summary.CategoriesMerged[0] != "Alpha" {
+		t.Fatalf("expected Alpha merged, got %v", summary.CategoriesMerged)
+	}
+	if len(summary.CategoriesAdded) != 1 || summary.CategoriesAdded[0] != "Delta" {
+		t.Fatalf("expected Delta added, got %v", summary.CategoriesAdded)
+	}
+	newID, ok := summary.RegeneratedTaskIDs["t1"]
+	if !ok || newID == "t1" {
+		t.Fatalf("expected colliding task id regenerated, got %v", summary.RegeneratedTaskIDs)
+	}
+	if _, ok := summary.RegeneratedCatIDs["cat2"]; !ok {
+		t.Fatalf("expected colliding category id regenerated, got %v", summary.RegeneratedCatIDs)
+	}
+	if len(summary.TasksOverflowed) != 1 || summary.TasksOverflowed[0] != "n2" {
+		t.Fatalf("expected n2 to overflow, got %v", summary.TasksOverflowed)
+	}
+
+	alpha := board.Categories[0]
+	if len(alpha.Tasks) != 4 || alpha.Tasks[3].ID != newID {
+		t.Fatalf("expected merged task appended to Alpha, got %+v", alpha.Tasks)
+	}
+	if len(board.Backburner) != 1 || board.Backburner[0].SourceID != "cat1" {
+		t.Fatalf("expected overflow in backburner sourced from Alpha, got %+v", board.Backburner)
+	}
+	if len(board.Categories) != 3 || board.Categories[2].Name != "Delta" {
+		t.Fatalf("expected Delta appended to active categories")
+	}
+}
+
+func TestImportMergeParksCategoriesOverLimit(t *testing.T) {
+	full := `{
+		"categories": [
+			{"id":"c1","name":"One","tasks":[]},
+			{"id":"c2","name":"Two","tasks":[]},
+			{"id":"c3","name":"Three","tasks":[]},
+			{"id":"c4","name":"Four","tasks":[]},
+			{"id":"c5","name":"Five","tasks":[]}
+		]
+	}`
+	store := newTestStore(t, full)
+	incoming := `{"categories":[{"id":"c6","name":"Six","tasks":[]}]}`
+
+	summary, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)})
+	if err != nil {
+		t.Fatalf("merge import: %v", err)
+	}
+	if len(summary.CategoriesParked) != 1 || len(board.CategoryBackburner) != 1 {
+		t.Fatalf("expected Six parked in category backburner, got %v", summary.CategoriesParked)
+	}
+}
+
+func TestImportReplaceAndValidation(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	if _, _, err := store.Import(ImportRequest{Mode: "mystery"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest for unknown mode, got %v", err)
+	}
+	_, board, err := store.Import(ImportRequest{Board: decodeBoard(t, mergeIncoming)})
+	if err != nil {
+		t.Fatalf("replace import: %v", err)
+	}
+	if len(board.Categories) != 2 || board.Categories[0].ID != "other-alpha" {
+		t.Fatalf("expected board replaced, got %+v", board.Categories)
+	}
+}
+
+const conflictIncoming = `{
+	"categories": [
+		{"id":"imported-alpha","name":"Alpha","tasks":[
+			{"id":"i1","name":"Imported","description":"","notes":"","state":"todo","size":1}
+		]}
+	],
+	"backburner": [
+		{"id":"t3","name":"Three from elsewhere","description":"","notes":"","state":"blocked","size":2}
+	],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func conflictsFor(summary ImportSummary, kind string) []ImportConflict {
+	var out []ImportConflict
+	for _, c := range summary.Conflicts {
+		if c.Kind == kind {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func TestImportConflictSkip(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	summary, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Conflicts: ConflictSkip, Board: decodeBoard(t, conflictIncoming)})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if got := conflictsFor(summary, "category"); len(got) != 1 || got[0].Strategy != ConflictSkip || got[0].Name != "Alpha" {
+		t.Fatalf("expected Alpha skipped, got %+v", got)
+	}
+	if got := conflictsFor(summary, "task"); len(got) != 1 || got[0].ID != "t3" {
+		t.Fatalf("expected t3 skipped, got %+v", got)
+	}
+	if len(board.Categories[0].Tasks) != 3 || len(board.Backburner) != 0 {
+		t.Fatalf("expected board unchanged by skipped entities")
+	}
+	if board.Categories[0].Tasks[2].Name != "Three" {
+		t.Fatalf("expected existing t3 untouched")
+	}
+}
+
+func TestImportConflictDuplicate(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	summary, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Conflicts: ConflictDuplicate, Board: decodeBoard(t, conflictIncoming)})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(summary.Conflicts) != 2 {
+		t.Fatalf("expected category and task conflicts, got %+v", summary.Conflicts)
+	}
+	if len(board.Categories) != 3 || board.Categories[2].Name != "Alpha (2)" {
+		t.Fatalf("expected duplicate category with suffixed name, got %+v", board.Categories)
+	}
+	if len(board.Backburner) != 1 || board.Backburner[0].ID == "t3" || summary.RegeneratedTaskIDs["t3"] != board.Backburner[0].ID {
+		t.Fatalf("expected t3 duplicated under a new id, got %+v", board.Backburner)
+	}
+	if board.Categories[0].Tasks[2].Name != "Three" {
+		t.Fatalf("expected existing t3 untouched")
+	}
+}
+
+func TestImportMergeRemapsBlockedBy(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	incoming := `{
+		"categories": [
+			{"id":"cat9","name":"Gamma","tasks":[
+				{"id":"t1","name":"Incoming one","state":"todo","size":1},
+				{"id":"g2","name":"G2","state":"todo","size":1,"blockedBy":["t1","t4"]}
+			]}
+		]
+	}`
+	summary, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	renamed := summary.RegeneratedTaskIDs["t1"]
+	if renamed == "" {
+		t.Fatalf("expected the colliding t1 given a new id, got %+v", summary.RegeneratedTaskIDs)
+	}
+	got := board.Categories[2].Tasks[1].BlockedBy
+	if len(got) != 2 || got[0] != renamed || got[1] != "t4" {
+		t.Fatalf("expected g2 blocked by %s and t4, got %v", renamed, got)
+	}
+	if board.Categories[0].Tasks[0].BlockedBy != nil {
+		t.Fatalf("expected the board's own t1 untouched, got %v", board.Categories[0].Tasks[0].BlockedBy)
+	}
+}
+
+func TestImportMergeRejectsCycles(t *testing.T) {
+	store := newTestStore(t, strings.Replace(bulkBoard, `"tags":["sprint"]`, `"tags":["sprint"],"blockedBy":["n1"]`, 1))
+	before := boardJSON(t, store)
+	incoming := `{
+		"categories": [
+			{"id":"cat9","name":"Gamma","tasks":[
+				{"id":"n1","name":"N1","state":"todo","size":1,"blockedBy":["t1"]}
+			]}
+		]
+	}`
+	if _, _, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a merge closing a cycle rejected, got %v", err)
+	}
+	if boardJSON(t, store) != before {
+		t.Fatalf("expected the rejected merge to leave the board as it was")
+	}
+}
+
+func TestImportMergeChecksTargetStates(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	before := boardJSON(t, store)
+	// valid against its own states, which the merge does not bring along
+	incoming := `{
+		"categories": [
+			{"id":"cat9","name":"Gamma","tasks":[
+				{"id":"n1","name":"N1","state":"review","size":1}
+			]}
+		],
+		"states": [{"id":"todo"},{"id":"review"}]
+	}`
+	if _, _, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a task in an unknown state rejected, got %v", err)
+	}
+	if boardJSON(t, store) != before {
+		t.Fatalf("expected the rejected merge to leave the board as it was")
+	}
+	if _, err := NewStore(store.path); err != nil {
+		t.Fatalf("expected the data file to reopen: %v", err)
+	}
+}
+
+func TestImportConflictOverwrite(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	summary, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Conflicts: ConflictOverwrite, Board: decodeBoard(t, conflictIncoming)})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	// overwriting Alpha drops its old tasks, so t3 no longer collides
+	if len(summary.Conflicts) != 1 || summary.Conflicts[0].Kind != "category" {
+		t.Fatalf("expected only the category conflict, got %+v", summary.Conflicts)
+	}
+	alpha := board.Categories[0]
+	if alpha.ID != "cat1" || len(alpha.Tasks) != 1 || alpha.Tasks[0].ID != "i1" {
+		t.Fatalf("expected Alpha's tasks replaced in place, got %+v", alpha)
+	}
+	if len(board.Backburner) != 1 || board.Backburner[0].Name != "Three from elsewhere" {
+		t.Fatalf("expected incoming t3 in backburner, got %+v", board.Backburner)
+	}
+}
+
+func TestImportConflictOverwritePreservesLocation(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	incoming := `{"backburner":[{"id":"t4","name":"Four, revised","description":"","notes":"","state":"todo","size":1}]}`
+
+	_, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Conflicts: ConflictOverwrite, Board: decodeBoard(t, incoming)})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if board.Categories[1].Tasks[0].Name != "Four, revised" || len(board.Backburner) != 0 {
+		t.Fatalf("expected t4 overwritten in its category, got %+v / %+v", board.Categories[1].Tasks, board.Backburner)
+	}
+
+	_, board, err = store.Import(ImportRequest{Mode: ImportModeMerge, Conflicts: ConflictOverwrite, Relocate: true, Board: decodeBoard(t, incoming)})
+	if err != nil {
+		t.Fatalf("import with relocate: %v", err)
+	}
+	if len(board.Categories[1].Tasks) != 0 || len(board.Backburner) != 1 || board.Backburner[0].ID != "t4" {
+		t.Fatalf("expected t4 relocated to backburner, got %+v", board.Backburner)
+	}
+}
+
+const mergeFileBoard = `{
+	"categories": [
+		{"id":"other-alpha","name":"Alpha","tasks":[
+			{"id":"t1","name":"Clashing id","description":"","notes":"","state":"todo","size":1,"focused":true},
+			{"id":"n2","name":"Too big","description":"","notes":"","state":"todo","size":3,"blockedBy":["t1"]}
+		]},
+		{"id":"cat2","name":"Delta","tasks":[]}
+	],
+	"backburner": [
+		{"id":"n4","name":"Parked","description":"","notes":"","state":"todo","size":1,"sourceId":"cat2","source":"Delta"}
+	],
+	"archives": [
+		{"id":"n5","name":"Old","description":"","notes":"","state":"done","size":1}
+	],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func writeMergeSource(t *testing.T, raw string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "other.json")
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	return path
+}
+
+func TestMergeBoardFromFile(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	board, err := store.MergeBoardFromFile(writeMergeSource(t, mergeFileBoard))
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	names := []string{}
+	for _, cat := range board.Categories {
+		names = append(names, cat.Name)
+	}
+	if strings.Join(names, ",") != "Alpha,Beta,Alpha (2),Delta" {
+		t.Fatalf("expected source categories appended, got %v", names)
+	}
+	merged := board.Categories[2]
+	if merged.ID == "other-alpha" || merged.Tasks[0].ID == "t1" || merged.Tasks[0].Focused {
+		t.Fatalf("expected fresh IDs and no focus, got %+v", merged)
+	}
+	if blockedBy := merged.Tasks[1].BlockedBy; len(blockedBy) != 1 || blockedBy[0] != merged.Tasks[0].ID {
+		t.Fatalf("expected blockedBy remapped to %s, got %v", merged.Tasks[0].ID, blockedBy)
+	}
+	if len(board.Backburner) != 1 || board.Backburner[0].SourceID != board.Categories[3].ID {
+		t.Fatalf("expected parked task pointing at the new Delta, got %+v", board.Backburner)
+	}
+	if len(board.Archives) != 1 || board.Archives[0].Name != "Old" {
+		t.Fatalf("expected archive appended, got %+v", board.Archives)
+	}
+
+	// the merge was saved
+	reopened, err := NewStore(store.Path())
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := len(reopened.GetState().Categories); got != 4 {
+		t.Fatalf("expected 4 categories on disk, got %d", got)
+	}
+}
+
+func TestMergeBoardFromFileOverLimitMergesTasksOnly(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	source := writeMergeSource(t, strings.Replace(mergeFileBoard, `{"id":"cat2","name":"Delta","tasks":[]}`,
+		`{"id":"cat2","name":"Delta","tasks":[]},{"id":"c3","name":"Echo","tasks":[]},{"id":"c4","name":"Foxtrot","tasks":[]}`, 1))
+	before, err := os.ReadFile(store.Path())
+	if err != nil {
+		t.Fatalf("read board: %v", err)
+	}
+	memory := boardJSON(t, store)
+
+	if _, err := store.MergeBoardFromFile(source); !errors.Is(err, ErrCategoryLimit) {
+		t.Fatalf("expected ErrCategoryLimit, got %v", err)
+	}
+	after, err := os.ReadFile(store.Path())
+	if err != nil {
+		t.Fatalf("read board: %v", err)
+	}
+	if string(after) != string(before) || boardJSON(t, store) != memory {
+		t.Fatalf("expected the refused merge to leave the board and its file as they were")
+	}
+
+	board, err := store.MergeBoardTasksFromFile(source)
+	if err != nil {
+		t.Fatalf("merge tasks: %v", err)
+	}
+	if got := categoryIDs(board.Categories); got != "cat1,cat2" {
+		t.Fatalf("expected no categories added, got %s", got)
+	}
+	// Alpha had 3 points: the size 1 task fits and the size 3 one overflows
+	alpha := board.Categories[0].Tasks
+	if len(alpha) != 4 || alpha[3].Name != "Clashing id" {
+		t.Fatalf("expected Clashing id merged into Alpha, got %d tasks", len(alpha))
+	}
+	if len(board.Backburner) != 2 || board.Backburner[0].Name != "Too big" || board.Backburner[0].SourceID != "cat1" {
+		t.Fatalf("expected Too big overflowed from cat1, got %+v", board.Backburner)
+	}
+
+	if _, err := store.MergeBoardFromFile(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a missing file rejected, got %v", err)
+	}
+}
+
+func TestImportMergeChecksTargetSizes(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	before := boardJSON(t, store)
+	incoming := `{
+		"categories": [
+			{"id":"cat9","name":"Gamma","tasks":[
+				{"id":"n1","name":"N1","state":"todo","size":8}
+			]}
+		],
+		"sizeScale": [1, 8],
+		"capacity": 8
+	}`
+	if _, _, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a size off the board's scale rejected, got %v", err)
+	}
+	if _, err := store.MergeBoardFromFile(writeMergeSource(t, incoming)); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a size off the board's scale rejected from a file, got %v", err)
+	}
+	if boardJSON(t, store) != before {
+		t.Fatalf("expected the rejected merges to leave the board as it was")
+	}
+}
+
+func TestMergeBoardFromFileChecksTargetStates(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	before := boardJSON(t, store)
+	source := writeMergeSource(t, `{
+		"categories": [
+			{"id":"cat9","name":"Gamma","tasks":[
+				{"id":"n1","name":"N1","state":"review","size":1}
+			]}
+		],
+		"states": [{"id":"todo"},{"id":"review"}]
+	}`)
+	if _, err := store.MergeBoardFromFile(source); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a task in an unknown state rejected, got %v", err)
+	}
+	if _, err := store.MergeBoardTasksFromFile(source); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a task in an unknown state rejected, got %v", err)
+	}
+	if boardJSON(t, store) != before {
+		t.Fatalf("expected the rejected merges to leave the board as it was")
+	}
+	if _, err := NewStore(store.path); err != nil {
+		t.Fatalf("expected the data file to reopen: %v", err)
+	}
+}
+
+func TestImportMergeRespectsCategoryRules(t *testing.T) {
+	incoming := `{
+		"categories": [
+			{"id":"x","name":"Alpha","tasks":[{"id":"i1","name":"I1","state":"todo","size":1}]},
+			{"id":"y","name":"Beta","tasks":[{"id":"i2","name":"I2","state":"todo","size":1}]}
+		]
+	}`
+	board := strings.Replace(bulkBoard, `"name":"Alpha",`, `"name":"Alpha","locked":true,`, 1)
+	board = strings.Replace(board, `"name":"Beta",`, `"name":"Beta","maxTasks":1,`, 1)
+	for name, mode := range map[string]string{"hard": CapacityHard, "soft": CapacitySoft} {
+		store := newTestStore(t, board)
+		if _, err := store.SetCapacityMode(mode); err != nil {
+			t.Fatalf("%s: set capacity mode: %v", name, err)
+		}
+		summary, merged, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)})
+		if err != nil {
+			t.Fatalf("%s: merge: %v", name, err)
+		}
+		if len(merged.Categories[0].Tasks) != 3 || len(merged.Categories[1].Tasks) != 1 {
+			t.Fatalf("%s: expected locked Alpha and full Beta unchanged, got %d and %d tasks", name, len(merged.Categories[0].Tasks), len(merged.Categories[1].Tasks))
+		}
+		if strings.Join(summary.TasksOverflowed, ",") != "i1,i2" {
+			t.Fatalf("%s: expected both tasks overflowed, got %v", name, summary.TasksOverflowed)
+		}
+	}
+
+	// soft boards take tasks past the column limit
+	store := newTestStore(t, softBoard)
+	big := `{"categories":[{"id":"x","name":"Ideas","tasks":[{"id":"i1","name":"I1","state":"todo","size":5}]}]}`
+	summary, merged, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, big)})
+	if err != nil {
+		t.Fatalf("soft merge: %v", err)
+	}
+	if len(merged.Categories[0].Tasks) != 2 || len(summary.TasksOverflowed) != 0 {
+		t.Fatalf("expected the task merged past capacity on a soft board, got %+v", summary)
+	}
+}
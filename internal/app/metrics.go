@@ -0,0 +1,64 @@
+package app
+
+import (
+	"sort"
+	"time"
+)
+
+// latencySamples is how many recent save durations are kept.
+const latencySamples = 128
+
+type latencyRing struct {
+	samples [latencySamples]time.Duration
+	next    int
+	count   int
+}
+
+func (r *latencyRing) record(d time.Duration) {
+	r.samples[r.next] = d
+	r.next = (r.next + 1) % latencySamples
+	if r.count < latencySamples {
+		r.count++
+	}
+}
+
+// SaveLatency summarises recent board saves. Durations are in milliseconds.
+type SaveLatency struct {
+	Samples   int     `json:"samples"`
+	LastMs    float64 `json:"lastMs"`
+	AverageMs float64 `json:"averageMs"`
+	P99Ms     float64 `json:"p99Ms"`
+}
+
+func (r *latencyRing) summary() SaveLatency {
+	if r.count == 0 {
+		return SaveLatency{}
+	}
+	sorted := make([]time.Duration, r.count)
+	copy(sorted, r.samples[:r.count])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	last := r.samples[(r.next+latencySamples-1)%latencySamples]
+	p99 := sorted[(len(sorted)*99+99)/100-1]
+	return SaveLatency{
+		Samples:   r.count,
+		LastMs:    millis(last),
+		AverageMs: millis(total / time.Duration(r.count)),
+		P99Ms:     millis(p99),
+	}
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// SaveLatency reports timings for the most recent writes of the data file.
+func (s *Store) SaveLatency() SaveLatency {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.saveTimes.summary()
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSaveLatencyRecorded(t *testing.T) {
+	store := newTestStore(t, swapBoard)
+	for i := 0; i < 5; i++ {
+		if _, err := store.SwapCategories("a", "b"); err != nil {
+			t.Fatalf("swap: %v", err)
+		}
+	}
+
+	var resp struct {
+		SaveLatency SaveLatency `json:"saveLatency"`
+	}
+	decodeBody(t, doRequest(t, NewServer(store), http.MethodGet, "/api/v1/board/info", ""), &resp)
+	if resp.SaveLatency.Samples != 5 {
+		t.Fatalf("expected 5 samples, got %d", resp.SaveLatency.Samples)
+	}
+	if resp.SaveLatency.AverageMs <= 0 || resp.SaveLatency.P99Ms < resp.SaveLatency.AverageMs {
+		t.Fatalf("unexpected latency summary %+v", resp.SaveLatency)
+	}
+}
+
+func TestLatencyRingWraps(t *testing.T) {
+	var ring latencyRing
+	for i := 1; i <= latencySamples+10; i++ {
+		ring.record(time.Duration(i) * time.Millisecond)
+	}
+	got := ring.summary()
+	if got.Samples != latencySamples || got.LastMs != float64(latencySamples+10) {
+		t.Fatalf("unexpected summary after wrap %+v", got)
+	}
+}
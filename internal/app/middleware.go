@@ -0,0 +1,38 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// BodyLoggingMiddleware logs up to maxLen bytes of each request body at debug
+// level, then restores the body so handlers can read it in full.
+func BodyLoggingMiddleware(logger *slog.Logger, maxLen int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody || !logger.Enabled(r.Context(), slog.LevelDebug) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// read one extra byte so we know whether the body was cut short
+			head, err := io.ReadAll(io.LimitReader(r.Body, int64(maxLen)+1))
+			if err != nil {
+				logger.Debug("read request body", "method", r.Method, "path", r.URL.Path, "error", err)
+			}
+			logged := string(head)
+			if len(head) > maxLen {
+				logged = string(head[:maxLen]) + fmt.Sprintf(" [truncated at %d bytes]", maxLen)
+			}
+			logger.Debug("request body", "method", r.Method, "path", r.URL.Path, "body", logged)
+
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLoggingMiddlewarePreservesBody(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	body := strings.Repeat("x", 40)
+
+	var seen string
+	handler := BodyLoggingMiddleware(logger, 10)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+		seen = string(data)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body)))
+
+	if seen != body {
+		t.Fatalf("expected handler to read full body, got %d bytes", len(seen))
+	}
+	if !strings.Contains(logs.String(), "[truncated at 10 bytes]") {
+		t.Fatalf("expected truncation marker in log, got %q", logs.String())
+	}
+}
+
+func TestBodyLoggingMiddlewareShortBody(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	var seen string
+	handler := BodyLoggingMiddleware(logger, 64)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		seen = string(data)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(`{"a":1}`)))
+
+	if seen != `{"a":1}` {
+		t.Fatalf("expected handler to read body, got %q", seen)
+	}
+	if strings.Contains(logs.String(), "truncated") {
+		t.Fatalf("did not expect truncation for short body")
+	}
+}
+
+func TestAPIMiddlewareSkipsIndex(t *testing.T) {
+	calls := 0
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			next.ServeHTTP(w, r)
+		})
+	}
+	srv := NewServer(newTestStore(t, bulkBoard), WithAPIMiddleware(mw))
+
+	doRequest(t, srv, http.MethodGet, "/", "")
+	doRequest(t, srv, http.MethodGet, "/api/board", "")
+	if calls != 1 {
+		t.Fatalf("expected middleware to run for API routes only, got %d calls", calls)
+	}
+}
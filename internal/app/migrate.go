@@ -0,0 +1,109 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrMigrationMismatch is returned when a migrated board does not load back
+// identical to its source.
+var ErrMigrationMismatch = errors.New("migrated board does not match its source")
+
+// StorageFile is the only storage backend: a JSON board file. Migration
+// targets name it as "file:path"; a bare path means the same.
+const StorageFile = "file"
+
+// ParseStorageTarget splits a "backend:location" migration target. Backends
+// other than StorageFile are rejected, since no other storage is built in.
+func ParseStorageTarget(target string) (string, error) {
+	backend, location, ok := strings.Cut(target, ":")
+	if !ok {
+		backend, location = StorageFile, target
+	}
+	if backend != StorageFile {
+		return "", fmt.Errorf("%w: storage backend %q is not available; only %s: targets are supported", ErrInvalidRequest, backend, StorageFile)
+	}
+	if strings.TrimSpace(location) == "" {
+		return "", fmt.Errorf("%w: %q names no location", ErrInvalidRequest, target)
+	}
+	if strings.Contains(location, DateToken) {
+		return "", fmt.Errorf("%w: %q is a rolling path; name a single day's file", ErrInvalidRequest, target)
+	}
+	return location, nil
+}
+
+// MigrationSummary counts what a migration copied.
+type MigrationSummary struct {
+	Categories         int `json:"categories"`
+	CategoryBackburner int `json:"categoryBackburner"`
+	CategoryArchives   int `json:"categoryArchives"`
+	Tasks              int `json:"tasks"`
+	Backburner         int `json:"backburner"`
+	Archives           int `json:"archives"`
+	Inbox              int `json:"inbox"`
+	AuditEvents        int `json:"auditEvents"`
+}
+
+func summarizeBoard(board BoardState) MigrationSummary {
+	summary := MigrationSummary{
+		Categories:         len(board.Categories),
+		CategoryBackburner: len(board.CategoryBackburner),
+		CategoryArchives:   len(board.CategoryArchives),
+		Backburner:         len(board.Backburner),
+		Archives:           len(board.Archives),
+		Inbox:              len(board.Inbox),
+		AuditEvents:        len(board.AuditLog),
+	}
+	forEachPoolTask(&board, func(*Task, bool) { summary.Tasks++ })
+	return summary
+}
+
+// MigrateBoard copies the board at from to to. The source is only read, and
+// must pass the same validation as a board being served. The destination is
+// written atomically, then loaded back and compared with the source; any
+// difference fails with ErrMigrationMismatch. An existing, non-empty
+// destination is only replaced with force.
+func MigrateBoard(from, to string, force bool) (MigrationSummary, error) {
+	src, err := ParseStorageTarget(from)
+	if err != nil {
+		return MigrationSummary{}, err
+	}
+	dst, err := ParseStorageTarget(to)
+	if err != nil {
+		return MigrationSummary{}, err
+	}
+	if src == dst {
+		return MigrationSummary{}, fmt.Errorf("%w: source and destination are the same file", ErrInvalidRequest)
+	}
+	board, err := loadBoardFile(src)
+	if err != nil {
+		return MigrationSummary{}, err
+	}
+	if info, err := os.Stat(dst); err == nil && info.Size() > 0 && !force {
+		return MigrationSummary{}, fmt.Errorf("%w: %s already holds data; use force to replace it", ErrInvalidRequest, dst)
+	}
+
+	if err := writeBoardFile(dst, board); err != nil {
+		return MigrationSummary{}, err
+	}
+	written, err := loadBoardFile(dst)
+	if err != nil {
+		return MigrationSummary{}, fmt.Errorf("%w: %v", ErrMigrationMismatch, err)
+	}
+	want, err := json.Marshal(board)
+	if err != nil {
+		return MigrationSummary{}, err
+	}
+	got, err := json.Marshal(written)
+	if err != nil {
+		return MigrationSummary{}, err
+	}
+	if !bytes.Equal(got, want) {
+		return MigrationSummary{}, ErrMigrationMismatch
+	}
+	return summarizeBoard(written), nil
+}
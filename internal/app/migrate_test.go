@@ -0,0 +1,61 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateBoard(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "board.json")
+	if err := os.WriteFile(src, []byte(bulkBoard), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	dst := filepath.Join(dir, "copy.json")
+
+	summary, err := MigrateBoard("file:"+src, "file:"+dst, false)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if summary.Categories != 2 || summary.Tasks != 4 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	want, err := loadBoardFile(src)
+	if err != nil {
+		t.Fatalf("reload source: %v", err)
+	}
+	if got := readBoardFile(t, dst); len(got.Categories) != len(want.Categories) || len(got.Categories[0].Tasks) != 3 {
+		t.Fatalf("unexpected destination %+v", got)
+	}
+
+	if _, err := MigrateBoard("file:"+src, "file:"+dst, false); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a non-empty destination to be refused, got %v", err)
+	}
+	if _, err := MigrateBoard(src, dst, true); err != nil {
+		t.Fatalf("expected force to replace the destination: %v", err)
+	}
+}
+
+func TestMigrateBoardRejectsBadTargets(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "board.json")
+	if err := os.WriteFile(src, []byte(`{"categories":[],"colour":"blue"}`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	dst := filepath.Join(dir, "copy.json")
+	for name, to := range map[string]string{
+		"unknown backend": "sqlite:" + filepath.Join(dir, "board.db"),
+		"same file":       "file:" + src,
+		"rolling path":    "file:" + filepath.Join(dir, "board-{date}.json"),
+		"invalid source":  "file:" + dst,
+	} {
+		if _, err := MigrateBoard("file:"+src, to, false); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := os.Stat(dst); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected an invalid source to write nothing, got %v", err)
+	}
+}
@@ -3,16 +3,39 @@ package app
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
+	// ColumnCapacity is the default per-category size limit; boards may
+	// configure their own.
 	ColumnCapacity = 5
 	CategoryLimit  = 5
+	// MaxForcedCapacity bounds how far a forced create or move may push a
+	// category past ColumnCapacity. Boards with a configured capacity allow
+	// twice that instead.
+	MaxForcedCapacity = 2 * ColumnCapacity
+	// MaxBlockedReasonLength caps Task.BlockedReason, in characters.
+	MaxBlockedReasonLength = 280
 
 	LocationCategory      = "category"
 	LocationBackburner    = "backburner"
 	LocationArchive       = "archive"
+	LocationInbox         = "inbox"
 	LocationCategoryBoard = "board"
+
+	PoolActive             = "active"
+	PoolBackburner         = "backburner"
+	PoolArchive            = "archive"
+	PoolCategoryBackburner = "category-backburner"
+	PoolCategoryArchive    = "category-archive"
+	PoolInbox              = "inbox"
+
+	CapacityHard = "hard"
+	CapacitySoft = "soft"
 )
 
 // BoardState represents the persisted board.
@@ -20,18 +43,78 @@ type BoardState struct {
 	Categories         []Category `json:"categories"`
 	Backburner         []Task     `json:"backburner"`
 	Archives           []Task     `json:"archives"`
+	// Inbox holds untriaged tasks that belong to no category yet.
+	Inbox []Task `json:"inbox"`
 	CategoryBackburner []Category `json:"categoryBackburner"`
 	CategoryArchives   []Category `json:"categoryArchives"`
+	// CapacityMode is CapacityHard (the default when empty) or CapacitySoft.
+	CapacityMode string `json:"capacityMode,omitempty"`
+	// Transitions maps a state to the states a task may move to from it.
+	// States without an entry, or an empty map, are unrestricted.
+	Transitions map[string][]string `json:"transitions,omitempty"`
+	// Revision increases by one with every persisted write.
+	Revision int64 `json:"revision"`
+	// AuditLog holds the most recent task events across the whole board.
+	AuditLog []AuditEvent `json:"auditLog,omitempty"`
+	// States lists the task states in display order. Empty means
+	// DefaultStates.
+	States []StateDef `json:"states,omitempty"`
+	// SizeScale lists the allowed task sizes in ascending order. Empty means
+	// DefaultSizeScale.
+	SizeScale []int `json:"sizeScale,omitempty"`
+	// Capacity is the per-category size limit. Zero means ColumnCapacity.
+	Capacity int `json:"capacity,omitempty"`
+	// StaleAfterDays flags active tasks that have been doing or blocked for
+	// at least this many days. Zero disables the flag.
+	StaleAfterDays int `json:"staleAfterDays,omitempty"`
+	// MaxTasks is the default task limit for categories without their own.
+	// Zero means no limit.
+	MaxTasks int `json:"maxTasks,omitempty"`
+	// LastTaskSeq is the highest task reference number handed out. Numbers
+	// are never reused, even after the task is deleted.
+	LastTaskSeq int `json:"lastTaskSeq,omitempty"`
+
+	// notes is the store's notes limit, carried for the duration of a write
+	// so every path that sets notes enforces it; never persisted.
+	notes notesLimit
+	// logged counts the audit events recorded during the current write.
+	logged int
+}
+
+// StateDef describes a task state. Label and Color are display hints for the
+// frontend; ID is what tasks store.
+type StateDef struct {
+	ID    string `json:"id"`
+	Label string `json:"label,omitempty"`
+	Color string `json:"color,omitempty"`
 }
 
 type Category struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
 	Tasks []Task `json:"tasks"`
+	// LastBoardIndex is where the category sat on the board before it was
+	// backburnered or archived; only kept with WithRestorePosition.
+	LastBoardIndex *int `json:"lastBoardIndex,omitempty"`
+	// Locked categories keep their task list fixed: tasks can be edited in
+	// place but not added, removed or moved.
+	Locked bool `json:"locked,omitempty"`
+	// MaxTasks caps how many tasks the category holds. Zero falls back to
+	// the board's MaxTasks.
+	MaxTasks int `json:"maxTasks,omitempty"`
+
+	// computed for responses, never persisted
+	HasFocus     bool `json:"hasFocus,omitempty"`
+	OverCapacity bool `json:"overCapacity,omitempty"`
+	TaskCount    int  `json:"taskCount,omitempty"`
+	TaskLimit    int  `json:"taskLimit,omitempty"`
 }
 
 type Task struct {
     ID          string     `json:"id"`
+    // Ref is the task's short reference, such as "T-12", numbered in order
+    // of creation and accepted by the API wherever an ID is.
+    Ref         string     `json:"ref,omitempty"`
     Name        string     `json:"name"`
     Description string     `json:"description"`
     Notes       string     `json:"notes"`
@@ -39,10 +122,46 @@ type Task struct {
     Size        int        `json:"size"`
     Links       []TaskLink `json:"links,omitempty"`
     Checklist   []ChecklistItem `json:"checklist,omitempty"`
+    Tags        []string   `json:"tags,omitempty"`
+    BlockedReason string   `json:"blockedReason,omitempty"`
+    // BlockedBy lists IDs of tasks that must finish before this one.
+    BlockedBy   []string   `json:"blockedBy,omitempty"`
+    DelegatedTo string     `json:"delegatedTo,omitempty"`
     Urgent      bool       `json:"urgent,omitempty"`
     Focused     bool       `json:"focused,omitempty"`
     SourceID    string     `json:"sourceId,omitempty"`
     Source      string     `json:"source,omitempty"`
+    CreatedAt   time.Time  `json:"createdAt"`
+    UpdatedAt   time.Time  `json:"updatedAt"`
+    CompletedAt *time.Time `json:"completedAt,omitempty"`
+    DueDate     *time.Time `json:"dueDate,omitempty"`
+    // MoveCount counts the times the task has been moved; tasks saved
+    // before it was tracked read as zero.
+    MoveCount   int        `json:"moveCount"`
+    History     []AuditEvent `json:"history,omitempty"`
+
+    // computed for responses, never persisted
+    AgeDays     int  `json:"ageDays,omitempty"`
+    DaysInState int  `json:"daysInState,omitempty"`
+    Stale       bool `json:"stale,omitempty"`
+}
+
+const (
+	// DefaultActor is recorded when a change does not name who made it.
+	DefaultActor = "unknown"
+
+	maxTaskHistory = 50
+	maxAuditLog    = 500
+)
+
+// AuditEvent records a single change to a task and the actor behind it.
+type AuditEvent struct {
+	At     time.Time `json:"at"`
+	Actor  string    `json:"actor"`
+	TaskID string    `json:"taskId,omitempty"`
+	Action string    `json:"action"`
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to,omitempty"`
 }
 
 type TaskLink struct {
@@ -55,6 +174,21 @@ type ChecklistItem struct {
     Done bool   `json:"done"`
 }
 
+// SearchResult pairs a task with the pool and category it currently lives in.
+type SearchResult struct {
+	Task         Task   `json:"task"`
+	Pool         string `json:"pool"`
+	CategoryID   string `json:"categoryId,omitempty"`
+	CategoryName string `json:"categoryName,omitempty"`
+}
+
+// UrgentTask is an urgent task with the active category holding it.
+type UrgentTask struct {
+	Task
+	CategoryID   string `json:"categoryId"`
+	CategoryName string `json:"categoryName"`
+}
+
 // Validation Errors
 var (
 	ErrTaskNotFound      = errors.New("task not found")
@@ -62,12 +196,53 @@ var (
 	ErrCapacityExceeded  = errors.New("column capacity exceeded")
 	ErrInvalidState      = errors.New("invalid state value")
 	ErrInvalidLocation   = errors.New("invalid location")
-	ErrInvalidTaskSize   = errors.New("task size must be between 1 and 5")
+	ErrInvalidTaskSize   = errors.New("invalid task size")
 	ErrInvalidRequest    = errors.New("invalid request")
 	ErrDuplicateCategory = errors.New("duplicate category name")
 	ErrCategoryLimit     = errors.New("maximum number of categories reached")
+	ErrFileExists        = errors.New("target file already exists")
+	ErrInvalidTransition = errors.New("state transition not allowed")
+	ErrStateInUse        = errors.New("state in use")
+	ErrSizeInUse         = errors.New("size in use")
+	ErrNotFocusable      = errors.New("task cannot be focused")
+	ErrTaskLimit         = errors.New("category task limit reached")
+	ErrBoardTooLarge     = errors.New("board exceeds the data file size limit")
+	ErrPatchConflict     = errors.New("task changed since it was read")
+
+	errCategoryLocked = fmt.Errorf("%w: category is locked", ErrInvalidRequest)
 )
 
+// recordEvent appends ev to the task's history and the board audit log,
+// trimming both to their most recent entries.
+func recordEvent(state *BoardState, task *Task, ev AuditEvent) {
+	if ev.Actor == "" {
+		ev.Actor = DefaultActor
+	}
+	ev.TaskID = task.ID
+	task.History = appendCapped(task.History, ev, maxTaskHistory)
+	state.AuditLog = appendCapped(state.AuditLog, ev, maxAuditLog)
+	state.logged++
+}
+
+func appendCapped(events []AuditEvent, ev AuditEvent, max int) []AuditEvent {
+	events = append(events, ev)
+	if len(events) > max {
+		events = append([]AuditEvent(nil), events[len(events)-max:]...)
+	}
+	return events
+}
+
+// patchEvent describes a patch that moved a task from prevState.
+func patchEvent(task Task, prevState, actor string, at time.Time) AuditEvent {
+	ev := AuditEvent{At: at, Actor: actor, Action: "update"}
+	if task.State != prevState {
+		ev.Action = "state"
+		ev.From = prevState
+		ev.To = task.State
+	}
+	return ev
+}
+
 func (t Task) Clone() Task {
     out := t
     if len(t.Links) > 0 {
@@ -78,11 +253,35 @@ func (t Task) Clone() Task {
         out.Checklist = make([]ChecklistItem, len(t.Checklist))
         copy(out.Checklist, t.Checklist)
     }
+    if len(t.Tags) > 0 {
+        out.Tags = make([]string, len(t.Tags))
+        copy(out.Tags, t.Tags)
+    }
+    if len(t.BlockedBy) > 0 {
+        out.BlockedBy = make([]string, len(t.BlockedBy))
+        copy(out.BlockedBy, t.BlockedBy)
+    }
+    if t.CompletedAt != nil {
+        completed := *t.CompletedAt
+        out.CompletedAt = &completed
+    }
+    if t.DueDate != nil {
+        due := *t.DueDate
+        out.DueDate = &due
+    }
+    if len(t.History) > 0 {
+        out.History = make([]AuditEvent, len(t.History))
+        copy(out.History, t.History)
+    }
     return out
 }
 
 func (c Category) Clone() Category {
 	out := c
+	if c.LastBoardIndex != nil {
+		index := *c.LastBoardIndex
+		out.LastBoardIndex = &index
+	}
 	if len(c.Tasks) > 0 {
 		out.Tasks = make([]Task, len(c.Tasks))
 		for i := range c.Tasks {
@@ -93,7 +292,24 @@ func (c Category) Clone() Category {
 }
 
 func (b BoardState) Clone() BoardState {
-	out := BoardState{}
+	out := BoardState{CapacityMode: b.CapacityMode, Capacity: b.Capacity, StaleAfterDays: b.StaleAfterDays, MaxTasks: b.MaxTasks, Revision: b.Revision, LastTaskSeq: b.LastTaskSeq, notes: b.notes, logged: b.logged}
+	if b.Transitions != nil {
+		out.Transitions = make(map[string][]string, len(b.Transitions))
+		for from, to := range b.Transitions {
+			out.Transitions[from] = append([]string(nil), to...)
+		}
+	}
+	if len(b.States) > 0 {
+		out.States = make([]StateDef, len(b.States))
+		copy(out.States, b.States)
+	}
+	if len(b.SizeScale) > 0 {
+		out.SizeScale = append([]int(nil), b.SizeScale...)
+	}
+	if len(b.AuditLog) > 0 {
+		out.AuditLog = make([]AuditEvent, len(b.AuditLog))
+		copy(out.AuditLog, b.AuditLog)
+	}
 	if len(b.Categories) > 0 {
 		out.Categories = make([]Category, len(b.Categories))
 		for i := range b.Categories {
@@ -106,6 +322,12 @@ func (b BoardState) Clone() BoardState {
 			out.Backburner[i] = b.Backburner[i].Clone()
 		}
 	}
+	if len(b.Inbox) > 0 {
+		out.Inbox = make([]Task, len(b.Inbox))
+		for i := range b.Inbox {
+			out.Inbox[i] = b.Inbox[i].Clone()
+		}
+	}
 	if len(b.Archives) > 0 {
 		out.Archives = make([]Task, len(b.Archives))
 		for i := range b.Archives {
@@ -127,24 +349,181 @@ func (b BoardState) Clone() BoardState {
 	return out
 }
 
-var allowedStates = map[string]struct{}{
-    "todo":      {},
-    "doing":     {},
-    "blocked":   {},
-    "done":      {},
-    "delegated": {},
+// DefaultStates is the state list used by boards that do not configure
+// their own.
+var DefaultStates = []StateDef{
+	{ID: "todo", Label: "To do"},
+	{ID: "doing", Label: "Doing"},
+	{ID: "blocked", Label: "Blocked"},
+	{ID: "delegated", Label: "Delegated"},
+	{ID: "done", Label: "Done"},
+}
+
+// MaxStateIDLength caps the length of a configured state ID.
+const MaxStateIDLength = 24
+
+var stateIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// StateList returns the board's configured states, or DefaultStates.
+func (b *BoardState) StateList() []StateDef {
+	if len(b.States) == 0 {
+		return DefaultStates
+	}
+	return b.States
 }
 
-func ValidateTaskState(state string) error {
-	if _, ok := allowedStates[state]; !ok {
-		return fmt.Errorf("%w: %s", ErrInvalidState, state)
+// ValidateTaskState reports whether state is one of the board's states.
+func (b *BoardState) ValidateTaskState(state string) error {
+	for _, def := range b.StateList() {
+		if def.ID == state {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidState, state)
+}
+
+// validateStateDefs checks a configured state list: at least one state, slug
+// IDs within MaxStateIDLength and no duplicates.
+func validateStateDefs(states []StateDef) error {
+	if len(states) == 0 {
+		return fmt.Errorf("%w: at least one state required", ErrInvalidRequest)
+	}
+	seen := make(map[string]struct{}, len(states))
+	for _, def := range states {
+		if len(def.ID) > MaxStateIDLength || !stateIDPattern.MatchString(def.ID) {
+			return fmt.Errorf("%w: state %q must be a lowercase slug of at most %d characters", ErrInvalidRequest, def.ID, MaxStateIDLength)
+		}
+		if _, dup := seen[def.ID]; dup {
+			return fmt.Errorf("%w: duplicate state %q", ErrInvalidRequest, def.ID)
+		}
+		seen[def.ID] = struct{}{}
+	}
+	return nil
+}
+
+// IsCompletedState reports whether a task in this state counts as finished
+// for review purposes.
+func IsCompletedState(state string) bool {
+	return state == "done" || state == "delegated"
+}
+
+// checkTransition reports whether transitions permit a task to move from one
+// state to another. Staying in the same state is always allowed.
+func checkTransition(transitions map[string][]string, from, to string) error {
+	if from == to {
+		return nil
+	}
+	allowed, ok := transitions[from]
+	if !ok {
+		return nil
+	}
+	for _, state := range allowed {
+		if state == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s -> %s (allowed from %s: %s)", ErrInvalidTransition, from, to, from, strings.Join(allowed, ", "))
+}
+
+// validateTransitions checks that a transitions map names only the board's
+// states.
+func (b *BoardState) validateTransitions(transitions map[string][]string) error {
+	for from, to := range transitions {
+		if err := b.ValidateTaskState(from); err != nil {
+			return fmt.Errorf("%w: transitions: %q is not a known state", ErrInvalidRequest, from)
+		}
+		for _, state := range to {
+			if err := b.ValidateTaskState(state); err != nil {
+				return fmt.Errorf("%w: transitions from %s: %q is not a known state", ErrInvalidRequest, from, state)
+			}
+		}
+	}
+	return nil
+}
+
+// stampCompletion records when a task entered a completed state and clears
+// the mark when it leaves one.
+func stampCompletion(task *Task, previousState string, now time.Time) {
+	switch {
+	case IsCompletedState(task.State) && (!IsCompletedState(previousState) || task.CompletedAt == nil):
+		completed := now
+		task.CompletedAt = &completed
+	case !IsCompletedState(task.State):
+		task.CompletedAt = nil
+	}
+}
+
+// DefaultSizeScale is the size scale used by boards that do not configure
+// their own.
+var DefaultSizeScale = []int{1, 2, 3, 4, 5}
+
+// Sizes returns the board's configured size scale, or DefaultSizeScale.
+func (b *BoardState) Sizes() []int {
+	if len(b.SizeScale) == 0 {
+		return DefaultSizeScale
+	}
+	return b.SizeScale
+}
+
+// ColumnLimit returns the board's per-category capacity.
+func (b *BoardState) ColumnLimit() int {
+	if b.Capacity == 0 {
+		return ColumnCapacity
+	}
+	return b.Capacity
+}
+
+// TaskLimit returns the task limit for cat: its own MaxTasks, else the
+// board default. Zero means no limit.
+func (b *BoardState) TaskLimit(cat Category) int {
+	if cat.MaxTasks > 0 {
+		return cat.MaxTasks
+	}
+	return b.MaxTasks
+}
+
+// forcedLimit returns how far a forced change may push a category.
+func (b *BoardState) forcedLimit() int {
+	if b.Capacity == 0 {
+		return MaxForcedCapacity
+	}
+	return 2 * b.Capacity
+}
+
+// NormalizeSize checks size against the board's size scale.
+func (b *BoardState) NormalizeSize(size int) (int, error) {
+	for _, allowed := range b.Sizes() {
+		if size == allowed {
+			return size, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %d (allowed: %s)", ErrInvalidTaskSize, size, joinInts(b.Sizes()))
+}
+
+// validateSizeScale checks a configured scale and capacity: positive sizes
+// in strictly ascending order, and a capacity that fits the smallest size.
+func validateSizeScale(scale []int, capacity int) error {
+	if len(scale) == 0 {
+		return fmt.Errorf("%w: size scale must not be empty", ErrInvalidRequest)
+	}
+	for i, size := range scale {
+		if size < 1 {
+			return fmt.Errorf("%w: sizes must be positive", ErrInvalidRequest)
+		}
+		if i > 0 && size <= scale[i-1] {
+			return fmt.Errorf("%w: sizes must be ascending without duplicates", ErrInvalidRequest)
+		}
+	}
+	if capacity < scale[0] {
+		return fmt.Errorf("%w: capacity %d is smaller than the smallest size", ErrInvalidRequest, capacity)
 	}
 	return nil
 }
 
-func NormalizeSize(size int) (int, error) {
-	if size < 1 || size > 5 {
-		return 0, ErrInvalidTaskSize
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
 	}
-	return size, nil
+	return strings.Join(parts, ", ")
 }
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StoreOption configures optional Store behavior.
+type StoreOption func(*Store)
+
+// WithEmptyReset makes ResetBoard clear the board instead of restoring the
+// demo seed.
+func WithEmptyReset(empty bool) StoreOption {
+	return func(s *Store) {
+		s.resetEmpty = empty
+	}
+}
+
+// WithNotesLimit caps task notes at max runes. Longer notes are rejected, or
+// truncated with an ellipsis when truncate is set. A max of zero disables the
+// limit.
+func WithNotesLimit(max int, truncate bool) StoreOption {
+	return func(s *Store) {
+		s.notes = notesLimit{max: max, truncate: truncate}
+	}
+}
+
+// WithClock replaces the clock used for task timestamps and day boundaries.
+func WithClock(now func() time.Time) StoreOption {
+	return func(s *Store) {
+		s.now = now
+	}
+}
+
+// WithLocation sets the timezone used for calendar-day calculations.
+func WithLocation(loc *time.Location) StoreOption {
+	return func(s *Store) {
+		s.location = loc
+	}
+}
+
+// WithMaxFileSize caps the serialized board at bytes. A board over the cap
+// fails to open, and a write that would take it over is refused and leaves
+// both the board and its file as they were. Zero disables the cap.
+func WithMaxFileSize(bytes int64) StoreOption {
+	return func(s *Store) {
+		s.maxFileBytes = bytes
+	}
+}
+
+// WithRestorePosition makes categories returning to the board without an
+// explicit position go back to the slot they left from, rather than the end.
+func WithRestorePosition(restore bool) StoreOption {
+	return func(s *Store) {
+		s.restorePosition = restore
+	}
+}
+
+// WithLinkSchemes replaces the URL schemes accepted for task links. The
+// default allows http and https.
+func WithLinkSchemes(schemes ...string) StoreOption {
+	return func(s *Store) {
+		s.linkSchemes = map[string]struct{}{}
+		for _, scheme := range schemes {
+			scheme = strings.ToLower(strings.TrimSpace(scheme))
+			if scheme != "" {
+				s.linkSchemes[scheme] = struct{}{}
+			}
+		}
+	}
+}
+
+// ServerOption configures optional Server behavior.
+type ServerOption func(*Server)
+
+// WithLenientJSON accepts request bodies carrying fields the server does not
+// know about, instead of rejecting them.
+func WithLenientJSON() ServerOption {
+	return func(s *Server) {
+		s.lenientJSON = true
+	}
+}
+
+// WithDestructive enables endpoints that irreversibly discard board data,
+// such as DELETE /board.
+func WithDestructive() ServerOption {
+	return func(s *Server) {
+		s.allowDestructive = true
+	}
+}
+
+// WithFileAdmin enables the admin endpoints that write or open board files
+// in the store's data directory: POST /admin/board/clone, /admin/open and
+// /admin/import/bundle.
+func WithFileAdmin() ServerOption {
+	return func(s *Server) {
+		s.allowFileAdmin = true
+	}
+}
+
+// WithHeartbeat sets how often the board event stream sends a keep-alive
+// comment. The default is DefaultHeartbeatInterval.
+func WithHeartbeat(interval time.Duration) ServerOption {
+	return func(s *Server) {
+		if interval > 0 {
+			s.heartbeat = interval
+		}
+	}
+}
+
+// WithWebhooks reports d's delivery status at /admin/webhooks. The caller
+// runs d.
+func WithWebhooks(d *WebhookDispatcher) ServerOption {
+	return func(s *Server) {
+		s.webhooks = d
+	}
+}
+
+// WithAPIMiddleware wraps the API routes, leaving the SPA handler untouched.
+func WithAPIMiddleware(mw func(http.Handler) http.Handler) ServerOption {
+	return func(s *Server) {
+		s.api = mw(s.api)
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import "time"
+
+// snapshotLocked returns a copy of the board with computed, non-persisted
+// fields filled in for clients. Callers must hold s.mu.
+func (s *Store) snapshotLocked() BoardState {
+	board := s.state.Clone()
+	projectBoard(&board, s.now())
+	return board
+}
+
+func projectBoard(board *BoardState, now time.Time) {
+	board.States = append([]StateDef{}, board.StateList()...)
+	board.SizeScale = append([]int{}, board.Sizes()...)
+	board.Capacity = board.ColumnLimit()
+	for i := range board.Categories {
+		if board.Categories[i].Tasks == nil {
+			// compacted boards keep no list for an empty category
+			board.Categories[i].Tasks = []Task{}
+		}
+		board.Categories[i].OverCapacity = categorySize(board.Categories[i]) > board.ColumnLimit()
+		board.Categories[i].TaskCount = len(board.Categories[i].Tasks)
+		board.Categories[i].TaskLimit = board.TaskLimit(board.Categories[i])
+		board.Categories[i].HasFocus = false
+		for _, task := range board.Categories[i].Tasks {
+			if task.Focused {
+				board.Categories[i].HasFocus = true
+				break
+			}
+		}
+	}
+	forEachPoolTask(board, func(task *Task, active bool) {
+		task.AgeDays = daysSince(task.CreatedAt, now)
+		task.DaysInState = daysSince(stateEnteredAt(*task), now)
+		task.Stale = active && board.isStale(*task, now)
+	})
+}
+
+// isStale reports whether an active task has been doing or blocked for at
+// least the board's StaleAfterDays.
+func (board *BoardState) isStale(task Task, now time.Time) bool {
+	if board.StaleAfterDays <= 0 || (task.State != "doing" && task.State != "blocked") {
+		return false
+	}
+	return daysSince(stateEnteredAt(task), now) >= board.StaleAfterDays
+}
+
+// stateEnteredAt is when the task last changed state according to its
+// history, or its creation time if it never has.
+func stateEnteredAt(task Task) time.Time {
+	for i := len(task.History) - 1; i >= 0; i-- {
+		if task.History[i].Action == "state" {
+			return task.History[i].At
+		}
+	}
+	return task.CreatedAt
+}
+
+// daysSince counts whole days from t to now; zero times count as zero.
+func daysSince(t, now time.Time) int {
+	if t.IsZero() || !now.After(t) {
+		return 0
+	}
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// forEachPoolTask visits every task on the board, including those inside
+// backburnered and archived categories. active is set for tasks in active
+// categories.
+func forEachPoolTask(board *BoardState, fn func(task *Task, active bool)) {
+	for ci, pool := range [][]Category{board.Categories, board.CategoryBackburner, board.CategoryArchives} {
+		for i := range pool {
+			for j := range pool[i].Tasks {
+				fn(&pool[i].Tasks[j], ci == 0)
+			}
+		}
+	}
+	for _, tasks := range [][]Task{board.Backburner, board.Archives, board.Inbox} {
+		for i := range tasks {
+			fn(&tasks[i], false)
+		}
+	}
+}
+
+// clearProjection strips computed fields so they are never persisted.
+func clearProjection(board *BoardState) {
+	for _, pool := range [][]Category{board.Categories, board.CategoryBackburner, board.CategoryArchives} {
+		for i := range pool {
+			pool[i].HasFocus = false
+			pool[i].OverCapacity = false
+			pool[i].TaskCount = 0
+			pool[i].TaskLimit = 0
+		}
+	}
+	forEachPoolTask(board, func(task *Task, _ bool) {
+		task.AgeDays = 0
+		task.DaysInState = 0
+		task.Stale = false
+	})
+}
+
+// overCapacityIDs lists the active categories flagged OverCapacity.
+func overCapacityIDs(board BoardState) []string {
+	ids := []string{}
+	for _, cat := range board.Categories {
+		if cat.OverCapacity {
+			ids = append(ids, cat.ID)
+		}
+	}
+	return ids
+}
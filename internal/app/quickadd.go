@@ -0,0 +1,221 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuickAddRequest carries one line of command-palette text, such as
+// "Email accountant about Q3 #finance !urgent ~2 @Build".
+type QuickAddRequest struct {
+	Text string `json:"text"`
+}
+
+// QuickAddParse is what the server understood from a quick-add line, so
+// clients can show it back. Category is the @ token as typed; CategoryID and
+// CategoryName are the active category the task went to.
+type QuickAddParse struct {
+	Name         string   `json:"name"`
+	Tags         []string `json:"tags"`
+	Urgent       bool     `json:"urgent"`
+	Size         int      `json:"size"`
+	Category     string   `json:"category,omitempty"`
+	CategoryID   string   `json:"categoryId"`
+	CategoryName string   `json:"categoryName"`
+	// DueDate is read from a due: token, as in "due:friday 5pm".
+	DueDate *time.Time `json:"dueDate,omitempty"`
+}
+
+// CategoryRef names an active category.
+type CategoryRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AmbiguousCategoryError is returned when a category query matches more
+// than one active category, listing them so the client can ask which.
+type AmbiguousCategoryError struct {
+	Query      string
+	Candidates []CategoryRef
+}
+
+func (e *AmbiguousCategoryError) Error() string {
+	return fmt.Sprintf("%v: category %q matches more than one category", ErrInvalidRequest, e.Query)
+}
+
+func (e *AmbiguousCategoryError) Unwrap() error {
+	return ErrInvalidRequest
+}
+
+// parseQuickAdd splits a quick-add line into its parts: #tag adds a tag,
+// !urgent marks the task urgent, ~N sets the size, @name picks the category
+// and due: starts a due date read against now in loc. Every other word
+// belongs to the name. Size is zero when no ~ was given.
+func parseQuickAdd(text string, now time.Time, loc *time.Location) (QuickAddParse, error) {
+	parsed := QuickAddParse{Tags: []string{}}
+	name := []string{}
+	// due holds the words after due: until the next token; the longest run
+	// of them that reads as a date is the due date and the rest is name
+	var due []string
+	inDue := false
+	endDue := func() error {
+		if !inDue {
+			return nil
+		}
+		inDue = false
+		for n := len(due); n > 0; n-- {
+			at, err := parseDueDate(strings.Join(due[:n], " "), now, loc)
+			if err == nil {
+				parsed.DueDate = &at
+				name = append(name, due[n:]...)
+				return nil
+			}
+		}
+		return &DueDateError{Text: strings.Join(due, " ")}
+	}
+	for _, word := range strings.Fields(text) {
+		lower := strings.ToLower(word)
+		if inDue && !strings.ContainsAny(word[:1], "#~@!") && !strings.HasPrefix(lower, "due:") {
+			due = append(due, word)
+			continue
+		}
+		if err := endDue(); err != nil {
+			return QuickAddParse{}, err
+		}
+		switch {
+		case strings.HasPrefix(lower, "due:"):
+			if parsed.DueDate != nil {
+				return QuickAddParse{}, fmt.Errorf("%w: more than one due: given", ErrInvalidRequest)
+			}
+			inDue = true
+			due = nil
+			if rest := word[len("due:"):]; rest != "" {
+				due = append(due, rest)
+			}
+		case strings.HasPrefix(word, "#") && len(word) > 1:
+			if !hasTag(parsed.Tags, word[1:]) {
+				parsed.Tags = append(parsed.Tags, word[1:])
+			}
+		case lower == "!urgent":
+			parsed.Urgent = true
+		case strings.HasPrefix(word, "~") && len(word) > 1:
+			size, err := strconv.Atoi(word[1:])
+			if err != nil {
+				return QuickAddParse{}, fmt.Errorf("%w: size %q is not a number", ErrInvalidRequest, word)
+			}
+			parsed.Size = size
+		case strings.HasPrefix(word, "@") && len(word) > 1:
+			if parsed.Category != "" {
+				return QuickAddParse{}, fmt.Errorf("%w: more than one @category given", ErrInvalidRequest)
+			}
+			parsed.Category = word[1:]
+		default:
+			name = append(name, word)
+		}
+	}
+	if err := endDue(); err != nil {
+		return QuickAddParse{}, err
+	}
+	parsed.Name = strings.Join(name, " ")
+	if parsed.Name == "" {
+		return QuickAddParse{}, fmt.Errorf("%w: quick add needs a task name", ErrInvalidRequest)
+	}
+	return parsed, nil
+}
+
+// matchCategory finds the active category query names: an exact,
+// case-insensitive name match wins, otherwise the query must be the prefix
+// of exactly one name.
+func matchCategory(categories []Category, query string) (Category, error) {
+	var prefixed []Category
+	for _, cat := range categories {
+		if strings.EqualFold(cat.Name, query) {
+			return cat, nil
+		}
+		if strings.HasPrefix(strings.ToLower(cat.Name), strings.ToLower(query)) {
+			prefixed = append(prefixed, cat)
+		}
+	}
+	switch len(prefixed) {
+	case 0:
+		return Category{}, fmt.Errorf("%w: no active category matches %q", ErrCategoryNotFound, query)
+	case 1:
+		return prefixed[0], nil
+	}
+	candidates := make([]CategoryRef, len(prefixed))
+	for i, cat := range prefixed {
+		candidates[i] = CategoryRef{ID: cat.ID, Name: cat.Name}
+	}
+	return Category{}, &AmbiguousCategoryError{Query: query, Candidates: candidates}
+}
+
+// QuickAddTask creates a task from a quick-add line. Without an @category
+// the task goes to the first active category with room for it.
+func (s *Store) QuickAddTask(text, actor string) (Task, QuickAddParse, BoardState, error) {
+	parsed, err := parseQuickAdd(text, s.now(), s.location)
+	if err != nil {
+		return Task{}, QuickAddParse{}, BoardState{}, err
+	}
+
+	s.mu.RLock()
+	state := s.state.Clone()
+	s.mu.RUnlock()
+	if parsed.Size == 0 {
+		parsed.Size = state.Sizes()[0]
+	}
+	if parsed.Category != "" {
+		cat, err := matchCategory(state.Categories, parsed.Category)
+		if err != nil {
+			return Task{}, QuickAddParse{}, BoardState{}, err
+		}
+		parsed.CategoryID, parsed.CategoryName = cat.ID, cat.Name
+	} else {
+		room := state.roomFor(parsed.Size, "")
+		if len(room) == 0 {
+			return Task{}, QuickAddParse{}, BoardState{}, fmt.Errorf("%w: no active category has room for size %d", ErrCapacityExceeded, parsed.Size)
+		}
+		parsed.CategoryID, parsed.CategoryName = room[0].CategoryID, room[0].Name
+	}
+
+	task, board, err := s.CreateTask(CreateTaskRequest{
+		Location:   LocationCategory,
+		CategoryID: parsed.CategoryID,
+		Task: Task{
+			Name:    parsed.Name,
+			State:   state.StateList()[0].ID,
+			Size:    parsed.Size,
+			Tags:    parsed.Tags,
+			Urgent:  parsed.Urgent,
+			DueDate: parsed.DueDate,
+		},
+		Actor: actor,
+	})
+	if err != nil {
+		return Task{}, QuickAddParse{}, BoardState{}, err
+	}
+	return task, parsed, board, nil
+}
+
+func (s *Server) handleQuickAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req QuickAddRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	task, parsed, board, err := s.store.QuickAddTask(req.Text, actorFromRequest(r))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusCreated, map[string]any{
+		"task":   task,
+		"parsed": parsed,
+	}, board)
+}
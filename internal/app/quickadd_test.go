@@ -0,0 +1,135 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const quickBoard = `{
+	"categories": [
+		{"id":"full","name":"Backlog","tasks":[
+			{"id":"t1","name":"One","state":"todo","size":5}
+		]},
+		{"id":"build","name":"Build","tasks":[]},
+		{"id":"bugs","name":"Bugs","tasks":[]},
+		{"id":"fin","name":"Finance","tasks":[]}
+	]
+}`
+
+func TestParseQuickAdd(t *testing.T) {
+	parsed, err := parseQuickAdd("Email accountant about Q3 #finance !urgent ~2 @Build #finance", time.Now(), time.UTC)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := QuickAddParse{Name: "Email accountant about Q3", Tags: []string{"finance"}, Urgent: true, Size: 2, Category: "Build"}
+	if !reflect.DeepEqual(parsed, want) {
+		t.Fatalf("expected %+v, got %+v", want, parsed)
+	}
+
+	for _, text := range []string{"#only #tags", "Two @Build @Bugs", "Sized ~big", "   ", "Bad date due:someday", "Two dates due:today due:tomorrow"} {
+		if _, err := parseQuickAdd(text, time.Now(), time.UTC); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%q: expected ErrInvalidRequest, got %v", text, err)
+		}
+	}
+}
+
+func TestParseQuickAddDueDate(t *testing.T) {
+	now := fixedClock("2025-03-12T15:00:00Z")() // a Wednesday
+	parsed, err := parseQuickAdd("Ship release due:next friday 5pm notes #ops", now, time.UTC)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.Name != "Ship release notes" || !reflect.DeepEqual(parsed.Tags, []string{"ops"}) {
+		t.Fatalf("expected the words after the date back in the name, got %+v", parsed)
+	}
+	if want := "2025-03-21T17:00:00Z"; parsed.DueDate == nil || parsed.DueDate.Format(time.RFC3339) != want {
+		t.Fatalf("expected due %s, got %v", want, parsed.DueDate)
+	}
+
+	parsed, err = parseQuickAdd("Call bank due: tomorrow", now, time.UTC)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.Name != "Call bank" || parsed.DueDate == nil || parsed.DueDate.Format(time.DateOnly) != "2025-03-13" {
+		t.Fatalf("unexpected parse %+v", parsed)
+	}
+
+	_, err = parseQuickAdd("Sometime due:whenever", now, time.UTC)
+	var dueErr *DueDateError
+	if !errors.As(err, &dueErr) || dueErr.Text != "whenever" {
+		t.Fatalf("expected a DueDateError for the due text, got %v", err)
+	}
+}
+
+func TestQuickAddTask(t *testing.T) {
+	store := newTestStore(t, quickBoard)
+	task, parsed, board, err := store.QuickAddTask("Pay invoice #finance !urgent ~2 @fin", "sam")
+	if err != nil {
+		t.Fatalf("quick add: %v", err)
+	}
+	if parsed.CategoryID != "fin" || parsed.CategoryName != "Finance" {
+		t.Fatalf("expected the @ prefix to pick Finance, got %+v", parsed)
+	}
+	if task.Name != "Pay invoice" || task.Size != 2 || !task.Urgent || !reflect.DeepEqual(task.Tags, []string{"finance"}) {
+		t.Fatalf("unexpected task %+v", task)
+	}
+	if got := board.Categories[3].Tasks; len(got) != 1 || got[0].ID != task.ID {
+		t.Fatalf("expected the task in Finance, got %+v", got)
+	}
+
+	// Backlog is full, so a task without @ lands in the next category with room
+	_, parsed, _, err = store.QuickAddTask("Triage later", "")
+	if err != nil {
+		t.Fatalf("quick add: %v", err)
+	}
+	if parsed.CategoryID != "build" || parsed.Size != 1 {
+		t.Fatalf("expected the first category with room and the smallest size, got %+v", parsed)
+	}
+
+	if _, _, _, err := store.QuickAddTask("Nowhere @Ops", ""); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestQuickAddEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, quickBoard))
+	rec := doRequest(t, srv, http.MethodPost, "/api/tasks/quick", `{"text":"Fix login ~3 @build"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		Task   Task          `json:"task"`
+		Parsed QuickAddParse `json:"parsed"`
+	}
+	decodeBody(t, rec, &created)
+	if created.Task.Name != "Fix login" || created.Parsed.CategoryName != "Build" || created.Parsed.Size != 3 || created.Task.DueDate != nil {
+		t.Fatalf("unexpected response %+v", created)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/tasks/quick", `{"text":"Fix login @Bu"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an ambiguous category, got %d", rec.Code)
+	}
+	var ambiguous struct {
+		Candidates []CategoryRef `json:"candidates"`
+	}
+	decodeBody(t, rec, &ambiguous)
+	if want := []CategoryRef{{ID: "build", Name: "Build"}, {ID: "bugs", Name: "Bugs"}}; !reflect.DeepEqual(ambiguous.Candidates, want) {
+		t.Fatalf("expected candidates %v, got %v", want, ambiguous.Candidates)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/tasks/quick", `{"text":"Fix login due:someday"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unreadable due date, got %d", rec.Code)
+	}
+	var bad struct {
+		Text string `json:"text"`
+	}
+	decodeBody(t, rec, &bad)
+	if bad.Text != "someday" {
+		t.Fatalf("expected the due text echoed back, got %q", bad.Text)
+	}
+}
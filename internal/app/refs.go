@@ -0,0 +1,103 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TaskRefPrefix starts every short task reference, as in "T-12".
+const TaskRefPrefix = "T-"
+
+func formatTaskRef(seq int) string {
+	return TaskRefPrefix + strconv.Itoa(seq)
+}
+
+// parseTaskRef reads a short reference such as "T-12" or "t-12" into its
+// sequence number.
+func parseTaskRef(ref string) (int, bool) {
+	if len(ref) <= len(TaskRefPrefix) || !strings.EqualFold(ref[:len(TaskRefPrefix)], TaskRefPrefix) {
+		return 0, false
+	}
+	seq, err := strconv.Atoi(ref[len(TaskRefPrefix):])
+	if err != nil || seq < 1 || strconv.Itoa(seq) != ref[len(TaskRefPrefix):] {
+		return 0, false
+	}
+	return seq, true
+}
+
+// numberTasks gives every task without a usable reference the next number
+// in the board's sequence, in board order. References are kept as loaded,
+// so a board read from a file or imported whole keeps its numbering; a
+// reference already taken by an earlier task is replaced, and LastTaskSeq
+// is raised past every number in use so none is handed out twice.
+func numberTasks(state *BoardState) {
+	for _, assign := range []bool{false, true} {
+		seen := map[int]struct{}{}
+		forEachPoolTask(state, func(task *Task, _ bool) {
+			seq, ok := parseTaskRef(task.Ref)
+			if _, dup := seen[seq]; ok && !dup {
+				seen[seq] = struct{}{}
+				task.Ref = formatTaskRef(seq)
+				if seq > state.LastTaskSeq {
+					state.LastTaskSeq = seq
+				}
+				return
+			}
+			if assign {
+				state.LastTaskSeq++
+				task.Ref = formatTaskRef(state.LastTaskSeq)
+			}
+		})
+	}
+}
+
+// replaceBoard swaps next in for state, keeping the larger LastTaskSeq of the
+// two so numbers handed out before the swap are never given to other tasks.
+func replaceBoard(state *BoardState, next BoardState) {
+	if state.LastTaskSeq > next.LastTaskSeq {
+		next.LastTaskSeq = state.LastTaskSeq
+	}
+	*state = next
+}
+
+// clearTaskRefs drops the references of tasks about to join another board,
+// whose sequence their numbers do not belong to.
+func clearTaskRefs(board *BoardState) {
+	forEachPoolTask(board, func(task *Task, _ bool) {
+		task.Ref = ""
+	})
+}
+
+// ResolveTaskRef returns the ID of the task named by ref, which may be a task
+// ID or a short reference such as "T-12". A task whose ID is ref wins over a
+// reference, and a ref naming no task comes back unchanged so callers report
+// it as they would an unknown ID.
+func (s *Store) ResolveTaskRef(ref string) string {
+	seq, ok := parseTaskRef(ref)
+	if !ok {
+		return ref
+	}
+	want := formatTaskRef(seq)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	exact, id := false, ""
+	forEachPoolTask(&s.state, func(task *Task, _ bool) {
+		if task.ID == ref {
+			exact = true
+		}
+		if task.Ref == want && id == "" {
+			id = task.ID
+		}
+	})
+	if exact || id == "" {
+		return ref
+	}
+	return id
+}
+
+// ResolveTaskRefs resolves each entry of refs in place.
+func (s *Store) ResolveTaskRefs(refs []string) {
+	for i, ref := range refs {
+		refs[i] = s.ResolveTaskRef(ref)
+	}
+}
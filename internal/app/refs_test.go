@@ -0,0 +1,200 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+)
+
+// refOf returns the reference of task id on board, or "" when it is absent.
+func refOf(board BoardState, id string) string {
+	ref := ""
+	forEachPoolTask(&board, func(task *Task, _ bool) {
+		if task.ID == id {
+			ref = task.Ref
+		}
+	})
+	return ref
+}
+
+func TestParseTaskRef(t *testing.T) {
+	for ref, want := range map[string]int{"T-1": 1, "t-42": 42, "T-0": 0, "T-": 0, "T-01": 0, "T-x": 0, "X-1": 0, "12": 0} {
+		seq, ok := parseTaskRef(ref)
+		if ok != (want > 0) || seq != want {
+			t.Errorf("%q: expected %d, got %d (%v)", ref, want, seq, ok)
+		}
+	}
+}
+
+func TestTaskRefsNumberedInOrder(t *testing.T) {
+	// bulkBoard predates refs, so its tasks are numbered in board order on load
+	store := newTestStore(t, bulkBoard)
+	board := store.GetState()
+	for id, want := range map[string]string{"t1": "T-1", "t2": "T-2", "t3": "T-3", "t4": "T-4"} {
+		if got := refOf(board, id); got != want {
+			t.Fatalf("expected %s to be %s, got %q", id, want, got)
+		}
+	}
+
+	created, _, err := store.CreateTask(CreateTaskRequest{CategoryID: "cat2", Task: Task{Name: "Five", State: "todo", Size: 1, Ref: "T-1"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.Ref != "T-5" {
+		t.Fatalf("expected the next number whatever the body says, got %q", created.Ref)
+	}
+
+	// deleting the newest task does not free its number, across a restart too
+	if _, _, err := store.MoveTask(created.ID, MoveTaskRequest{Location: LocationArchive}); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if _, err := store.DeleteTask(created.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	reopened, err := NewStore(store.path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	created, _, err = reopened.CreateTask(CreateTaskRequest{Location: LocationBackburner, Task: Task{Name: "Six", State: "todo", Size: 1}})
+	if err != nil {
+		t.Fatalf("create after reopen: %v", err)
+	}
+	if created.Ref != "T-6" {
+		t.Fatalf("expected T-6 after the deleted T-5, got %q", created.Ref)
+	}
+	if got := refOf(reopened.GetState(), "t1"); got != "T-1" {
+		t.Fatalf("expected refs kept across the restart, got %q", got)
+	}
+}
+
+func TestNumberTasksRepairsRefs(t *testing.T) {
+	board := decodeBoard(t, `{
+		"categories": [
+			{"id":"cat1","name":"Alpha","tasks":[
+				{"id":"a","name":"A","state":"todo","size":1,"ref":"t-7"},
+				{"id":"b","name":"B","state":"todo","size":1,"ref":"T-7"},
+				{"id":"c","name":"C","state":"todo","size":1,"ref":"bogus"}
+			]}
+		],
+		"lastTaskSeq": 3
+	}`)
+	numberTasks(&board)
+	for id, want := range map[string]string{"a": "T-7", "b": "T-8", "c": "T-9"} {
+		if got := refOf(board, id); got != want {
+			t.Errorf("expected %s to be %s, got %q", id, want, got)
+		}
+	}
+	if board.LastTaskSeq != 9 {
+		t.Fatalf("expected the counter raised to 9, got %d", board.LastTaskSeq)
+	}
+}
+
+func TestResolveTaskRef(t *testing.T) {
+	store := newTestStore(t, `{
+		"categories": [
+			{"id":"cat1","name":"Alpha","tasks":[
+				{"id":"x1","name":"One","state":"todo","size":1,"ref":"T-1"},
+				{"id":"T-1","name":"Named like a ref","state":"todo","size":1,"ref":"T-2"}
+			]}
+		]
+	}`)
+	for ref, want := range map[string]string{
+		"T-2": "T-1", // a ref resolves to its task's ID
+		"t-2": "T-1",
+		"T-1": "T-1", // an exact ID wins over the ref of another task
+		"x1":  "x1",
+		"T-9": "T-9", // unknown refs come back as given
+	} {
+		if got := store.ResolveTaskRef(ref); got != want {
+			t.Errorf("%q: expected %q, got %q", ref, want, got)
+		}
+	}
+}
+
+func TestImportTaskRefs(t *testing.T) {
+	incoming := `{
+		"categories": [
+			{"id":"cat9","name":"Gamma","tasks":[
+				{"id":"g1","name":"G1","state":"todo","size":1,"ref":"T-1"}
+			]}
+		],
+		"lastTaskSeq": 1
+	}`
+
+	store := newTestStore(t, bulkBoard)
+	_, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if got, own := refOf(board, "g1"), refOf(board, "t1"); got != "T-5" || own != "T-1" {
+		t.Fatalf("expected merged tasks numbered after the board's own, got g1=%q t1=%q", got, own)
+	}
+
+	_, board, err = store.Import(ImportRequest{Mode: ImportModeReplace, Board: decodeBoard(t, incoming)})
+	if err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	// the counter stays past the numbers the replaced board handed out
+	if got := refOf(board, "g1"); got != "T-1" || board.LastTaskSeq != 5 {
+		t.Fatalf("expected a replace import to keep its numbering and the counter, got %q (counter %d)", got, board.LastTaskSeq)
+	}
+}
+
+func TestReplacedBoardKeepsTaskSeq(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, err := store.Wipe(); err != nil {
+		t.Fatalf("wipe: %v", err)
+	}
+	created, _, err := store.CreateTask(CreateTaskRequest{Location: LocationBackburner, Task: Task{Name: "Five", State: "todo", Size: 1}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.Ref != "T-5" {
+		t.Fatalf("expected numbering to carry on after a wipe, got %q", created.Ref)
+	}
+
+	board, err := store.ResetBoard()
+	if err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if got := board.Categories[0].Tasks[0].Ref; got != "T-6" {
+		t.Fatalf("expected the seed numbered after T-5, got %q", got)
+	}
+}
+
+func TestTaskRefsInAPI(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+
+	rec := doRequest(t, srv, http.MethodPatch, "/api/tasks/T-3", `{"name":"Third","blockedBy":["t-1"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Task.ID != "t3" || resp.Task.Ref != "T-3" || resp.Task.Name != "Third" {
+		t.Fatalf("expected t3 patched through its ref, got %+v", resp.Task)
+	}
+	if len(resp.Task.BlockedBy) != 1 || resp.Task.BlockedBy[0] != "t1" {
+		t.Fatalf("expected blockedBy stored as IDs, got %v", resp.Task.BlockedBy)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/board/focus", `{"taskId":"T-2"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Task.ID != "t2" || !resp.Task.Focused {
+		t.Fatalf("expected t2 focused through its ref, got %+v", resp.Task)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/tasks/T-4/move", `{"location":"backburner"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = doRequest(t, srv, http.MethodDelete, "/api/tasks/T-99", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown ref, got %d", rec.Code)
+	}
+}
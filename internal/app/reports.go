@@ -0,0 +1,88 @@
+package app
+
+import "net/http"
+
+// ReadinessReport checks the work in play before a sprint starts: the open
+// tasks in active categories and the inbox. Completed tasks are left out.
+type ReadinessReport struct {
+	// UnsizedTasks have no size, which only a board edited by hand or a
+	// changed default can produce.
+	UnsizedTasks     []Task `json:"unsizedTasks"`
+	UndescribedTasks []Task `json:"undescribedTasks"`
+	// UnassignedTasks sit in the inbox, not yet triaged into a category.
+	UnassignedTasks []Task `json:"unassignedTasks"`
+	BlockedTasks    []Task `json:"blockedTasks"`
+	// OversizedCategories lists the IDs of active categories over their
+	// capacity or task limit.
+	OversizedCategories []string `json:"oversizedCategories"`
+	// TotalReadinessScore is the percentage of tasks that are sized,
+	// described, assigned and not blocked; 100 when there are no tasks.
+	TotalReadinessScore float64 `json:"totalReadinessScore"`
+}
+
+// GetReadinessReport computes a ReadinessReport from the current state.
+func (s *Store) GetReadinessReport() ReadinessReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	report := ReadinessReport{
+		UnsizedTasks:        []Task{},
+		UndescribedTasks:    []Task{},
+		UnassignedTasks:     []Task{},
+		BlockedTasks:        []Task{},
+		OversizedCategories: []string{},
+	}
+	total, ready := 0, 0
+	check := func(task Task, assigned bool) {
+		if IsCompletedState(task.State) {
+			return
+		}
+		total++
+		isReady := true
+		if task.Size == 0 {
+			report.UnsizedTasks = append(report.UnsizedTasks, task.Clone())
+			isReady = false
+		}
+		if task.Description == "" {
+			report.UndescribedTasks = append(report.UndescribedTasks, task.Clone())
+			isReady = false
+		}
+		if !assigned {
+			report.UnassignedTasks = append(report.UnassignedTasks, task.Clone())
+			isReady = false
+		}
+		if task.State == "blocked" {
+			report.BlockedTasks = append(report.BlockedTasks, task.Clone())
+			isReady = false
+		}
+		if isReady {
+			ready++
+		}
+	}
+	for _, cat := range s.state.Categories {
+		limit := s.state.TaskLimit(cat)
+		if categorySize(cat) > s.state.ColumnLimit() || (limit > 0 && len(cat.Tasks) > limit) {
+			report.OversizedCategories = append(report.OversizedCategories, cat.ID)
+		}
+		for _, task := range cat.Tasks {
+			check(task, true)
+		}
+	}
+	for _, task := range s.state.Inbox {
+		check(task, false)
+	}
+
+	report.TotalReadinessScore = 100
+	if total > 0 {
+		report.TotalReadinessScore = float64(ready) / float64(total) * 100
+	}
+	return report
+}
+
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.store.GetReadinessReport())
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const readinessBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"ready","name":"Ready","description":"All set","state":"todo","size":1},
+			{"id":"bare","name":"Bare","state":"todo","size":1},
+			{"id":"stuck","name":"Stuck","description":"Waiting","state":"blocked","blockedReason":"vendor","size":1},
+			{"id":"shipped","name":"Shipped","state":"done","size":1}
+		]},
+		{"id":"cat2","name":"Beta","tasks":[
+			{"id":"big","name":"Big","description":"Large","state":"doing","size":4},
+			{"id":"extra","name":"Extra","description":"Overflow","state":"todo","size":2},
+			{"id":"unsized","name":"Unsized","description":"Hand edited","state":"todo","size":1}
+		]}
+	],
+	"inbox": [
+		{"id":"new","name":"New","description":"Triage me","state":"todo","size":1}
+	],
+	"backburner": [
+		{"id":"later","name":"Later","state":"todo","size":1}
+	],
+	"capacityMode": "soft"
+}`
+
+func readinessIDs(tasks []Task) []string {
+	ids := []string{}
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func TestGetReadinessReport(t *testing.T) {
+	store := newTestStore(t, readinessBoard)
+	// sizes below the scale cannot be loaded, so unsize one by hand
+	store.state.Categories[1].Tasks[2].Size = 0
+
+	report := store.GetReadinessReport()
+	for name, tc := range map[string]struct{ got, want []string }{
+		"unsized":     {readinessIDs(report.UnsizedTasks), []string{"unsized"}},
+		"undescribed": {readinessIDs(report.UndescribedTasks), []string{"bare"}},
+		"unassigned":  {readinessIDs(report.UnassignedTasks), []string{"new"}},
+		"blocked":     {readinessIDs(report.BlockedTasks), []string{"stuck"}},
+		"oversized":   {report.OversizedCategories, []string{"cat2"}},
+	} {
+		if !reflect.DeepEqual(tc.got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, tc.got)
+		}
+	}
+	// ready, big and extra are ready out of seven open tasks; done and
+	// backburnered tasks do not count
+	if want := float64(3) / float64(7) * 100; report.TotalReadinessScore != want {
+		t.Fatalf("expected score %v, got %v", want, report.TotalReadinessScore)
+	}
+}
+
+func TestGetReadinessReportEmptyBoard(t *testing.T) {
+	report := newTestStore(t, `{"categories":[]}`).GetReadinessReport()
+	if report.TotalReadinessScore != 100 || report.UnsizedTasks == nil || report.OversizedCategories == nil {
+		t.Fatalf("expected a full score and empty lists, got %+v", report)
+	}
+}
+
+func TestReadinessEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, readinessBoard))
+	rec := doRequest(t, srv, http.MethodGet, "/api/board/readiness", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var report ReadinessReport
+	decodeBody(t, rec, &report)
+	if len(report.BlockedTasks) != 1 || report.BlockedTasks[0].ID != "stuck" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if rec := doRequest(t, srv, http.MethodPost, "/api/board/readiness", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
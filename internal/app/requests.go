@@ -1,25 +1,61 @@
 package app
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
 
 type CreateTaskRequest struct {
 	Location   string `json:"location"`
 	CategoryID string `json:"categoryId,omitempty"`
 	Position   *int   `json:"position,omitempty"`
 	Task       Task   `json:"task"`
+	// Force allows this one create to exceed the board's capacity, up to
+	// twice that.
+	Force bool `json:"force,omitempty"`
+	// Actor is taken from the X-Actor header rather than the request body.
+	Actor string `json:"-"`
+}
+
+// CreateTasksRequest creates several tasks in one location. With a
+// Position the tasks are inserted there in order; otherwise they are
+// appended.
+type CreateTasksRequest struct {
+	Location   string `json:"location"`
+	CategoryID string `json:"categoryId,omitempty"`
+	Position   *int   `json:"position,omitempty"`
+	Tasks      []Task `json:"tasks"`
+	// Force lets the batch exceed the board's capacity, up to twice that.
+	Force bool   `json:"force,omitempty"`
+	Actor string `json:"-"`
 }
 
 func (r *CreateTaskRequest) Normalize() {
 	if r.Location == "" {
 		r.Location = LocationCategory
 	}
+	r.Task.BlockedReason = strings.TrimSpace(r.Task.BlockedReason)
+	if r.Task.State != "blocked" {
+		r.Task.BlockedReason = ""
+	}
+	r.Task.DelegatedTo = strings.TrimSpace(r.Task.DelegatedTo)
+	if r.Task.State != "delegated" {
+		r.Task.DelegatedTo = ""
+	}
 }
 
 func (r CreateTaskRequest) Validate() error {
-	if err := ValidateTaskState(r.Task.State); err != nil {
-		return err
+	// the board's scale is checked on insert; this only rejects sizes no
+	// scale can hold
+	if r.Task.Size < 1 {
+		return fmt.Errorf("%w: %d", ErrInvalidTaskSize, r.Task.Size)
 	}
-	if _, err := NormalizeSize(r.Task.Size); err != nil {
+	// a new task has no prior state, so entering blocked or delegated counts
+	// as a transition
+	if err := checkStateDetails(&r.Task, ""); err != nil {
 		return err
 	}
 	switch r.Location {
@@ -27,7 +63,7 @@ func (r CreateTaskRequest) Validate() error {
 		if r.CategoryID == "" {
 			return fmt.Errorf("%w: categoryId required for category location", ErrInvalidRequest)
 		}
-	case LocationBackburner, LocationArchive:
+	case LocationBackburner, LocationArchive, LocationInbox:
 	default:
 		return ErrInvalidLocation
 	}
@@ -42,10 +78,152 @@ type TaskPatch struct {
     Size        *int        `json:"size,omitempty"`
     Links       *[]TaskLink `json:"links,omitempty"`
     Checklist   *[]ChecklistItem `json:"checklist,omitempty"`
+    Tags        *[]string   `json:"tags,omitempty"`
+    BlockedReason *string   `json:"blockedReason,omitempty"`
+    BlockedBy   *[]string   `json:"blockedBy,omitempty"`
+    DelegatedTo *string     `json:"delegatedTo,omitempty"`
     Urgent      *bool       `json:"urgent,omitempty"`
+    DueDate     *time.Time  `json:"dueDate,omitempty"`
+    // DueDateText sets DueDate from text such as "friday 5pm" or "in 2
+    // weeks", read in the board's timezone; an empty string clears it.
+    DueDateText *string     `json:"dueDateText,omitempty"`
+    // ClearFields names fields to reset to empty, since an omitted key and
+    // a JSON null both decode as "leave alone". Clears run after the fields
+    // above are set, so a field named in both ends up empty. Clearing
+    // checklist, links, tags or blockedBy drops the list from the task, and
+    // clearing urgent unmarks the task without marking another.
+    ClearFields []string    `json:"clearFields,omitempty"`
+    // ExpectedName, ExpectedState, ExpectedSize and ExpectedUrgent must
+    // match the task as it stands or nothing is changed, so a client does
+    // not overwrite an edit it has not seen.
+    ExpectedName   *string  `json:"expectedName,omitempty"`
+    ExpectedState  *string  `json:"expectedState,omitempty"`
+    ExpectedSize   *int     `json:"expectedSize,omitempty"`
+    ExpectedUrgent *bool    `json:"expectedUrgent,omitempty"`
+    // Actor is taken from the X-Actor header rather than the request body.
+    Actor       string      `json:"-"`
+}
+
+// PatchConflictError is ErrPatchConflict naming the first field whose
+// current value differs from the patch's expectation.
+type PatchConflictError struct {
+	Field    string
+	Expected any
+	Actual   any
+}
+
+func (e *PatchConflictError) Error() string {
+	return fmt.Sprintf("%v: %s is %v, expected %v", ErrPatchConflict, e.Field, e.Actual, e.Expected)
+}
+
+func (e *PatchConflictError) Unwrap() error {
+	return ErrPatchConflict
+}
+
+// checkExpected compares task with the patch's expected values.
+func (p TaskPatch) checkExpected(task Task) error {
+	if p.ExpectedName != nil && *p.ExpectedName != task.Name {
+		return &PatchConflictError{Field: "name", Expected: *p.ExpectedName, Actual: task.Name}
+	}
+	if p.ExpectedState != nil && *p.ExpectedState != task.State {
+		return &PatchConflictError{Field: "state", Expected: *p.ExpectedState, Actual: task.State}
+	}
+	if p.ExpectedSize != nil && *p.ExpectedSize != task.Size {
+		return &PatchConflictError{Field: "size", Expected: *p.ExpectedSize, Actual: task.Size}
+	}
+	if p.ExpectedUrgent != nil && *p.ExpectedUrgent != task.Urgent {
+		return &PatchConflictError{Field: "urgent", Expected: *p.ExpectedUrgent, Actual: task.Urgent}
+	}
+	return nil
+}
+
+// replacementPatch builds the patch that makes a task's client-owned fields
+// match task, clearing any task leaves empty. ID, source, timestamps,
+// history, focus and move count are server-owned and not touched.
+func replacementPatch(task Task) TaskPatch {
+	patch := TaskPatch{
+		Name:          &task.Name,
+		Description:   &task.Description,
+		Notes:         &task.Notes,
+		State:         &task.State,
+		Size:          &task.Size,
+		BlockedReason: &task.BlockedReason,
+		DelegatedTo:   &task.DelegatedTo,
+		Urgent:        &task.Urgent,
+		DueDate:       task.DueDate,
+	}
+	if task.Links != nil {
+		patch.Links = &task.Links
+	} else {
+		patch.ClearFields = append(patch.ClearFields, "links")
+	}
+	if task.Checklist != nil {
+		patch.Checklist = &task.Checklist
+	} else {
+		patch.ClearFields = append(patch.ClearFields, "checklist")
+	}
+	if task.Tags != nil {
+		patch.Tags = &task.Tags
+	} else {
+		patch.ClearFields = append(patch.ClearFields, "tags")
+	}
+	if task.BlockedBy != nil {
+		patch.BlockedBy = &task.BlockedBy
+	} else {
+		patch.ClearFields = append(patch.ClearFields, "blockedBy")
+	}
+	if task.DueDate == nil {
+		patch.ClearFields = append(patch.ClearFields, "dueDate")
+	}
+	return patch
 }
 
-func (p TaskPatch) Apply(task *Task) error {
+// clearableFields lists the names ClearFields accepts. Name, state and size
+// always hold a value, so they cannot be cleared.
+var clearableFields = []string{
+	"description", "notes", "links", "checklist", "tags", "blockedReason",
+	"blockedBy", "delegatedTo", "urgent", "dueDate",
+}
+
+// clearTaskField resets one clearable field on task.
+func clearTaskField(task *Task, field string) {
+	switch field {
+	case "description":
+		task.Description = ""
+	case "notes":
+		task.Notes = ""
+	case "links":
+		task.Links = nil
+	case "checklist":
+		task.Checklist = nil
+	case "tags":
+		task.Tags = nil
+	case "blockedReason":
+		task.BlockedReason = ""
+	case "blockedBy":
+		task.BlockedBy = nil
+	case "delegatedTo":
+		task.DelegatedTo = ""
+	case "urgent":
+		task.Urgent = false
+	case "dueDate":
+		task.DueDate = nil
+	}
+}
+
+// Apply patches task in place. A state change must name one of the board's
+// states and be permitted by its transitions, and a size must be on the
+// board's scale. Expected values are checked before anything changes.
+func (p TaskPatch) Apply(task *Task, board *BoardState) error {
+	for _, field := range p.ClearFields {
+		if !slices.Contains(clearableFields, field) {
+			return fmt.Errorf("%w: cannot clear %q", ErrInvalidRequest, field)
+		}
+	}
+	if err := p.checkExpected(*task); err != nil {
+		return err
+	}
+	prevState := task.State
 	if p.Name != nil {
 		task.Name = *p.Name
 	}
@@ -53,16 +231,23 @@ func (p TaskPatch) Apply(task *Task) error {
 		task.Description = *p.Description
 	}
 	if p.Notes != nil {
-		task.Notes = *p.Notes
+		notes, err := board.notes.apply(*p.Notes)
+		if err != nil {
+			return err
+		}
+		task.Notes = notes
 	}
 	if p.State != nil {
-		if err := ValidateTaskState(*p.State); err != nil {
+		if err := board.ValidateTaskState(*p.State); err != nil {
+			return err
+		}
+		if err := checkTransition(board.Transitions, task.State, *p.State); err != nil {
 			return err
 		}
 		task.State = *p.State
 	}
 	if p.Size != nil {
-		size, err := NormalizeSize(*p.Size)
+		size, err := board.NormalizeSize(*p.Size)
 		if err != nil {
 			return err
 		}
@@ -76,18 +261,77 @@ func (p TaskPatch) Apply(task *Task) error {
         task.Checklist = make([]ChecklistItem, len(*p.Checklist))
         copy(task.Checklist, *p.Checklist)
     }
-    if p.Urgent != nil {
-        task.Urgent = *p.Urgent
+    if p.Tags != nil {
+        task.Tags = make([]string, len(*p.Tags))
+        copy(task.Tags, *p.Tags)
     }
-    return nil
+    if p.BlockedReason != nil {
+        task.BlockedReason = strings.TrimSpace(*p.BlockedReason)
+    }
+    if p.DelegatedTo != nil {
+        task.DelegatedTo = strings.TrimSpace(*p.DelegatedTo)
+    }
+    if p.DueDate != nil {
+        due := *p.DueDate
+        task.DueDate = &due
+    }
+    if p.BlockedBy != nil {
+        blockers, err := trimBlockers(*p.BlockedBy)
+        if err != nil {
+            return err
+        }
+        task.BlockedBy = blockers
+        if err := checkBoardCycle(board, task.ID); err != nil {
+            return err
+        }
+    }
+	if p.Urgent != nil {
+		task.Urgent = *p.Urgent
+	}
+	for _, field := range p.ClearFields {
+		clearTaskField(task, field)
+	}
+	return checkStateDetails(task, prevState)
+}
+
+// checkStateDetails clears BlockedReason and DelegatedTo outside the states
+// they describe, and requires them when a task enters blocked or delegated
+// from prevState. Tasks already in those states keep whatever they have, so
+// boards written before the requirement still load and edit.
+func checkStateDetails(task *Task, prevState string) error {
+	if task.State != "blocked" {
+		task.BlockedReason = ""
+	} else if prevState != "blocked" && task.BlockedReason == "" {
+		return fmt.Errorf("%w: blockedReason required when state is blocked", ErrInvalidRequest)
+	}
+	if utf8.RuneCountInString(task.BlockedReason) > MaxBlockedReasonLength {
+		return fmt.Errorf("%w: blockedReason exceeds %d characters", ErrInvalidRequest, MaxBlockedReasonLength)
+	}
+	if task.State != "delegated" {
+		task.DelegatedTo = ""
+	} else if prevState != "delegated" && task.DelegatedTo == "" {
+		return fmt.Errorf("%w: delegatedTo required when state is delegated", ErrInvalidRequest)
+	}
+	return nil
 }
 
 type MoveTaskRequest struct {
 	Location   string `json:"location"`
 	CategoryID string `json:"categoryId,omitempty"`
-	Position   *int   `json:"position,omitempty"`
-	SourceID   string `json:"sourceId,omitempty"`
-	Source     string `json:"source,omitempty"`
+	// CategoryName is resolved to an active category ID when CategoryID is
+	// empty.
+	CategoryName string `json:"categoryName,omitempty"`
+	Position     *int   `json:"position,omitempty"`
+	SourceID     string `json:"sourceId,omitempty"`
+	Source       string `json:"source,omitempty"`
+	// Force allows this one move to exceed the board's capacity, up to
+	// twice that.
+	Force bool `json:"force,omitempty"`
+	// ArchiveNote is appended to the task's notes when it is archived and
+	// ignored for any other destination.
+	ArchiveNote string `json:"archiveNote,omitempty"`
+	// Actor is taken from the X-Actor header rather than the request body.
+	Actor string `json:"-"`
 }
 
 func (r *MoveTaskRequest) Normalize() {
@@ -99,16 +343,135 @@ func (r *MoveTaskRequest) Normalize() {
 func (r MoveTaskRequest) Validate() error {
 	switch r.Location {
 	case LocationCategory:
-		if r.CategoryID == "" {
-			return fmt.Errorf("%w: categoryId required for category move", ErrInvalidRequest)
+		if r.CategoryID == "" && r.CategoryName == "" {
+			return fmt.Errorf("%w: categoryId or categoryName required for category move", ErrInvalidRequest)
 		}
-	case LocationBackburner, LocationArchive:
+	case LocationBackburner, LocationArchive, LocationInbox:
 	default:
 		return ErrInvalidLocation
 	}
 	return nil
 }
 
+type SetTaskSizeRequest struct {
+	Size int `json:"size"`
+}
+
+type SetTaskUrgentRequest struct {
+	Urgent bool `json:"urgent"`
+}
+
+// TaskFilter selects tasks by category, state, tag and location. Empty fields
+// match everything.
+type TaskFilter struct {
+	CategoryID string `json:"categoryId,omitempty"`
+	State      string `json:"state,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Location   string `json:"location,omitempty"`
+}
+
+// Validate checks the filter's fields. State names depend on the board, so
+// they are checked against it by the caller.
+func (f TaskFilter) Validate() error {
+	switch f.Location {
+	case "", LocationCategory, LocationBackburner, LocationArchive, LocationInbox:
+	default:
+		return ErrInvalidLocation
+	}
+	return nil
+}
+
+func (f TaskFilter) Matches(task Task, loc taskLocation, state *BoardState) bool {
+	if f.Location != "" && loc.Kind != f.Location {
+		return false
+	}
+	if f.CategoryID != "" {
+		if loc.Kind != LocationCategory || state.Categories[loc.CategoryIndex].ID != f.CategoryID {
+			return false
+		}
+	}
+	if f.State != "" && task.State != f.State {
+		return false
+	}
+	if f.Tag != "" && !hasTag(task.Tags, f.Tag) {
+		return false
+	}
+	return true
+}
+
+type BulkPatchRequest struct {
+	Filter TaskFilter `json:"filter"`
+	Patch  TaskPatch  `json:"patch"`
+	DryRun bool       `json:"dryRun,omitempty"`
+}
+
+// BulkTagRequest adds and removes tags across the listed tasks in one write.
+type BulkTagRequest struct {
+	TaskIDs []string `json:"taskIds"`
+	Add     []string `json:"add,omitempty"`
+	Remove  []string `json:"remove,omitempty"`
+	// Actor is taken from the X-Actor header rather than the request body.
+	Actor string `json:"-"`
+}
+
+func (r *BulkTagRequest) Normalize() {
+	r.Add = trimTags(r.Add)
+	r.Remove = trimTags(r.Remove)
+}
+
+func (r BulkTagRequest) Validate() error {
+	if len(r.TaskIDs) == 0 {
+		return fmt.Errorf("%w: taskIds required", ErrInvalidRequest)
+	}
+	if len(r.Add) == 0 && len(r.Remove) == 0 {
+		return fmt.Errorf("%w: nothing to add or remove", ErrInvalidRequest)
+	}
+	for _, tag := range r.Add {
+		if hasTag(r.Remove, tag) {
+			return fmt.Errorf("%w: tag %q both added and removed", ErrInvalidRequest, tag)
+		}
+	}
+	return nil
+}
+
+func trimTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+type BulkPatchResult struct {
+	Matched int    `json:"matched"`
+	Changed int    `json:"changed"`
+	Tasks   []Task `json:"tasks"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+}
+
+type CloneBoardRequest struct {
+	Path          string `json:"path"`
+	RegenerateIDs bool   `json:"regenerateIds,omitempty"`
+	Force         bool   `json:"force,omitempty"`
+	// Serve switches the server over to the clone once it is written.
+	Serve bool `json:"serve,omitempty"`
+}
+
+type OpenBoardRequest struct {
+	Path string `json:"path"`
+}
+
 type FocusRequest struct {
 	TaskID string `json:"taskId"`
 }
@@ -116,6 +479,12 @@ type FocusRequest struct {
 type CategoryPatch struct {
 	Name  *string  `json:"name,omitempty"`
 	Order []string `json:"order,omitempty"`
+	// MaxTasks of zero clears the category's own limit.
+	MaxTasks *int `json:"maxTasks,omitempty"`
+}
+
+type LockCategoryRequest struct {
+	Locked bool `json:"locked"`
 }
 
 type MoveCategoryRequest struct {
@@ -137,3 +506,25 @@ func (r MoveCategoryRequest) Validate() error {
 		return ErrInvalidLocation
 	}
 }
+
+type CloneCategoryRequest struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+type SwapCategoriesRequest struct {
+	CategoryAID string `json:"categoryAId"`
+	CategoryBID string `json:"categoryBId"`
+}
+
+type BoardSettingsPatch struct {
+	CapacityMode *string              `json:"capacityMode,omitempty"`
+	Transitions  *map[string][]string `json:"transitions,omitempty"`
+	States       *[]StateDef          `json:"states,omitempty"`
+	SizeScale    *[]int               `json:"sizeScale,omitempty"`
+	Capacity     *int                 `json:"capacity,omitempty"`
+	// StaleAfterDays of zero turns stale flagging off.
+	StaleAfterDays *int `json:"staleAfterDays,omitempty"`
+	// MaxTasks of zero removes the default task limit.
+	MaxTasks *int `json:"maxTasks,omitempty"`
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DateToken in a data file path is replaced by the current calendar date,
+// giving one board file per day.
+const DateToken = "{date}"
+
+const dateLayout = "2006-01-02"
+
+// isPathTemplate reports whether a data path rolls by date.
+func isPathTemplate(path string) bool {
+	return strings.Contains(path, DateToken)
+}
+
+// resolvePathLocked returns the template expanded for the store's current
+// day. Callers must hold s.mu or have exclusive access to s.
+func (s *Store) resolvePathLocked() string {
+	day := s.now().In(s.location).Format(dateLayout)
+	return strings.ReplaceAll(s.pathTemplate, DateToken, day)
+}
+
+// latestRolledPath finds the most recent existing file matching the template
+// that is not later than today, so a restart on a new day carries the board
+// forward. It returns "" when there is none.
+func (s *Store) latestRolledPath(today string) string {
+	pattern := strings.ReplaceAll(filepath.Clean(s.pathTemplate), DateToken, "*")
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return ""
+	}
+	sort.Strings(matches)
+	for i := len(matches) - 1; i >= 0; i-- {
+		if matches[i] <= filepath.Clean(today) {
+			return matches[i]
+		}
+	}
+	return ""
+}
+
+// openTemplateLocked points the store at today's file for its path template,
+// loading the latest earlier day's board when today's file does not exist yet.
+func (s *Store) openTemplateLocked() error {
+	today := s.resolvePathLocked()
+	s.path = today
+	if _, err := os.Stat(today); err == nil || !os.IsNotExist(err) {
+		return s.loadOrSeed()
+	}
+	prev := s.latestRolledPath(today)
+	if prev == "" {
+		return s.loadOrSeed()
+	}
+	s.path = prev
+	if err := s.loadOrSeed(); err != nil {
+		return err
+	}
+	s.path = today
+	if err := os.MkdirAll(filepath.Dir(today), 0o755); err != nil {
+		return fmt.Errorf("create data dir: %w", err)
+	}
+	return s.saveLocked()
+}
+
+// Roll moves a store opened with a date path template onto the file for the
+// current day, writing the board there so state carries forward. The previous
+// day's file is left as that day's snapshot. It reports whether the file
+// changed; stores without a template never roll.
+func (s *Store) Roll() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rolled, err := s.rollLocked()
+	if err != nil || !rolled {
+		return false, err
+	}
+	return true, s.saveLocked()
+}
+
+// rollLocked switches s.path to the current day's file. Callers must hold
+// s.mu and save afterwards.
+func (s *Store) rollLocked() (bool, error) {
+	if s.pathTemplate == "" {
+		return false, nil
+	}
+	next := s.resolvePathLocked()
+	if next == s.path {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(next), 0o755); err != nil {
+		return false, fmt.Errorf("create data dir: %w", err)
+	}
+	s.path = next
+	return true, nil
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readBoardFile(t *testing.T, path string) BoardState {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	var board BoardState
+	if err := json.Unmarshal(data, &board); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+	return board
+}
+
+func TestDatePathRollsAtMidnight(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 10, 23, 59, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	dayOne := filepath.Join(dir, "board-2025-03-10.json")
+	if err := os.WriteFile(dayOne, []byte(bulkBoard), 0o644); err != nil {
+		t.Fatalf("write data: %v", err)
+	}
+
+	store, err := NewStore(filepath.Join(dir, "board-"+DateToken+".json"), WithClock(clock), WithLocation(time.UTC))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	if store.Path() != dayOne {
+		t.Fatalf("expected %s, got %s", dayOne, store.Path())
+	}
+	if rolled, err := store.Roll(); err != nil || rolled {
+		t.Fatalf("expected no roll before midnight, got %v %v", rolled, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	rolled, err := store.Roll()
+	if err != nil || !rolled {
+		t.Fatalf("expected roll after midnight, got %v %v", rolled, err)
+	}
+	dayTwo := filepath.Join(dir, "board-2025-03-11.json")
+	if store.Path() != dayTwo {
+		t.Fatalf("expected %s, got %s", dayTwo, store.Path())
+	}
+	if got := len(readBoardFile(t, dayTwo).Categories); got != 2 {
+		t.Fatalf("expected board carried forward, got %d categories", got)
+	}
+
+	// writes now land in the new file and leave yesterday's snapshot alone
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationArchive}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if got := len(readBoardFile(t, dayTwo).Archives); got != 1 {
+		t.Fatalf("expected write in new file, got %d archived", got)
+	}
+	if got := len(readBoardFile(t, dayOne).Archives); got != 0 {
+		t.Fatalf("expected previous day untouched, got %d archived", got)
+	}
+}
+
+func TestDatePathWriteAfterMidnightRolls(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	if err := os.MkdirAll(filepath.Join(dir, "2025-03-10"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2025-03-10", "board.json"), []byte(bulkBoard), 0o644); err != nil {
+		t.Fatalf("write data: %v", err)
+	}
+	store, err := NewStore(filepath.Join(dir, DateToken, "board.json"), WithClock(func() time.Time { return now }), WithLocation(time.UTC))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	now = now.Add(24 * time.Hour)
+	if _, _, err := store.MoveTask("t1", MoveTaskRequest{Location: LocationBackburner}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	want := filepath.Join(dir, "2025-03-11", "board.json")
+	if store.Path() != want {
+		t.Fatalf("expected %s, got %s", want, store.Path())
+	}
+	if got := len(readBoardFile(t, want).Backburner); got != 1 {
+		t.Fatalf("expected new file to hold the current board, got %d backburnered", got)
+	}
+}
+
+func TestDatePathRestartCarriesLatestDay(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "board-"+DateToken+".json")
+	for _, day := range []string{"2025-03-08", "2025-03-09", "2025-03-20"} {
+		board := bulkBoard
+		if day != "2025-03-09" {
+			board = swapBoard
+		}
+		if err := os.WriteFile(filepath.Join(dir, "board-"+day+".json"), []byte(board), 0o644); err != nil {
+			t.Fatalf("write data: %v", err)
+		}
+	}
+
+	now := time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC)
+	store, err := NewStore(template, WithClock(func() time.Time { return now }), WithLocation(time.UTC))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	// the latest day not after today is 2025-03-09
+	if got := categoryIDs(store.GetState().Categories); got != "cat1,cat2" {
+		t.Fatalf("expected board from 2025-03-09, got %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "board-2025-03-11.json")); err != nil {
+		t.Fatalf("expected today's file written: %v", err)
+	}
+}
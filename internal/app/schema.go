@@ -0,0 +1,58 @@
+package app
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// boardSchema is the JSON Schema for the board file, kept in step with the
+// Go types by TestBoardSchemaMatchesTypes.
+//
+//go:embed board.schema.json
+var boardSchema []byte
+
+// BoardSchema returns the JSON Schema describing the board file format.
+func BoardSchema() []byte {
+	return bytes.Clone(boardSchema)
+}
+
+// ValidateBoardDocument reports whether data is a board the server will
+// load: it must match the board schema, unknown fields included, and be well
+// formed. Columns over capacity are accepted, as they are when loading.
+func ValidateBoardDocument(data []byte) error {
+	_, err := decodeBoardDocument(data)
+	return err
+}
+
+// decodeBoardDocument is ValidateBoardDocument returning the normalized
+// board.
+func decodeBoardDocument(data []byte) (BoardState, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var board BoardState
+	if err := dec.Decode(&board); err != nil {
+		return BoardState{}, fmt.Errorf("%w: decode board: %v", ErrInvalidRequest, err)
+	}
+	if dec.More() {
+		return BoardState{}, fmt.Errorf("%w: decode board: trailing data", ErrInvalidRequest)
+	}
+	normalizeBoardState(&board)
+	if err := validateBoardState(board); err != nil {
+		return BoardState{}, err
+	}
+	return board, nil
+}
+
+func (s *Server) handleBoardSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	if _, err := w.Write(boardSchema); err != nil {
+		logWriteError("board schema", err)
+	}
+}
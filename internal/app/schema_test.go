@@ -0,0 +1,266 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// schemaValidator checks documents against the subset of JSON Schema the
+// board schema uses.
+type schemaValidator struct {
+	root map[string]any
+}
+
+func newSchemaValidator(t *testing.T) schemaValidator {
+	t.Helper()
+	var root map[string]any
+	if err := json.Unmarshal(BoardSchema(), &root); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	return schemaValidator{root: root}
+}
+
+func (v schemaValidator) resolve(ref string) map[string]any {
+	node := any(v.root)
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		node = node.(map[string]any)[part]
+	}
+	return node.(map[string]any)
+}
+
+func (v schemaValidator) validate(doc []byte) error {
+	var value any
+	if err := json.Unmarshal(doc, &value); err != nil {
+		return err
+	}
+	return v.check(v.root, value, "$")
+}
+
+func (v schemaValidator) check(schema map[string]any, value any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		return v.check(v.resolve(ref), value, path)
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range enum {
+			if allowed == value {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v not in enum", path, value)
+		}
+	}
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				if _, present := obj[name.(string)]; !present {
+					return fmt.Errorf("%s: missing %s", path, name)
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for name, child := range obj {
+			if prop, ok := props[name]; ok {
+				if err := v.check(prop.(map[string]any), child, path+"."+name); err != nil {
+					return err
+				}
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					return fmt.Errorf("%s: unexpected property %s", path, name)
+				}
+			case map[string]any:
+				if err := v.check(extra, child, path+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		if max, ok := schema["maxItems"].(float64); ok && float64(len(items)) > max {
+			return fmt.Errorf("%s: more than %v items", path, max)
+		}
+		if itemSchema, ok := schema["items"].(map[string]any); ok {
+			for i, item := range items {
+				if err := v.check(itemSchema, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+		if min, ok := schema["minLength"].(float64); ok && float64(len(s)) < min {
+			return fmt.Errorf("%s: shorter than %v", path, min)
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return fmt.Errorf("%s: not a date-time", path)
+			}
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			return fmt.Errorf("%s: below %v", path, min)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	}
+	return nil
+}
+
+func jsonFieldNames(typ reflect.Type) []string {
+	names := []string{}
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func schemaPropertyNames(schema map[string]any) []string {
+	names := []string{}
+	for name := range schema["properties"].(map[string]any) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestBoardSchemaMatchesTypes(t *testing.T) {
+	v := newSchemaValidator(t)
+	for ref, typ := range map[string]reflect.Type{
+		"#":                     reflect.TypeOf(BoardState{}),
+		"#/$defs/category":      reflect.TypeOf(Category{}),
+		"#/$defs/task":          reflect.TypeOf(Task{}),
+		"#/$defs/stateDef":      reflect.TypeOf(StateDef{}),
+		"#/$defs/link":          reflect.TypeOf(TaskLink{}),
+		"#/$defs/checklistItem": reflect.TypeOf(ChecklistItem{}),
+		"#/$defs/auditEvent":    reflect.TypeOf(AuditEvent{}),
+	} {
+		schema := v.root
+		if ref != "#" {
+			schema = v.resolve(ref)
+		}
+		if got, want := schemaPropertyNames(schema), jsonFieldNames(typ); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s properties %v do not match %s fields %v", ref, got, typ.Name(), want)
+		}
+	}
+
+	settings := []string{}
+	for name := range boardSettings(BoardState{}) {
+		settings = append(settings, name)
+	}
+	sort.Strings(settings)
+	if got := schemaPropertyNames(v.resolve("#/$defs/settings")); !reflect.DeepEqual(got, settings) {
+		t.Errorf("settings properties %v do not match the settings response %v", got, settings)
+	}
+}
+
+func TestBoardSchemaFixtures(t *testing.T) {
+	v := newSchemaValidator(t)
+	seed, err := json.Marshal(seedBoard(time.Now()))
+	if err != nil {
+		t.Fatalf("encode seed: %v", err)
+	}
+	valid := map[string]string{
+		"seed":     string(seed),
+		"bulk":     bulkBoard,
+		"soft":     softBoard,
+		"cycles":   cycleBoard,
+		"minimal":  `{"categories":[{"id":"c","tasks":[{"id":"t","state":"todo","size":1}]}]}`,
+		"settings": `{"categories":[],"states":[{"id":"open"},{"id":"shut","label":"Shut"}],"sizeScale":[1,3],"capacity":6,"transitions":{"open":["shut"]},"capacityMode":"soft"}`,
+	}
+	for name, doc := range valid {
+		if err := v.validate([]byte(doc)); err != nil {
+			t.Errorf("%s: schema rejected a valid board: %v", name, err)
+		}
+		if err := ValidateBoardDocument([]byte(doc)); err != nil {
+			t.Errorf("%s: server rejected a valid board: %v", name, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"task without id":     `{"categories":[{"id":"c","tasks":[{"state":"todo","size":1}]}]}`,
+		"size as string":      `{"backburner":[{"id":"t","state":"todo","size":"2"}]}`,
+		"zero size":           `{"backburner":[{"id":"t","state":"todo","size":0}]}`,
+		"unknown field":       `{"categories":[],"colour":"blue"}`,
+		"unknown task field":  `{"archives":[{"id":"t","state":"done","size":1,"priority":3}]}`,
+		"capacity mode":       `{"capacityMode":"medium"}`,
+		"too many categories": `{"categories":[{"id":"1"},{"id":"2"},{"id":"3"},{"id":"4"},{"id":"5"},{"id":"6"}]}`,
+		"bad timestamp":       `{"inbox":[{"id":"t","state":"todo","size":1,"createdAt":"yesterday"}]}`,
+		"negative limit":      `{"categories":[{"id":"c","maxTasks":-1}]}`,
+		"empty state id":      `{"states":[{"id":""}]}`,
+	}
+	for name, doc := range invalid {
+		if err := v.validate([]byte(doc)); err == nil {
+			t.Errorf("%s: schema accepted an invalid board", name)
+		}
+		if err := ValidateBoardDocument([]byte(doc)); err == nil {
+			t.Errorf("%s: server accepted an invalid board", name)
+		}
+	}
+
+	// rules that depend on the board's own settings are beyond the schema
+	for name, doc := range map[string]string{
+		"duplicate ids": `{"backburner":[{"id":"t","state":"todo","size":1},{"id":"t","state":"todo","size":1}]}`,
+		"unknown state": `{"backburner":[{"id":"t","state":"someday","size":1}]}`,
+		"off scale":     `{"sizeScale":[1,2],"backburner":[{"id":"t","state":"todo","size":4}]}`,
+	} {
+		if err := v.validate([]byte(doc)); err != nil {
+			t.Errorf("%s: expected the schema to accept it, got %v", name, err)
+		}
+		if err := ValidateBoardDocument([]byte(doc)); err == nil {
+			t.Errorf("%s: server accepted an invalid board", name)
+		}
+	}
+}
+
+func TestBoardSchemaEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	rec := doRequest(t, srv, http.MethodGet, "/api/v1/schema/board.json", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/schema+json" {
+		t.Fatalf("expected schema content type, got %q", ct)
+	}
+	var schema map[string]any
+	decodeBody(t, rec, &schema)
+	if schema["title"] != "TwentyFive board" {
+		t.Fatalf("unexpected schema %v", schema["title"])
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/v1/import", `{"board":{"categories":[],"colour":"blue"}}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected import to apply the document checks, got %d", rec.Code)
+	}
+}
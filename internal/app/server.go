@@ -1,12 +1,18 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"twentyfive/internal/assets"
 )
@@ -14,29 +20,116 @@ import (
 type Server struct {
 	store        *Store
 	mux          *http.ServeMux
+	api          http.Handler
 	indexHandler http.Handler
+
+	lenientJSON      bool
+	allowDestructive bool
+	allowFileAdmin   bool
+	heartbeat        time.Duration
+	webhooks         *WebhookDispatcher
+	githubAPI        string
 }
 
-func NewServer(store *Store) *Server {
+func NewServer(store *Store, opts ...ServerOption) *Server {
 	s := &Server{
 		store:        store,
 		mux:          http.NewServeMux(),
 		indexHandler: assets.IndexHandler(),
+		heartbeat:    DefaultHeartbeatInterval,
+		githubAPI:    defaultGitHubAPI,
 	}
 
-	s.mux.HandleFunc("/api/board", s.handleBoard)
-	s.mux.HandleFunc("/api/tasks", s.handleTasks)
-	s.mux.HandleFunc("/api/tasks/", s.handleTaskByID)
-	s.mux.HandleFunc("/api/categories", s.handleCategories)
-	s.mux.HandleFunc("/api/categories/", s.handleCategoryByID)
-	s.mux.HandleFunc("/api/board/focus", s.handleFocus)
+	for _, rt := range s.apiRoutes() {
+		s.mux.Handle(APIPrefix+rt.pattern, http.StripPrefix(APIPrefix, rt.handler))
+		s.mux.Handle(legacyAPIPrefix+rt.pattern, deprecatedAlias(http.StripPrefix(legacyAPIPrefix, rt.handler)))
+	}
+	s.mux.HandleFunc("/admin/board/clone", s.handleCloneBoard)
+	s.mux.HandleFunc("/admin/open", s.handleOpenBoard)
+	s.mux.HandleFunc("/admin/import/bundle", s.handleImportBundle)
+	s.mux.HandleFunc("/admin/webhooks", s.handleWebhookStatus)
 
+	s.api = s.mux
+	for _, opt := range opts {
+		opt(s)
+	}
 	return s
 }
 
+const (
+	// APIPrefix is the canonical, versioned prefix for every API route.
+	APIPrefix = "/api/v1"
+	// legacyAPIPrefix serves the same routes unversioned, flagged deprecated.
+	legacyAPIPrefix = "/api"
+)
+
+type route struct {
+	pattern string
+	handler http.HandlerFunc
+}
+
+// apiRoutes lists every API route relative to its version prefix. Handlers
+// see the request path with the prefix already stripped.
+func (s *Server) apiRoutes() []route {
+	return []route{
+		{"/board", s.handleBoard},
+		{"/tasks", s.handleTasks},
+		{"/tasks/", s.handleTaskByID},
+		{"/tasks/bulk-patch", s.handleBulkPatch},
+		{"/tasks/quick", s.handleQuickAdd},
+		{"/tasks/tag", s.handleTagTasks},
+		{"/tasks/export.csv", s.handleExportTasksCSV},
+		{"/tasks/recent", s.handleRecentTasks},
+		{"/tasks/today", s.handleTasksToday},
+		{"/tasks/completed-this-week", s.handleCompletedThisWeek},
+		{"/tasks/urgent", s.handleUrgentTasks},
+		{"/board/urgents", s.handleBoardUrgents},
+		{"/categories", s.handleCategories},
+		{"/categories/", s.handleCategoryByID},
+		{"/categories/import", s.handleImportCategory},
+		{"/categories/swap", s.handleSwapCategories},
+		{"/category-archives/", s.handleCategoryArchiveByID},
+		{"/archives/categories/", s.handleArchivedByCategory},
+		{"/archives/stats", s.handleArchiveStats},
+		{"/backburner/stats", s.handleBackburnerStats},
+		{"/board/focus", s.handleFocus},
+		{"/board/focus/context", s.handleFocusContext},
+		{"/board/focus/move", s.handleMoveFocused},
+		{"/board/reset", s.handleReset},
+		{"/board/archive-done", s.handleArchiveDone},
+		{"/board/sync", s.handleSync},
+		{"/board/stats", s.handleStats},
+		{"/board/readiness", s.handleReadiness},
+		{"/board/info", s.handleBoardInfo},
+		{"/board/events", s.handleBoardEvents},
+		{"/board/category-counts", s.handleCategoryCounts},
+		{"/board/diff", s.handleBoardDiff},
+		{"/board/cycles", s.handleBoardCycles},
+		{"/board/compact", s.handleCompact},
+		{"/board/settings", s.handleBoardSettings},
+		{"/board/matrix/states", s.handleStateMatrix},
+		{"/health", s.handleHealth},
+		{"/schema/board.json", s.handleBoardSchema},
+		{"/import", s.handleImport},
+		{"/import/github", s.handleImportGitHub},
+		{"/import/csv", s.handleImportCSV},
+		{"/export/bundle", s.handleExportBundle},
+	}
+}
+
+// deprecatedAlias marks responses served from the unversioned prefix and
+// points clients at the canonical route.
+func deprecatedAlias(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", APIPrefix, strings.TrimPrefix(r.URL.Path, legacyAPIPrefix)))
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if strings.HasPrefix(r.URL.Path, "/api/") {
-		s.mux.ServeHTTP(w, r)
+	if strings.HasPrefix(r.URL.Path, "/api/") || strings.HasPrefix(r.URL.Path, "/admin/") {
+		s.api.ServeHTTP(w, r)
 		return
 	}
 	s.indexHandler.ServeHTTP(w, r)
@@ -45,126 +138,332 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleBoard(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		state := s.store.GetState()
+		state, err := s.store.GetStateSF()
+		if err != nil {
+			writeDomainError(w, err)
+			return
+		}
+		key := r.URL.Query().Get("sortCategories")
+		states := trimTags(strings.Split(r.URL.Query().Get("state"), ","))
+		if key != "" || len(states) > 0 {
+			// the coalesced snapshot may be shared with other requests
+			state = state.Clone()
+		}
+		if key != "" {
+			if err := sortCategories(&state, key); err != nil {
+				writeDomainError(w, err)
+				return
+			}
+		}
+		if len(states) > 0 {
+			if err := filterBoardStates(&state, states); err != nil {
+				writeDomainError(w, err)
+				return
+			}
+		}
 		writeJSON(w, http.StatusOK, state)
-	default:
-		methodNotAllowed(w, http.MethodGet)
-	}
-}
-
-func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodPost:
-		var req CreateTaskRequest
-		if err := decodeJSON(r, &req); err != nil {
-			writeError(w, http.StatusBadRequest, err)
+	case http.MethodDelete:
+		if !s.allowDestructive {
+			writeError(w, http.StatusForbidden, errors.New("wiping the board is disabled; start the server with -allow-destructive"))
 			return
 		}
-		task, board, err := s.store.CreateTask(req)
+		board, err := s.store.Wipe()
 		if err != nil {
 			writeDomainError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusCreated, map[string]any{
-			"task":  task,
-			"board": board,
-		})
+		writeMutation(w, r, http.StatusOK, map[string]any{}, board)
 	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+	}
+}
+
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
 		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	if !s.allowFileAdmin {
+		writeError(w, http.StatusForbidden, errors.New("reloading the board from disk is disabled; start the server with -allow-file-admin"))
+		return
+	}
+	diff, err := s.store.SyncFromFile()
+	if err != nil {
+		writeDomainError(w, err)
+		return
 	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"diff": diff,
+	}, s.store.GetState())
 }
 
-func (s *Server) handleTaskByID(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/tasks/")
-	if path == "" {
-		http.NotFound(w, r)
+func (s *Server) handleArchiveDone(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
 		return
 	}
-	if strings.HasSuffix(path, "/move") {
-		id := strings.TrimSuffix(path, "/move")
-		id = strings.TrimSuffix(id, "/")
-		s.handleMoveTask(w, r, id)
+	tasks, board, err := s.store.ArchiveAllDoneTasks()
+	if err != nil {
+		writeDomainError(w, err)
 		return
 	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"tasks": tasks,
+	}, board)
+}
 
-	id := strings.Trim(path, "/")
+func (s *Server) handleBoardSettings(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, boardSettings(s.store.GetState()))
 	case http.MethodPatch:
-		var patch TaskPatch
-		if err := decodeJSON(r, &patch); err != nil {
+		var patch BoardSettingsPatch
+		if err := s.decodeJSON(r, &patch); err != nil {
 			writeError(w, http.StatusBadRequest, err)
 			return
 		}
-		task, board, err := s.store.UpdateTask(id, patch)
-		if err != nil {
-			writeDomainError(w, err)
-			return
-		}
-		writeJSON(w, http.StatusOK, map[string]any{
-			"task":  task,
-			"board": board,
-		})
-	case http.MethodDelete:
-		board, err := s.store.DeleteTask(id)
+		board, err := s.store.UpdateSettings(patch)
 		if err != nil {
 			writeDomainError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, map[string]any{
-			"board": board,
-		})
+		writeMutation(w, r, http.StatusOK, boardSettings(board), board)
 	default:
-		methodNotAllowed(w, http.MethodPatch, http.MethodDelete)
+		methodNotAllowed(w, http.MethodGet, http.MethodPatch)
 	}
 }
 
-func (s *Server) handleMoveTask(w http.ResponseWriter, r *http.Request, id string) {
-	if r.Method != http.MethodPost {
-		methodNotAllowed(w, http.MethodPost)
+func boardSettings(board BoardState) map[string]any {
+	mode := board.CapacityMode
+	if mode == "" {
+		mode = CapacityHard
+	}
+	transitions := board.Transitions
+	if transitions == nil {
+		transitions = map[string][]string{}
+	}
+	return map[string]any{
+		"capacityMode":   mode,
+		"transitions":    transitions,
+		"states":         board.StateList(),
+		"sizeScale":      board.Sizes(),
+		"capacity":       board.ColumnLimit(),
+		"staleAfterDays": board.StaleAfterDays,
+		"maxTasks":       board.MaxTasks,
+	}
+}
+
+func (s *Server) handleBoardInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
 		return
 	}
-	var req MoveTaskRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, err)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"dataFile":    s.store.Path(),
+		"revision":    s.store.GetState().Revision,
+		"saveLatency": s.store.SaveLatency(),
+	})
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
 		return
 	}
-	task, board, err := s.store.MoveTask(id, req)
+	writeJSON(w, http.StatusOK, s.store.GetStats())
+}
+
+func (s *Server) handleArchiveStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	stats, err := s.store.GetArchiveStats()
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *Server) handleBackburnerStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	stats, err := s.store.GetBackburnerStats()
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *Server) handleBoardDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	since, err := time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: since must be an RFC3339 timestamp", ErrInvalidRequest))
+		return
+	}
+	diff, err := s.store.GetBoardDiff(since)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
+
+func (s *Server) handleCategoryCounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	active, backburner, archive, err := s.store.CountCategories()
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
+	counts := map[string]int{
+		"active":     active,
+		"backburner": backburner,
+		"archive":    archive,
+	}
+	pool := r.URL.Query().Get("pool")
+	if pool == "" {
+		counts["limit"] = CategoryLimit
+		writeJSON(w, http.StatusOK, counts)
+		return
+	}
+	count, ok := counts[pool]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: pool must be active, backburner or archive", ErrInvalidRequest))
+		return
+	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"task":  task,
-		"board": board,
+		"pool":  pool,
+		"count": count,
+		"limit": CategoryLimit,
 	})
 }
 
-func (s *Server) handleCategories(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleStateMatrix(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	stats := s.store.GetStats()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"totals":     stats.StateTotals,
+		"categories": stats.StateMatrix,
+	})
+}
+
+func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
-		var payload struct {
-			Name string `json:"name"`
+		// the body is either one CreateTaskRequest or, with tasks, a batch
+		// for a single location
+		var body struct {
+			CreateTaskRequest
+			Tasks []Task `json:"tasks"`
 		}
-		if err := decodeJSON(r, &payload); err != nil {
+		if err := s.decodeJSON(r, &body); err != nil {
 			writeError(w, http.StatusBadRequest, err)
 			return
 		}
-		cat, board, err := s.store.CreateCategory(payload.Name)
+		if body.Tasks != nil {
+			if body.Task.ID != "" || body.Task.Name != "" {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("%w: send either task or tasks, not both", ErrInvalidRequest))
+				return
+			}
+			for i := range body.Tasks {
+				s.store.ResolveTaskRefs(body.Tasks[i].BlockedBy)
+			}
+			tasks, board, err := s.store.CreateTasks(CreateTasksRequest{
+				Location:   body.Location,
+				CategoryID: body.CategoryID,
+				Position:   body.Position,
+				Tasks:      body.Tasks,
+				Force:      body.Force,
+				Actor:      actorFromRequest(r),
+			})
+			if err != nil {
+				writeDomainError(w, err)
+				return
+			}
+			writeMutation(w, r, http.StatusCreated, map[string]any{
+				"tasks": tasks,
+			}, board)
+			return
+		}
+		req := body.CreateTaskRequest
+		req.Actor = actorFromRequest(r)
+		s.store.ResolveTaskRefs(req.Task.BlockedBy)
+		// ifFits turns a full category into a no-op for automations that
+		// would rather skip than fail
+		ifFits, _ := strconv.ParseBool(r.URL.Query().Get("ifFits"))
+		task, board, err := s.store.CreateTask(req)
 		if err != nil {
+			if ifFits && (errors.Is(err, ErrCapacityExceeded) || errors.Is(err, ErrTaskLimit)) {
+				writeJSON(w, http.StatusOK, map[string]any{
+					"created": false,
+					"reason":  err.Error(),
+				})
+				return
+			}
 			writeDomainError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusCreated, map[string]any{
-			"category": cat,
-			"board":    board,
+		payload := map[string]any{
+			"task": task,
+		}
+		if ifFits {
+			payload["created"] = true
+		}
+		writeMutation(w, r, http.StatusCreated, payload, board)
+	case http.MethodGet:
+		hasChecklist, err := optionalBool(r, "hasChecklist")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		allDone, err := optionalBool(r, "allDone")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		results, err := s.store.GetTasksByChecklist(hasChecklist, allDone)
+		if err != nil {
+			writeDomainError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"tasks": results,
 		})
 	default:
-		methodNotAllowed(w, http.MethodPost)
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
 	}
 }
 
-func (s *Server) handleCategoryByID(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/categories/")
+// optionalBool reads a boolean query parameter, nil when it is absent.
+func optionalBool(r *http.Request, name string) (*bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s %q", ErrInvalidRequest, name, raw)
+	}
+	return &value, nil
+}
+
+func (s *Server) handleTaskByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/tasks/")
 	if path == "" {
 		http.NotFound(w, r)
 		return
@@ -172,105 +471,911 @@ func (s *Server) handleCategoryByID(w http.ResponseWriter, r *http.Request) {
 	if strings.HasSuffix(path, "/move") {
 		id := strings.TrimSuffix(path, "/move")
 		id = strings.TrimSuffix(id, "/")
-		if id == "" {
-			http.NotFound(w, r)
-			return
-		}
-		s.handleMoveCategory(w, r, id)
+		s.handleMoveTask(w, r, s.store.ResolveTaskRef(id))
 		return
 	}
-	id := strings.Trim(path, "/")
-	if id == "" {
-		http.NotFound(w, r)
+	if strings.HasSuffix(path, "/size") {
+		id := strings.TrimSuffix(strings.TrimSuffix(path, "/size"), "/")
+		s.handleTaskSize(w, r, s.store.ResolveTaskRef(id))
+		return
+	}
+	if strings.HasSuffix(path, "/urgent") {
+		id := strings.TrimSuffix(strings.TrimSuffix(path, "/urgent"), "/")
+		s.handleTaskUrgent(w, r, s.store.ResolveTaskRef(id))
+		return
+	}
+	if strings.HasSuffix(path, "/touch") {
+		id := strings.TrimSuffix(strings.TrimSuffix(path, "/touch"), "/")
+		s.handleTouchTask(w, r, s.store.ResolveTaskRef(id))
 		return
 	}
+
+	// path segments and body fields naming tasks take an ID or a short ref
+	id := s.store.ResolveTaskRef(strings.Trim(path, "/"))
 	switch r.Method {
 	case http.MethodPatch:
-		var patch CategoryPatch
-		if err := decodeJSON(r, &patch); err != nil {
+		var patch TaskPatch
+		if err := s.decodeJSON(r, &patch); err != nil {
 			writeError(w, http.StatusBadRequest, err)
 			return
 		}
-		var (
-			board BoardState
-			cat   Category
-			err   error
-		)
-		if patch.Name != nil {
-			cat, board, err = s.store.RenameCategory(id, *patch.Name)
-			if err != nil {
-				writeDomainError(w, err)
-				return
-			}
+		patch.Actor = actorFromRequest(r)
+		if patch.BlockedBy != nil {
+			s.store.ResolveTaskRefs(*patch.BlockedBy)
 		}
-		if patch.Order != nil {
-			cat, board, err = s.store.ReorderCategoryTasks(id, patch.Order)
-			if err != nil {
-				writeDomainError(w, err)
-				return
-			}
+		update, board, err := s.store.PatchTask(id, patch)
+		if err != nil {
+			writeDomainError(w, err)
+			return
 		}
-		if patch.Name == nil && patch.Order == nil {
-			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: no fields to update", ErrInvalidRequest))
+		payload := map[string]any{
+			"task": update.Task,
+		}
+		if update.Task.State != update.PreviousState {
+			payload["previousState"] = update.PreviousState
+		}
+		writeMutation(w, r, http.StatusOK, payload, board)
+	case http.MethodPut:
+		var task Task
+		if err := s.decodeJSON(r, &task); err != nil {
+			writeError(w, http.StatusBadRequest, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, map[string]any{
-			"category": cat,
-			"board":    board,
-		})
+		if task.ID != "" {
+			task.ID = s.store.ResolveTaskRef(task.ID)
+		}
+		s.store.ResolveTaskRefs(task.BlockedBy)
+		update, board, err := s.store.ReplaceTask(id, task, actorFromRequest(r))
+		if err != nil {
+			writeDomainError(w, err)
+			return
+		}
+		payload := map[string]any{
+			"task": update.Task,
+		}
+		if update.Task.State != update.PreviousState {
+			payload["previousState"] = update.PreviousState
+		}
+		writeMutation(w, r, http.StatusOK, payload, board)
+	case http.MethodDelete:
+		board, err := s.store.DeleteTask(id)
+		if err != nil {
+			writeDomainError(w, err)
+			return
+		}
+		writeMutation(w, r, http.StatusOK, map[string]any{}, board)
 	default:
-		methodNotAllowed(w, http.MethodPatch)
+		methodNotAllowed(w, http.MethodPatch, http.MethodPut, http.MethodDelete)
 	}
 }
 
-func (s *Server) handleMoveCategory(w http.ResponseWriter, r *http.Request, id string) {
-	if r.Method != http.MethodPost {
-		methodNotAllowed(w, http.MethodPost)
+func (s *Server) handleTaskSize(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPatch {
+		methodNotAllowed(w, http.MethodPatch)
 		return
 	}
-	var req MoveCategoryRequest
-	if err := decodeJSON(r, &req); err != nil {
+	var req SetTaskSizeRequest
+	if err := s.decodeJSON(r, &req); err != nil {
 		writeError(w, http.StatusBadRequest, err)
 		return
 	}
-	cat, board, err := s.store.MoveCategory(id, req)
+	task, board, err := s.store.SetTaskSize(id, req.Size)
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{
-		"category": cat,
-		"board":    board,
-	})
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"task": task,
+	}, board)
 }
 
-func (s *Server) handleFocus(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleTouchTask(w http.ResponseWriter, r *http.Request, id string) {
 	if r.Method != http.MethodPost {
 		methodNotAllowed(w, http.MethodPost)
 		return
 	}
-	var req FocusRequest
-	if err := decodeJSON(r, &req); err != nil {
+	task, board, err := s.store.TouchTask(id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"task": task,
+	}, board)
+}
+
+func (s *Server) handleTaskUrgent(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPatch {
+		methodNotAllowed(w, http.MethodPatch)
+		return
+	}
+	var req SetTaskUrgentRequest
+	if err := s.decodeJSON(r, &req); err != nil {
 		writeError(w, http.StatusBadRequest, err)
 		return
 	}
-	task, board, err := s.store.SetFocused(req.TaskID)
+	task, board, err := s.store.SetTaskUrgent(id, req.Urgent)
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{
-		"task":  task,
-		"board": board,
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"task": task,
+	}, board)
+}
+
+func (s *Server) handleMoveTask(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req MoveTaskRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.Actor = actorFromRequest(r)
+	task, board, err := s.store.MoveTask(id, req)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"task": task,
+	}, board)
+}
+
+func (s *Server) handleMoveFocused(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req MoveTaskRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.Actor = actorFromRequest(r)
+	task, board, err := s.store.MoveFocusedTask(req)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"task": task,
+	}, board)
+}
+
+func (s *Server) handleBulkPatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req BulkPatchRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.Patch.Actor = actorFromRequest(r)
+	if req.Patch.BlockedBy != nil {
+		s.store.ResolveTaskRefs(*req.Patch.BlockedBy)
+	}
+	result, board, err := s.store.BulkPatchTasks(req)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"matched": result.Matched,
+		"changed": result.Changed,
+		"tasks":   result.Tasks,
+		"dryRun":  result.DryRun,
+	}, board)
+}
+
+func (s *Server) handleTagTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req BulkTagRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.Actor = actorFromRequest(r)
+	s.store.ResolveTaskRefs(req.TaskIDs)
+	tasks, board, err := s.store.TagTasks(req)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"tasks": tasks,
+	}, board)
+}
+
+func (s *Server) handleExportTasksCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	q := r.URL.Query()
+	filter := TaskFilter{
+		CategoryID: q.Get("categoryId"),
+		State:      q.Get("state"),
+		Tag:        q.Get("tag"),
+		Location:   q.Get("location"),
+	}
+	var columns []string
+	if raw := q.Get("columns"); raw != "" {
+		columns = strings.Split(raw, ",")
+	}
+	data, err := s.store.ExportTasksCSV(filter, columns)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="tasks.csv"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		logWriteError("export csv", err)
+	}
+}
+
+func (s *Server) handleRecentTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	limit := 10
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: limit must be an integer", ErrInvalidRequest))
+			return
+		}
+		limit = n
+	}
+	results, err := s.store.GetRecentlyModifiedTasks(limit)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tasks": results,
+	})
+}
+
+func (s *Server) handleTasksToday(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	results, err := s.store.GetTasksCreatedToday()
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tasks": results,
+	})
+}
+
+func (s *Server) handleCompletedThisWeek(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	results, err := s.store.GetCompletedTasksThisWeek()
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tasks": results,
+	})
+}
+
+func (s *Server) handleUrgentTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	urgent := s.store.UrgentTasks()
+	results := make([]SearchResult, len(urgent))
+	for i, u := range urgent {
+		results[i] = SearchResult{Task: u.Task, Pool: PoolActive, CategoryID: u.CategoryID, CategoryName: u.CategoryName}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tasks": results,
+	})
+}
+
+func (s *Server) handleBoardUrgents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tasks": s.store.UrgentTasks(),
+	})
+}
+
+func (s *Server) handleArchivedByCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	sourceID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/archives/categories/"), "/")
+	cat, err := s.store.GetArchivedByCategory(sourceID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"category": cat,
+	})
+}
+
+func (s *Server) handleTaskAtPosition(w http.ResponseWriter, r *http.Request, categoryID, rawPosition string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	position, err := strconv.Atoi(rawPosition)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: position must be an integer", ErrInvalidRequest))
+		return
+	}
+	task, err := s.store.GetTaskAtPosition(categoryID, position)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"task": task,
+	})
+}
+
+func (s *Server) handleCategories(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var payload struct {
+			Name string `json:"name"`
+		}
+		if err := s.decodeJSON(r, &payload); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		cat, board, err := s.store.CreateCategory(payload.Name)
+		if err != nil {
+			writeDomainError(w, err)
+			return
+		}
+		writeMutation(w, r, http.StatusCreated, map[string]any{
+			"category": cat,
+		}, board)
+	default:
+		methodNotAllowed(w, http.MethodPost)
+	}
+}
+
+func (s *Server) handleSwapCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req SwapCategoriesRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	board, err := s.store.SwapCategories(req.CategoryAID, req.CategoryBID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{}, board)
+}
+
+func (s *Server) handleCategoryByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/categories/")
+	if path == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/move") {
+		id := strings.TrimSuffix(path, "/move")
+		id = strings.TrimSuffix(id, "/")
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleMoveCategory(w, r, id)
+		return
+	}
+	if strings.HasSuffix(path, "/export") {
+		id := strings.TrimSuffix(strings.TrimSuffix(path, "/export"), "/")
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleExportCategory(w, r, id)
+		return
+	}
+	if strings.HasSuffix(path, "/clone") {
+		id := strings.TrimSuffix(strings.TrimSuffix(path, "/clone"), "/")
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleCloneCategory(w, r, id)
+		return
+	}
+	if strings.HasSuffix(path, "/lock") {
+		id := strings.TrimSuffix(strings.TrimSuffix(path, "/lock"), "/")
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleLockCategory(w, r, id)
+		return
+	}
+	if strings.HasSuffix(path, "/park") {
+		id := strings.TrimSuffix(strings.TrimSuffix(path, "/park"), "/")
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleParkCategory(w, r, id)
+		return
+	}
+	if strings.HasSuffix(path, "/summary") {
+		id := strings.TrimSuffix(strings.TrimSuffix(path, "/summary"), "/")
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleCategorySummary(w, r, id)
+		return
+	}
+	if id, position, ok := strings.Cut(strings.Trim(path, "/"), "/tasks/"); ok {
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleTaskAtPosition(w, r, id, position)
+		return
+	}
+	id := strings.Trim(path, "/")
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodPatch:
+		var patch CategoryPatch
+		if err := s.decodeJSON(r, &patch); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		var (
+			board BoardState
+			cat   Category
+			err   error
+		)
+		if patch.Name != nil {
+			cat, board, err = s.store.RenameCategory(id, *patch.Name)
+			if err != nil {
+				writeDomainError(w, err)
+				return
+			}
+		}
+		if patch.Order != nil {
+			s.store.ResolveTaskRefs(patch.Order)
+			cat, board, err = s.store.ReorderCategoryTasks(id, patch.Order)
+			if err != nil {
+				writeDomainError(w, err)
+				return
+			}
+		}
+		if patch.MaxTasks != nil {
+			cat, board, err = s.store.SetCategoryTaskLimit(id, *patch.MaxTasks)
+			if err != nil {
+				writeDomainError(w, err)
+				return
+			}
+		}
+		if patch.Name == nil && patch.Order == nil && patch.MaxTasks == nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: no fields to update", ErrInvalidRequest))
+			return
+		}
+		writeMutation(w, r, http.StatusOK, map[string]any{
+			"category": cat,
+		}, board)
+	default:
+		methodNotAllowed(w, http.MethodPatch)
+	}
+}
+
+func (s *Server) handleMoveCategory(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req MoveCategoryRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	cat, board, err := s.store.MoveCategory(id, req)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"category": cat,
+	}, board)
+}
+
+func (s *Server) handleCloneCategory(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req CloneCategoryRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	cat, board, err := s.store.CloneCategory(id, req.Name, req.Location)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusCreated, map[string]any{
+		"category": cat,
+	}, board)
+}
+
+func (s *Server) handleLockCategory(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPatch {
+		methodNotAllowed(w, http.MethodPatch)
+		return
+	}
+	var req LockCategoryRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	cat, board, err := s.store.LockCategory(id, req.Locked)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"category": cat,
+	}, board)
+}
+
+func (s *Server) handleExportCategory(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	cat, err := s.store.ExportCategory(id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, cat)
+}
+
+func (s *Server) handleParkCategory(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	tasks, board, err := s.store.ParkCategory(id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"tasks": tasks,
+	}, board)
+}
+
+func (s *Server) handleCategorySummary(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	summary, err := s.store.GetBacklogSummary(id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func (s *Server) handleImportCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var payload Category
+	if err := s.decodeJSON(r, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	cat, board, err := s.store.ImportCategory(payload)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusCreated, map[string]any{
+		"category": cat,
+	}, board)
+}
+
+func (s *Server) handleCategoryArchiveByID(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/category-archives/"), "/")
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+	keepTasks := r.URL.Query().Get("keepTasks") == "true"
+	board, err := s.store.DeleteCategoryArchive(id, keepTasks)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{}, board)
+}
+
+func (s *Server) handleFocus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req FocusRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	task, changed, board, err := s.store.SetFocused(s.store.ResolveTaskRef(req.TaskID))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"task":    task,
+		"changed": changed,
+	}, board)
+}
+
+func (s *Server) handleFocusContext(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	ctx, err := s.store.GetFocusedTaskContext()
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ctx)
+}
+
+func (s *Server) handleReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	if r.URL.Query().Get("confirm") != "true" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: confirm=true required to reset the board", ErrInvalidRequest))
+		return
+	}
+	board, err := s.store.ResetBoard()
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{}, board)
+}
+
+func (s *Server) handleCloneBoard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	if !s.allowFileAdmin {
+		writeError(w, http.StatusForbidden, errors.New("cloning the board is disabled; start the server with -allow-file-admin"))
+		return
+	}
+	var req CloneBoardRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	path, board, err := s.store.CloneTo(req.Path, req.RegenerateIDs, req.Force)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if req.Serve {
+		if board, err = s.store.Open(path); err != nil {
+			writeDomainError(w, err)
+			return
+		}
+	}
+	writeMutation(w, r, http.StatusCreated, map[string]any{
+		"path":    path,
+		"serving": s.store.Path() == path,
+	}, board)
+}
+
+func (s *Server) handleOpenBoard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	if !s.allowFileAdmin {
+		writeError(w, http.StatusForbidden, errors.New("opening another board is disabled; start the server with -allow-file-admin"))
+		return
+	}
+	var req OpenBoardRequest
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	board, err := s.store.Open(req.Path)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"path": s.store.Path(),
+	}, board)
+}
+
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	// the board is held back so it gets the same checks as a data file
+	var req struct {
+		ImportRequest
+		Board json.RawMessage `json:"board"`
+	}
+	if err := s.decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Board) > 0 {
+		incoming, err := decodeBoardDocument(req.Board)
+		if err != nil {
+			writeDomainError(w, err)
+			return
+		}
+		req.ImportRequest.Board = incoming
+	}
+	summary, board, err := s.store.Import(req.ImportRequest)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeMutation(w, r, http.StatusOK, map[string]any{
+		"summary": summary,
+	}, board)
+}
+
+func (s *Server) handleExportBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="twentyfive-bundle.tar.gz"`)
+	if err := s.store.WriteBundle(w); err != nil {
+		logWriteError("export bundle", err)
+	}
+}
+
+func (s *Server) handleImportBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	if !s.allowFileAdmin {
+		writeError(w, http.StatusForbidden, errors.New("restoring bundles is disabled; start the server with -allow-file-admin"))
+		return
+	}
+	dir, err := s.store.RestoreBundle(r.Body, r.URL.Query().Get("dir"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"dir": dir,
+	})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":   "ok",
+		"dataFile": s.store.Path(),
 	})
 }
 
-func decodeJSON(r *http.Request, v any) error {
+func (s *Server) decodeJSON(r *http.Request, v any) error {
 	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
+	if !s.lenientJSON {
+		dec.DisallowUnknownFields()
+	}
 	return dec.Decode(v)
 }
 
+// writeMutation writes a mutation response, embedding the updated board
+// unless the client opted out via ?includeBoard=false or X-Include-Board, in
+// which case only the board revision is reported. Active categories over
+// capacity are listed either way, as is the position of a returned task.
+func writeMutation(w http.ResponseWriter, r *http.Request, status int, payload map[string]any, board BoardState) {
+	if includeBoard(r) {
+		payload["board"] = board
+	} else {
+		payload["revision"] = board.Revision
+	}
+	if task, ok := payload["task"].(Task); ok {
+		payload["position"] = taskPosition(board, task.ID)
+	}
+	if over := overCapacityIDs(board); len(over) > 0 {
+		payload["overCapacity"] = over
+	}
+	writeJSON(w, status, payload)
+}
+
+// taskPosition is the task's index within its active category, or -1 when it
+// is not on the active board.
+func taskPosition(board BoardState, id string) int {
+	if _, loc, err := findTask(&board, id, nil); err == nil && loc.Kind == LocationCategory {
+		return loc.TaskIndex
+	}
+	return -1
+}
+
+func includeBoard(r *http.Request) bool {
+	raw := r.URL.Query().Get("includeBoard")
+	if raw == "" {
+		raw = r.Header.Get("X-Include-Board")
+	}
+	if raw == "" {
+		return true
+	}
+	include, err := strconv.ParseBool(raw)
+	return err != nil || include
+}
+
+// actorFromRequest names who is making a change, from the X-Actor header.
+func actorFromRequest(r *http.Request) string {
+	if actor := strings.TrimSpace(r.Header.Get("X-Actor")); actor != "" {
+		return actor
+	}
+	return DefaultActor
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -278,8 +1383,27 @@ func writeJSON(w http.ResponseWriter, status int, payload any) {
 		return
 	}
 	if err := json.NewEncoder(w).Encode(payload); err != nil {
-		log.Printf("write json: %v", err)
+		logWriteError("write json", err)
+	}
+}
+
+// logWriteError logs a failed response write. A client that went away
+// mid-response is routine, so that case is only logged at debug level.
+func logWriteError(op string, err error) {
+	if clientGone(err) {
+		slog.Debug("client disconnected", "op", op, "error", err)
+		return
 	}
+	log.Printf("%s: %v", op, err)
+}
+
+// clientGone reports whether err means the client cancelled the request or
+// closed the connection.
+func clientGone(err error) bool {
+	return errors.Is(err, context.Canceled) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, net.ErrClosed)
 }
 
 func writeError(w http.ResponseWriter, status int, err error) {
@@ -292,20 +1416,55 @@ func methodNotAllowed(w http.ResponseWriter, methods ...string) {
 }
 
 func writeDomainError(w http.ResponseWriter, err error) {
+	var capacityErr *CapacityError
+	var ambiguousErr *AmbiguousCategoryError
+	var dueErr *DueDateError
+	var conflictErr *PatchConflictError
 	switch {
+	case errors.As(err, &conflictErr):
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":    err.Error(),
+			"field":    conflictErr.Field,
+			"expected": conflictErr.Expected,
+			"actual":   conflictErr.Actual,
+		})
+	case errors.As(err, &dueErr):
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": err.Error(),
+			"text":  dueErr.Text,
+		})
+	case errors.As(err, &ambiguousErr):
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error":      err.Error(),
+			"candidates": ambiguousErr.Candidates,
+		})
 	case errors.Is(err, ErrInvalidRequest),
 		errors.Is(err, ErrInvalidState),
 		errors.Is(err, ErrInvalidLocation),
-		errors.Is(err, ErrInvalidTaskSize):
+		errors.Is(err, ErrInvalidTaskSize),
+		errors.Is(err, ErrNotFocusable):
 		writeError(w, http.StatusBadRequest, err)
 	case errors.Is(err, ErrTaskNotFound),
 		errors.Is(err, ErrCategoryNotFound):
 		writeError(w, http.StatusNotFound, err)
+	case errors.As(err, &capacityErr):
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":       err.Error(),
+			"suggestions": capacityErr.Suggestions,
+		})
 	case errors.Is(err, ErrCapacityExceeded),
+		errors.Is(err, ErrTaskLimit),
 		errors.Is(err, ErrCategoryLimit):
 		writeError(w, http.StatusConflict, err)
-	case errors.Is(err, ErrDuplicateCategory):
+	case errors.Is(err, ErrDuplicateCategory),
+		errors.Is(err, ErrFileExists),
+		errors.Is(err, ErrInvalidTransition),
+		errors.Is(err, ErrStateInUse),
+		errors.Is(err, ErrSizeInUse),
+		errors.Is(err, ErrPatchConflict):
 		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrBoardTooLarge):
+		writeError(w, http.StatusInsufficientStorage, err)
 	default:
 		log.Printf("internal error: %v", err)
 		writeError(w, http.StatusInternalServerError, errors.New("internal server error"))
@@ -0,0 +1,503 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func doRequest(t *testing.T, srv *Server, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+}
+
+func TestResetBoardRequiresConfirm(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	srv := NewServer(store)
+
+	rec := doRequest(t, srv, http.MethodPost, "/api/board/reset", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without confirm, got %d", rec.Code)
+	}
+	if got := store.GetState().Categories[0].ID; got != "cat1" {
+		t.Fatalf("expected board untouched, got first category %q", got)
+	}
+}
+
+func TestResetBoardRestoresSeed(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	srv := NewServer(store)
+
+	rec := doRequest(t, srv, http.MethodPost, "/api/board/reset?confirm=true", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Board BoardState `json:"board"`
+	}
+	decodeBody(t, rec, &resp)
+	seed := seedBoard(time.Now())
+	if len(resp.Board.Categories) != len(seed.Categories) {
+		t.Fatalf("expected %d seed categories, got %d", len(seed.Categories), len(resp.Board.Categories))
+	}
+	for i := range seed.Categories {
+		if resp.Board.Categories[i].Name != seed.Categories[i].Name {
+			t.Fatalf("expected category %q at %d, got %q", seed.Categories[i].Name, i, resp.Board.Categories[i].Name)
+		}
+	}
+}
+
+func TestWipeBoard(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, _, err := store.CreateTask(CreateTaskRequest{CategoryID: "cat2", Task: Task{Name: "Extra", State: "todo", Size: 1}}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := store.Wipe(); err != nil {
+		t.Fatalf("wipe: %v", err)
+	}
+	board := store.GetState()
+	if len(board.Categories) != 0 || len(board.AuditLog) != 0 {
+		t.Fatalf("expected an empty board, got %d categories and %d audit events", len(board.Categories), len(board.AuditLog))
+	}
+	if _, _, err := store.MoveTask("t1", MoveTaskRequest{Location: LocationArchive}); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected wiped tasks gone from the index, got %v", err)
+	}
+}
+
+func TestWipeBoardEndpointGated(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if rec := doRequest(t, NewServer(store), http.MethodDelete, "/api/v1/board", ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without -allow-destructive, got %d", rec.Code)
+	}
+	if len(store.GetState().Categories) != 2 {
+		t.Fatalf("expected board untouched")
+	}
+
+	rec := doRequest(t, NewServer(store, WithDestructive()), http.MethodDelete, "/api/v1/board", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Board BoardState `json:"board"`
+	}
+	decodeBody(t, rec, &resp)
+	if len(resp.Board.Categories) != 0 {
+		t.Fatalf("expected empty board in response, got %d categories", len(resp.Board.Categories))
+	}
+}
+
+const sortBoard = `{
+	"categories": [
+		{"id":"c","name":"charlie","tasks":[
+			{"id":"t1","name":"One","description":"","notes":"","state":"todo","size":1}
+		]},
+		{"id":"a","name":"Alpha","tasks":[
+			{"id":"t2","name":"Two","description":"","notes":"","state":"todo","size":4}
+		]},
+		{"id":"b","name":"bravo","tasks":[]}
+	],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func boardCategoryIDs(t *testing.T, srv *Server, target string) []string {
+	t.Helper()
+	rec := doRequest(t, srv, http.MethodGet, target, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected 200, got %d", target, rec.Code)
+	}
+	var board BoardState
+	decodeBody(t, rec, &board)
+	ids := make([]string, len(board.Categories))
+	for i, cat := range board.Categories {
+		ids[i] = cat.ID
+	}
+	return ids
+}
+
+func TestBoardSortCategories(t *testing.T) {
+	srv := NewServer(newTestStore(t, sortBoard))
+
+	if got := strings.Join(boardCategoryIDs(t, srv, "/api/board?sortCategories=name"), ","); got != "a,b,c" {
+		t.Fatalf("expected name order a,b,c, got %s", got)
+	}
+	if got := strings.Join(boardCategoryIDs(t, srv, "/api/board?sortCategories=size"), ","); got != "b,c,a" {
+		t.Fatalf("expected size order b,c,a, got %s", got)
+	}
+	if got := strings.Join(boardCategoryIDs(t, srv, "/api/board"), ","); got != "c,a,b" {
+		t.Fatalf("expected stored order c,a,b to be unchanged, got %s", got)
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/board?sortCategories=color", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown sort key, got %d", rec.Code)
+	}
+}
+
+func TestUrgentTasksInCategoryOrder(t *testing.T) {
+	board := `{
+		"categories": [
+			{"id":"first","name":"First","tasks":[
+				{"id":"f1","name":"F1","description":"","notes":"","state":"todo","size":1},
+				{"id":"f2","name":"F2","description":"","notes":"","state":"todo","size":1,"urgent":true}
+			]},
+			{"id":"second","name":"Second","tasks":[
+				{"id":"s1","name":"S1","description":"","notes":"","state":"todo","size":1,"urgent":true}
+			]}
+		],
+		"backburner": [],
+		"archives": [],
+		"categoryBackburner": [],
+		"categoryArchives": []
+	}`
+	srv := NewServer(newTestStore(t, board))
+
+	rec := doRequest(t, srv, http.MethodGet, "/api/tasks/urgent", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Tasks []SearchResult `json:"tasks"`
+	}
+	decodeBody(t, rec, &resp)
+	if len(resp.Tasks) != 2 {
+		t.Fatalf("expected 2 urgent tasks, got %d", len(resp.Tasks))
+	}
+	if resp.Tasks[0].Task.ID != "f2" || resp.Tasks[0].CategoryID != "first" {
+		t.Fatalf("expected f2 from first category, got %+v", resp.Tasks[0])
+	}
+	if resp.Tasks[1].Task.ID != "s1" || resp.Tasks[1].CategoryID != "second" {
+		t.Fatalf("expected s1 from second category, got %+v", resp.Tasks[1])
+	}
+}
+
+func TestCategoryExportImportRoundTrip(t *testing.T) {
+	source := NewServer(newTestStore(t, bulkBoard))
+	rec := doRequest(t, source, http.MethodGet, "/api/categories/cat2/export", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("export: expected 200, got %d", rec.Code)
+	}
+	exported := rec.Body.String()
+
+	target := NewServer(newTestStore(t, sortBoard))
+	rec = doRequest(t, target, http.MethodPost, "/api/categories/import", exported)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("import: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Category Category   `json:"category"`
+		Board    BoardState `json:"board"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Category.Name != "Beta" || len(resp.Category.Tasks) != 1 {
+		t.Fatalf("expected Beta with 1 task, got %+v", resp.Category)
+	}
+	if !resp.Category.Tasks[0].Urgent {
+		t.Fatalf("expected urgent flag to survive import")
+	}
+	if resp.Category.ID == "cat2" || resp.Category.Tasks[0].ID == "t4" {
+		t.Fatalf("expected fresh ids on import")
+	}
+	if len(resp.Board.Categories) != 4 {
+		t.Fatalf("expected imported category appended, got %d categories", len(resp.Board.Categories))
+	}
+
+	// importing the same payload again collides on name
+	if rec := doRequest(t, target, http.MethodPost, "/api/categories/import", exported); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 on duplicate name, got %d", rec.Code)
+	}
+}
+
+func TestAPIRoutesServedUnderBothPrefixes(t *testing.T) {
+	clock := fixedClock("2024-05-01T12:00:00Z")
+	srv := NewServer(newTestStore(t, bulkBoard, WithClock(clock)))
+
+	for _, rt := range srv.apiRoutes() {
+		path := rt.pattern
+		if path == "/board/events" {
+			// streams until the client goes away; see TestBoardEventsHeartbeat
+			continue
+		}
+		if strings.HasSuffix(path, "/") {
+			path += "cat1"
+		}
+		t.Run(path, func(t *testing.T) {
+			canonical := doRequest(t, srv, http.MethodGet, APIPrefix+path, "")
+			legacy := doRequest(t, srv, http.MethodGet, "/api"+path, "")
+			if canonical.Code == http.StatusNotFound && canonical.Body.String() == "404 page not found\n" {
+				t.Fatalf("route not registered under %s", APIPrefix)
+			}
+			if canonical.Code != legacy.Code || canonical.Body.String() != legacy.Body.String() {
+				t.Fatalf("prefixes differ: %d %q vs %d %q", canonical.Code, canonical.Body.String(), legacy.Code, legacy.Body.String())
+			}
+			if canonical.Header().Get("Deprecation") != "" {
+				t.Fatalf("canonical route should not be deprecated")
+			}
+			if legacy.Header().Get("Deprecation") != "true" {
+				t.Fatalf("expected Deprecation header on legacy route")
+			}
+			if want := "<" + APIPrefix + path + `>; rel="successor-version"`; legacy.Header().Get("Link") != want {
+				t.Fatalf("expected Link %q, got %q", want, legacy.Header().Get("Link"))
+			}
+		})
+	}
+}
+
+func TestMutationCanOmitBoard(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+
+	rec := doRequest(t, srv, http.MethodPatch, "/api/v1/tasks/t1", `{"name":"Renamed"}`)
+	var full map[string]json.RawMessage
+	decodeBody(t, rec, &full)
+	if _, ok := full["board"]; !ok {
+		t.Fatalf("expected board by default")
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPatch, "/api/v1/tasks/t1?includeBoard=false", strings.NewReader(`{"name":"Again"}`)),
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodPatch, "/api/v1/tasks/t1", strings.NewReader(`{"name":"Once more"}`))
+			r.Header.Set("X-Include-Board", "false")
+			return r
+		}(),
+	} {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		var slim map[string]json.RawMessage
+		decodeBody(t, rec, &slim)
+		if _, ok := slim["board"]; ok {
+			t.Fatalf("expected board omitted")
+		}
+		if _, ok := slim["task"]; !ok {
+			t.Fatalf("expected task in slim response")
+		}
+		if _, ok := slim["revision"]; !ok {
+			t.Fatalf("expected revision in slim response")
+		}
+	}
+
+	if got := srv.store.GetState().Revision; got != 3 {
+		t.Fatalf("expected revision 3 after three writes, got %d", got)
+	}
+}
+
+func TestActorRecordedOnStateChange(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/tasks/t3", strings.NewReader(`{"state":"doing"}`))
+	req.Header.Set("X-Actor", "alice")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task  Task       `json:"task"`
+		Board BoardState `json:"board"`
+	}
+	decodeBody(t, rec, &resp)
+	want := AuditEvent{Actor: "alice", TaskID: "t3", Action: "state", From: "todo", To: "doing"}
+	if len(resp.Task.History) != 1 {
+		t.Fatalf("expected one history event, got %+v", resp.Task.History)
+	}
+	if got := resp.Task.History[0]; got.Actor != want.Actor || got.Action != want.Action || got.From != want.From || got.To != want.To {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if len(resp.Board.AuditLog) != 1 || resp.Board.AuditLog[0].TaskID != "t3" || resp.Board.AuditLog[0].Actor != "alice" {
+		t.Fatalf("expected board audit entry for t3 by alice, got %+v", resp.Board.AuditLog)
+	}
+
+	rec = doRequest(t, srv, http.MethodPatch, "/api/v1/tasks/t3", `{"name":"Renamed"}`)
+	decodeBody(t, rec, &resp)
+	if got := resp.Task.History[len(resp.Task.History)-1]; got.Actor != DefaultActor || got.Action != "update" {
+		t.Fatalf("expected update by %s, got %+v", DefaultActor, got)
+	}
+}
+
+func TestUnknownFieldsStrictByDefault(t *testing.T) {
+	body := `{"name":"Renamed","futureField":true}`
+
+	strict := NewServer(newTestStore(t, bulkBoard))
+	if rec := doRequest(t, strict, http.MethodPatch, "/api/v1/tasks/t1", body); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 in strict mode, got %d", rec.Code)
+	}
+
+	lenient := NewServer(newTestStore(t, bulkBoard), WithLenientJSON())
+	rec := doRequest(t, lenient, http.MethodPatch, "/api/v1/tasks/t1", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 in lenient mode, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Task.Name != "Renamed" {
+		t.Fatalf("expected known fields applied, got %q", resp.Task.Name)
+	}
+}
+
+func TestMutationReportsTaskPosition(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	var resp struct {
+		Task     Task `json:"task"`
+		Position int  `json:"position"`
+	}
+
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks", `{"location":"category","categoryId":"cat2","position":1,"task":{"name":"New","state":"todo","size":1}}`)
+	decodeBody(t, rec, &resp)
+	if resp.Position != 1 {
+		t.Fatalf("expected created task at position 1, got %d", resp.Position)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/v1/tasks/t1/move", `{"location":"category","categoryId":"cat2","position":0}`)
+	decodeBody(t, rec, &resp)
+	if resp.Task.ID != "t1" || resp.Position != 0 {
+		t.Fatalf("expected t1 moved to position 0, got %s at %d", resp.Task.ID, resp.Position)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/v1/tasks/t1/move", `{"location":"backburner"}`)
+	decodeBody(t, rec, &resp)
+	if resp.Position != -1 {
+		t.Fatalf("expected -1 for backburnered task, got %d", resp.Position)
+	}
+}
+
+func TestBoardUrgentsSkipsCategoriesWithoutUrgent(t *testing.T) {
+	store := newTestStore(t, matrixBoard)
+	for _, id := range []string{"a1", "b2"} {
+		urgent := true
+		if _, _, err := store.UpdateTask(id, TaskPatch{Urgent: &urgent}); err != nil {
+			t.Fatalf("mark %s urgent: %v", id, err)
+		}
+	}
+
+	tasks, err := store.GetUrgentTasks()
+	if err != nil {
+		t.Fatalf("urgent tasks: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "a1" || tasks[1].ID != "b2" {
+		t.Fatalf("expected a1 and b2, got %+v", tasks)
+	}
+
+	var resp struct {
+		Tasks []UrgentTask `json:"tasks"`
+	}
+	decodeBody(t, doRequest(t, NewServer(store), http.MethodGet, "/api/v1/board/urgents", ""), &resp)
+	want := []UrgentTask{
+		{Task: Task{ID: "a1"}, CategoryID: "a", CategoryName: "Alpha"},
+		{Task: Task{ID: "b2"}, CategoryID: "b", CategoryName: "Beta"},
+	}
+	if len(resp.Tasks) != len(want) {
+		t.Fatalf("expected %d urgent tasks, got %+v", len(want), resp.Tasks)
+	}
+	for i, got := range resp.Tasks {
+		if got.ID != want[i].ID || got.CategoryID != want[i].CategoryID || got.CategoryName != want[i].CategoryName || !got.Urgent {
+			t.Fatalf("expected %s from %s (%s), got %+v", want[i].ID, want[i].CategoryID, want[i].CategoryName, got)
+		}
+	}
+}
+
+// failingWriter fails every body write with err.
+type failingWriter struct {
+	*httptest.ResponseRecorder
+	err error
+}
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestWriteJSONClientDisconnectLogsAtDebug(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	for _, err := range []error{
+		context.Canceled,
+		fmt.Errorf("write tcp: %w", syscall.EPIPE),
+	} {
+		logs.Reset()
+		writeJSON(failingWriter{httptest.NewRecorder(), err}, http.StatusOK, map[string]string{"ok": "yes"})
+		if !strings.Contains(logs.String(), "level=DEBUG") || strings.Contains(logs.String(), "level=INFO") || strings.Contains(logs.String(), "level=ERROR") {
+			t.Fatalf("%v: expected a debug-level log only, got %q", err, logs.String())
+		}
+	}
+
+	logs.Reset()
+	writeJSON(failingWriter{httptest.NewRecorder(), errors.New("disk on fire")}, http.StatusOK, map[string]string{"ok": "yes"})
+	if !strings.Contains(logs.String(), "write json: disk on fire") || strings.Contains(logs.String(), "level=DEBUG") {
+		t.Fatalf("expected other write errors logged as before, got %q", logs.String())
+	}
+}
+
+func TestUpdateTaskReportsPreviousState(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+
+	var resp struct {
+		Task          Task    `json:"task"`
+		PreviousState *string `json:"previousState"`
+	}
+	decodeBody(t, doRequest(t, srv, http.MethodPatch, "/api/v1/tasks/t3", `{"state":"doing"}`), &resp)
+	if resp.Task.State != "doing" || resp.PreviousState == nil || *resp.PreviousState != "todo" {
+		t.Fatalf("expected previousState todo, got %+v", resp)
+	}
+
+	resp.PreviousState = nil
+	decodeBody(t, doRequest(t, srv, http.MethodPatch, "/api/v1/tasks/t3", `{"name":"Renamed"}`), &resp)
+	if resp.PreviousState != nil {
+		t.Fatalf("expected no previousState without a state change, got %q", *resp.PreviousState)
+	}
+}
+
+func TestGetBoardFilteredByState(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	srv := NewServer(store)
+	rec := doRequest(t, srv, http.MethodGet, "/api/v1/board?state=todo", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var board BoardState
+	decodeBody(t, rec, &board)
+	if len(board.Categories) != 2 {
+		t.Fatalf("expected empty categories to stay, got %d categories", len(board.Categories))
+	}
+	if got := board.Categories[0].Tasks; len(got) != 1 || got[0].ID != "t3" {
+		t.Fatalf("expected only the todo task t3, got %+v", got)
+	}
+	if len(board.Categories[1].Tasks) != 0 {
+		t.Fatalf("expected Beta's doing task filtered out, got %+v", board.Categories[1].Tasks)
+	}
+	if stored := store.GetState(); len(stored.Categories[0].Tasks) != 3 || len(stored.Categories[1].Tasks) != 1 {
+		t.Fatalf("expected the stored board untouched")
+	}
+
+	rec = doRequest(t, srv, http.MethodGet, "/api/v1/board?state=doing,%20blocked", "")
+	decodeBody(t, rec, &board)
+	if len(board.Categories[0].Tasks) != 2 || len(board.Categories[1].Tasks) != 1 {
+		t.Fatalf("expected the doing tasks, got %+v", board.Categories)
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/board?state=someday", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown state, got %d", rec.Code)
+	}
+}
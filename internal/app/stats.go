@@ -0,0 +1,257 @@
+package app
+
+import (
+	"sort"
+	"time"
+)
+
+// BoardStats summarises the board for dashboards.
+type BoardStats struct {
+	ActiveTasks     int `json:"activeTasks"`
+	ActivePoints    int `json:"activePoints"`
+	BackburnerTasks int `json:"backburnerTasks"`
+	ArchivedTasks   int `json:"archivedTasks"`
+	InboxTasks      int `json:"inboxTasks"`
+	// flat copies of CategoryCounts for clients that only want the numbers
+	ActiveCategories     int                       `json:"activeCategories"`
+	BackburnerCategories int                       `json:"backburnerCategories"`
+	ArchivedCategories   int                       `json:"archivedCategories"`
+	StateMatrix          map[string]map[string]int `json:"stateMatrix"`
+	StateTotals          map[string]int            `json:"stateTotals"`
+	CategoryCounts       CategoryCounts            `json:"categoryCounts"`
+	// Delegates counts active delegated tasks by who they are waiting on.
+	Delegates map[string]int `json:"delegates"`
+	// Stale counts stale tasks by active category ID.
+	Stale map[string]int `json:"stale"`
+}
+
+// CategoryCounts reports how many categories sit in each category pool,
+// alongside the active-board limit.
+type CategoryCounts struct {
+	Active     int `json:"active"`
+	Backburner int `json:"backburner"`
+	Archive    int `json:"archive"`
+	Limit      int `json:"limit"`
+}
+
+// GetStats computes BoardStats from the current state.
+func (s *Store) GetStats() BoardStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matrix, totals := stateMatrix(&s.state)
+	stats := BoardStats{
+		BackburnerTasks: len(s.state.Backburner),
+		ArchivedTasks:   len(s.state.Archives),
+		InboxTasks:      len(s.state.Inbox),
+		StateMatrix:     matrix,
+		StateTotals:     totals,
+		CategoryCounts:  countCategories(&s.state),
+		Delegates:       map[string]int{},
+		Stale:           map[string]int{},
+	}
+	stats.ActiveCategories = stats.CategoryCounts.Active
+	stats.BackburnerCategories = stats.CategoryCounts.Backburner
+	stats.ArchivedCategories = stats.CategoryCounts.Archive
+	now := s.now()
+	for _, cat := range s.state.Categories {
+		stats.ActiveTasks += len(cat.Tasks)
+		stats.ActivePoints += categorySize(cat)
+		stats.Stale[cat.ID] = 0
+		for _, task := range cat.Tasks {
+			if s.state.isStale(task, now) {
+				stats.Stale[cat.ID]++
+			}
+			if task.State == "delegated" {
+				stats.Delegates[task.DelegatedTo]++
+			}
+		}
+	}
+	return stats
+}
+
+// CountCategories returns the number of active, backburnered and archived
+// categories.
+func (s *Store) CountCategories() (active, backburner, archive int, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	counts := countCategories(&s.state)
+	return counts.Active, counts.Backburner, counts.Archive, nil
+}
+
+func countCategories(state *BoardState) CategoryCounts {
+	return CategoryCounts{
+		Active:     len(state.Categories),
+		Backburner: len(state.CategoryBackburner),
+		Archive:    len(state.CategoryArchives),
+		Limit:      CategoryLimit,
+	}
+}
+
+// CategoryTaskMatrix counts active tasks by category ID and state. Every
+// allowed state is present for every active category, zero-filled.
+func (s *Store) CategoryTaskMatrix() (map[string]map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	matrix, _ := stateMatrix(&s.state)
+	return matrix, nil
+}
+
+func stateMatrix(state *BoardState) (map[string]map[string]int, map[string]int) {
+	states := knownStates(state)
+	totals := make(map[string]int, len(states))
+	for _, st := range states {
+		totals[st] = 0
+	}
+	matrix := make(map[string]map[string]int, len(state.Categories))
+	for _, cat := range state.Categories {
+		row := make(map[string]int, len(states))
+		for _, st := range states {
+			row[st] = 0
+		}
+		for _, task := range cat.Tasks {
+			row[task.State]++
+			totals[task.State]++
+		}
+		matrix[cat.ID] = row
+	}
+	return matrix, totals
+}
+
+func knownStates(board *BoardState) []string {
+	states := make([]string, 0, len(board.StateList()))
+	for _, def := range board.StateList() {
+		states = append(states, def.ID)
+	}
+	sort.Strings(states)
+	return states
+}
+
+// ArchiveStats summarises the loose task archive for retrospectives. Oldest
+// and newest are by CompletedAt; tasks archived without one are counted but
+// never picked.
+type ArchiveStats struct {
+	TotalTasks             int            `json:"totalTasks"`
+	ByState                map[string]int `json:"byState"`
+	BySizeDistribution     map[int]int    `json:"bySizeDistribution"`
+	AverageSize            float64        `json:"averageSize"`
+	OldestTask             *Task          `json:"oldestTask"`
+	NewestTask             *Task          `json:"newestTask"`
+	UniqueSourceCategories []string       `json:"uniqueSourceCategories"`
+}
+
+// BackburnerStats is ArchiveStats for the backburner, with oldest and newest
+// by CreatedAt.
+type BackburnerStats ArchiveStats
+
+// GetArchiveStats computes ArchiveStats from the archive.
+func (s *Store) GetArchiveStats() (ArchiveStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return taskPoolStats(s.state.Archives, func(task Task) time.Time {
+		if task.CompletedAt == nil {
+			return time.Time{}
+		}
+		return *task.CompletedAt
+	}), nil
+}
+
+// GetBackburnerStats computes BackburnerStats from the backburner.
+func (s *Store) GetBackburnerStats() (BackburnerStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return BackburnerStats(taskPoolStats(s.state.Backburner, func(task Task) time.Time {
+		return task.CreatedAt
+	})), nil
+}
+
+// taskPoolStats summarises tasks, ordering them for oldest and newest by
+// stamp. Tasks with a zero stamp are skipped for that ordering.
+func taskPoolStats(tasks []Task, stamp func(Task) time.Time) ArchiveStats {
+	stats := ArchiveStats{
+		TotalTasks:             len(tasks),
+		ByState:                map[string]int{},
+		BySizeDistribution:     map[int]int{},
+		UniqueSourceCategories: []string{},
+	}
+	sources := map[string]struct{}{}
+	total := 0
+	for i := range tasks {
+		task := &tasks[i]
+		stats.ByState[task.State]++
+		stats.BySizeDistribution[task.Size]++
+		total += task.Size
+		if task.Source != "" {
+			if _, seen := sources[task.Source]; !seen {
+				sources[task.Source] = struct{}{}
+				stats.UniqueSourceCategories = append(stats.UniqueSourceCategories, task.Source)
+			}
+		}
+		at := stamp(*task)
+		if at.IsZero() {
+			continue
+		}
+		if stats.OldestTask == nil || at.Before(stamp(*stats.OldestTask)) {
+			oldest := task.Clone()
+			stats.OldestTask = &oldest
+		}
+		if stats.NewestTask == nil || at.After(stamp(*stats.NewestTask)) {
+			newest := task.Clone()
+			stats.NewestTask = &newest
+		}
+	}
+	if len(tasks) > 0 {
+		stats.AverageSize = float64(total) / float64(len(tasks))
+	}
+	sort.Strings(stats.UniqueSourceCategories)
+	return stats
+}
+
+// BacklogSummary is the at-a-glance view of one category, typically the
+// seeded Backlog.
+type BacklogSummary struct {
+	// ActiveCount counts tasks not in a completed state.
+	ActiveCount  int `json:"activeCount"`
+	BlockedCount int `json:"blockedCount"`
+	// Blocked lists the blocked tasks in position order, each with the
+	// blockedReason it was given.
+	Blocked    []Task `json:"blocked"`
+	UrgentTask *Task  `json:"urgentTask"`
+	// NextToStart is the first todo task by position, or the zero Task when
+	// there is none.
+	NextToStart Task `json:"nextToStart"`
+	TotalSize   int  `json:"totalSize"`
+}
+
+// GetBacklogSummary summarises the category with the given ID in any pool.
+func (s *Store) GetBacklogSummary(categoryID string) (BacklogSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	// findCategory returns a copy, so tasks can be handed out directly
+	cat, ok := findCategory(&s.state, categoryID)
+	if !ok {
+		return BacklogSummary{}, ErrCategoryNotFound
+	}
+
+	summary := BacklogSummary{Blocked: []Task{}}
+	foundNext := false
+	for _, task := range cat.Tasks {
+		summary.TotalSize += task.Size
+		if !IsCompletedState(task.State) {
+			summary.ActiveCount++
+		}
+		if task.State == "blocked" {
+			summary.BlockedCount++
+			summary.Blocked = append(summary.Blocked, task)
+		}
+		if task.Urgent && summary.UrgentTask == nil {
+			urgent := task
+			summary.UrgentTask = &urgent
+		}
+		if task.State == "todo" && !foundNext {
+			summary.NextToStart = task
+			foundNext = true
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,318 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const matrixBoard = `{
+	"categories": [
+		{"id":"a","name":"Alpha","tasks":[
+			{"id":"a1","name":"A1","description":"","notes":"","state":"todo","size":1},
+			{"id":"a2","name":"A2","description":"","notes":"","state":"todo","size":1},
+			{"id":"a3","name":"A3","description":"","notes":"","state":"doing","size":1}
+		]},
+		{"id":"b","name":"Beta","tasks":[
+			{"id":"b1","name":"B1","description":"","notes":"","state":"blocked","size":1},
+			{"id":"b2","name":"B2","description":"","notes":"","state":"done","size":2}
+		]},
+		{"id":"c","name":"Gamma","tasks":[]}
+	],
+	"backburner": [
+		{"id":"x1","name":"X1","description":"","notes":"","state":"todo","size":1}
+	],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestCategoryTaskMatrix(t *testing.T) {
+	store := newTestStore(t, matrixBoard)
+
+	matrix, err := store.CategoryTaskMatrix()
+	if err != nil {
+		t.Fatalf("matrix: %v", err)
+	}
+	if len(matrix) != 3 {
+		t.Fatalf("expected 3 active categories, got %d", len(matrix))
+	}
+	want := map[string]map[string]int{
+		"a": {"todo": 2, "doing": 1, "blocked": 0, "done": 0, "delegated": 0},
+		"b": {"todo": 0, "doing": 0, "blocked": 1, "done": 1, "delegated": 0},
+		"c": {"todo": 0, "doing": 0, "blocked": 0, "done": 0, "delegated": 0},
+	}
+	for catID, row := range want {
+		for state, n := range row {
+			if got := matrix[catID][state]; got != n {
+				t.Fatalf("matrix[%s][%s] = %d, want %d", catID, state, got, n)
+			}
+		}
+	}
+
+	stats := store.GetStats()
+	if stats.StateTotals["todo"] != 2 || stats.StateTotals["done"] != 1 {
+		t.Fatalf("expected backburner excluded from totals, got %v", stats.StateTotals)
+	}
+	if stats.StateMatrix["a"]["todo"] != 2 {
+		t.Fatalf("expected matrix in board stats")
+	}
+}
+
+func TestStateMatrixEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, matrixBoard))
+
+	rec := doRequest(t, srv, http.MethodGet, "/api/board/matrix/states", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Totals     map[string]int            `json:"totals"`
+		Categories map[string]map[string]int `json:"categories"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Totals["blocked"] != 1 || resp.Categories["b"]["blocked"] != 1 {
+		t.Fatalf("unexpected matrix response %+v", resp)
+	}
+}
+
+func TestCountCategories(t *testing.T) {
+	store := newTestStore(t, swapBoard)
+
+	active, backburner, archive, err := store.CountCategories()
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if active != 4 || backburner != 1 || archive != 0 {
+		t.Fatalf("expected 4/1/0, got %d/%d/%d", active, backburner, archive)
+	}
+
+	if _, _, err := store.MoveCategory("a", MoveCategoryRequest{Location: LocationArchive}); err != nil {
+		t.Fatalf("archive category: %v", err)
+	}
+	if _, _, err := store.MoveCategory("shelved", MoveCategoryRequest{Location: LocationCategoryBoard}); err != nil {
+		t.Fatalf("restore category: %v", err)
+	}
+	active, backburner, archive, _ = store.CountCategories()
+	if active != 4 || backburner != 0 || archive != 1 {
+		t.Fatalf("expected 4/0/1 after moves, got %d/%d/%d", active, backburner, archive)
+	}
+	if got := store.GetStats().CategoryCounts; got != (CategoryCounts{Active: 4, Archive: 1, Limit: CategoryLimit}) {
+		t.Fatalf("unexpected stats counts %+v", got)
+	}
+
+	srv := NewServer(store)
+	var counts map[string]int
+	decodeBody(t, doRequest(t, srv, http.MethodGet, "/api/v1/board/category-counts", ""), &counts)
+	if counts["active"] != 4 || counts["archive"] != 1 || counts["limit"] != CategoryLimit {
+		t.Fatalf("unexpected counts %+v", counts)
+	}
+	var single struct {
+		Pool  string `json:"pool"`
+		Count int    `json:"count"`
+		Limit int    `json:"limit"`
+	}
+	decodeBody(t, doRequest(t, srv, http.MethodGet, "/api/v1/board/category-counts?pool=archive", ""), &single)
+	if single.Pool != "archive" || single.Count != 1 || single.Limit != CategoryLimit {
+		t.Fatalf("unexpected single-pool response %+v", single)
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/board/category-counts?pool=nope", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown pool, got %d", rec.Code)
+	}
+}
+
+func TestDelegatedToRequiredAndGrouped(t *testing.T) {
+	store := newTestStore(t, matrixBoard)
+	delegated := "delegated"
+
+	if _, _, err := store.UpdateTask("a1", TaskPatch{State: &delegated}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest without delegatedTo, got %v", err)
+	}
+	sam := "Sam"
+	task, _, err := store.UpdateTask("a1", TaskPatch{State: &delegated, DelegatedTo: &sam})
+	if err != nil {
+		t.Fatalf("delegate: %v", err)
+	}
+	if task.DelegatedTo != "Sam" {
+		t.Fatalf("expected delegatedTo Sam, got %q", task.DelegatedTo)
+	}
+	if _, _, err := store.UpdateTask("a2", TaskPatch{State: &delegated, DelegatedTo: &sam}); err != nil {
+		t.Fatalf("delegate a2: %v", err)
+	}
+	if _, _, err := store.CreateTask(CreateTaskRequest{CategoryID: "c", Task: Task{Name: "New", State: "delegated", DelegatedTo: "Ana", Size: 1}}); err != nil {
+		t.Fatalf("create delegated: %v", err)
+	}
+	if got := store.GetStats().Delegates; got["Sam"] != 2 || got["Ana"] != 1 {
+		t.Fatalf("expected Sam:2 Ana:1, got %v", got)
+	}
+
+	todo := "todo"
+	task, _, err = store.UpdateTask("a1", TaskPatch{State: &todo})
+	if err != nil {
+		t.Fatalf("undelegate: %v", err)
+	}
+	if task.DelegatedTo != "" {
+		t.Fatalf("expected delegatedTo cleared, got %q", task.DelegatedTo)
+	}
+}
+
+const archiveStatsBoard = `{
+	"categories": [],
+	"backburner": [
+		{"id":"k1","name":"K1","description":"","notes":"","state":"todo","size":1,"source":"Alpha","createdAt":"2025-02-01T00:00:00Z"},
+		{"id":"k2","name":"K2","description":"","notes":"","state":"blocked","size":3,"createdAt":"2025-01-01T00:00:00Z"}
+	],
+	"archives": [
+		{"id":"r1","name":"R1","description":"","notes":"","state":"done","size":2,"source":"Beta","completedAt":"2025-03-05T00:00:00Z"},
+		{"id":"r2","name":"R2","description":"","notes":"","state":"done","size":1,"source":"Alpha","completedAt":"2025-03-01T00:00:00Z"},
+		{"id":"r3","name":"R3","description":"","notes":"","state":"todo","size":3,"source":"Alpha"},
+		{"id":"r4","name":"R4","description":"","notes":"","state":"done","size":2,"source":"Beta","completedAt":"2025-03-09T00:00:00Z"}
+	],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestGetArchiveStats(t *testing.T) {
+	store := newTestStore(t, archiveStatsBoard)
+
+	stats, err := store.GetArchiveStats()
+	if err != nil {
+		t.Fatalf("archive stats: %v", err)
+	}
+	if stats.TotalTasks != 4 || stats.ByState["done"] != 3 || stats.ByState["todo"] != 1 {
+		t.Fatalf("unexpected totals %+v", stats)
+	}
+	if stats.BySizeDistribution[2] != 2 || stats.BySizeDistribution[1] != 1 || stats.BySizeDistribution[3] != 1 {
+		t.Fatalf("unexpected size distribution %v", stats.BySizeDistribution)
+	}
+	if stats.AverageSize != 2 {
+		t.Fatalf("expected average size 2, got %v", stats.AverageSize)
+	}
+	// r3 has no completion time and never counts as oldest
+	if stats.OldestTask == nil || stats.OldestTask.ID != "r2" {
+		t.Fatalf("expected r2 oldest, got %+v", stats.OldestTask)
+	}
+	if stats.NewestTask == nil || stats.NewestTask.ID != "r4" {
+		t.Fatalf("expected r4 newest, got %+v", stats.NewestTask)
+	}
+	if got := fmt.Sprint(stats.UniqueSourceCategories); got != "[Alpha Beta]" {
+		t.Fatalf("expected deduplicated sources, got %s", got)
+	}
+}
+
+func TestGetBackburnerStats(t *testing.T) {
+	srv := NewServer(newTestStore(t, archiveStatsBoard))
+	rec := doRequest(t, srv, http.MethodGet, "/api/v1/backburner/stats", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var stats BackburnerStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if stats.TotalTasks != 2 || stats.OldestTask.ID != "k2" || stats.NewestTask.ID != "k1" {
+		t.Fatalf("unexpected backburner stats %+v", stats)
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/archives/stats", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for archive stats, got %d", rec.Code)
+	}
+}
+
+func TestArchiveStatsEmpty(t *testing.T) {
+	store := newTestStore(t, matrixBoard)
+	stats, err := store.GetArchiveStats()
+	if err != nil {
+		t.Fatalf("archive stats: %v", err)
+	}
+	if stats.TotalTasks != 0 || stats.AverageSize != 0 || stats.OldestTask != nil || len(stats.UniqueSourceCategories) != 0 {
+		t.Fatalf("expected zero stats, got %+v", stats)
+	}
+}
+
+func TestStatsFlatCategoryCounts(t *testing.T) {
+	store := newTestStore(t, matrixBoard)
+	if _, _, err := store.MoveCategory("b", MoveCategoryRequest{Location: LocationBackburner}); err != nil {
+		t.Fatalf("backburner category: %v", err)
+	}
+	if _, _, err := store.MoveCategory("c", MoveCategoryRequest{Location: LocationArchive}); err != nil {
+		t.Fatalf("archive category: %v", err)
+	}
+
+	var stats map[string]any
+	decodeBody(t, doRequest(t, NewServer(store), http.MethodGet, "/api/v1/board/stats", ""), &stats)
+	for key, want := range map[string]float64{"activeCategories": 1, "backburnerCategories": 1, "archivedCategories": 1} {
+		if stats[key] != want {
+			t.Fatalf("expected %s %v, got %v", key, want, stats[key])
+		}
+	}
+}
+
+const backlogBoard = `{
+	"categories": [
+		{"id":"backlog","name":"Backlog","tasks":[
+			{"id":"b1","name":"Stuck","description":"","notes":"","state":"blocked","size":2,"blockedReason":"waiting"},
+			{"id":"b2","name":"Shipped","description":"","notes":"","state":"done","size":1},
+			{"id":"b3","name":"Next","description":"","notes":"","state":"todo","size":1,"urgent":true},
+			{"id":"b4","name":"Later","description":"","notes":"","state":"todo","size":1}
+		]},
+		{"id":"quiet","name":"Quiet","tasks":[
+			{"id":"q1","name":"Going","description":"","notes":"","state":"doing","size":3}
+		]}
+	],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestGetBacklogSummary(t *testing.T) {
+	store := newTestStore(t, backlogBoard)
+
+	summary, err := store.GetBacklogSummary("backlog")
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	if summary.ActiveCount != 3 || summary.BlockedCount != 1 || summary.TotalSize != 5 {
+		t.Fatalf("expected 3 active, 1 blocked, size 5, got %+v", summary)
+	}
+	if len(summary.Blocked) != 1 || summary.Blocked[0].ID != "b1" || summary.Blocked[0].BlockedReason != "waiting" {
+		t.Fatalf("expected b1 listed as blocked with its reason, got %+v", summary.Blocked)
+	}
+	if summary.UrgentTask == nil || summary.UrgentTask.ID != "b3" {
+		t.Fatalf("expected urgent b3, got %+v", summary.UrgentTask)
+	}
+	if summary.NextToStart.ID != "b3" {
+		t.Fatalf("expected b3 next to start, got %q", summary.NextToStart.ID)
+	}
+
+	quiet, err := store.GetBacklogSummary("quiet")
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	if quiet.UrgentTask != nil || quiet.NextToStart.ID != "" || quiet.ActiveCount != 1 || quiet.TotalSize != 3 {
+		t.Fatalf("expected no urgent or todo task, got %+v", quiet)
+	}
+
+	if _, err := store.GetBacklogSummary("missing"); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestCategorySummaryEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, backlogBoard))
+
+	rec := doRequest(t, srv, http.MethodGet, "/api/v1/categories/quiet/summary", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var raw map[string]json.RawMessage
+	decodeBody(t, rec, &raw)
+	if string(raw["urgentTask"]) != "null" || string(raw["blockedCount"]) != "0" {
+		t.Fatalf("unexpected summary %s", rec.Body.String())
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/categories/missing/summary", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
@@ -1,32 +1,118 @@
 package app
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"math/rand"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 type Store struct {
 	mu    sync.RWMutex
 	state BoardState
 	path  string
+	// dataDir holds the data file, above any dated component of the path;
+	// admin endpoints may only write boards inside it.
+	dataDir string
+	// pathTemplate is the data path as given when it contains DateToken;
+	// path then holds the file for the current day.
+	pathTemplate string
+
+	now      func() time.Time
+	location *time.Location
+
+	resetEmpty      bool
+	notes           notesLimit
+	linkSchemes     map[string]struct{}
+	restorePosition bool
+	// maxFileBytes caps the serialized board; zero means no cap.
+	maxFileBytes int64
+
+	// unloggedAt is when the board last changed without an audit event: a
+	// write that recorded none, or a board read from disk. Diffs cannot
+	// reach back past it.
+	unloggedAt time.Time
+
+	// taskIndex maps task IDs to their location in state; rebuilt on every
+	// write and verified on use, so a stale entry only costs a full scan.
+	taskIndex map[string]taskLocation
+
+	// saveTimes holds recent saveLocked durations, guarded by mu.
+	saveTimes latencyRing
+
+	subMu sync.Mutex
+	subs  map[chan BoardEvent]struct{}
+
+	// flight coalesces concurrent GetStateSF calls; guarded by flightMu.
+	flightMu sync.Mutex
+	flight   *boardFlight
 }
 
-func NewStore(path string) (*Store, error) {
-	s := &Store{path: path}
-	if err := s.loadOrSeed(); err != nil {
+func NewStore(path string, opts ...StoreOption) (*Store, error) {
+	s := &Store{
+		path:        path,
+		dataDir:     dataDirOf(path),
+		now:         time.Now,
+		location:    time.Local,
+		linkSchemes: map[string]struct{}{"http": {}, "https": {}},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if isPathTemplate(path) {
+		s.pathTemplate = path
+		if err := s.openTemplateLocked(); err != nil {
+			return nil, err
+		}
+	} else if err := s.loadOrSeed(); err != nil {
+		return nil, err
+	}
+	// a board already over the cap could never be saved again
+	if _, err := s.encodeLocked(); err != nil {
 		return nil, err
 	}
+	s.unloggedAt = s.now()
 	return s, nil
 }
 
+// SeedEnv names the environment variable that may carry a board JSON used in
+// place of the demo seed when no data file exists yet.
+const SeedEnv = "TWENTYFIVE_SEED"
+
+// initialBoard returns the board a new data file starts with: the board in
+// SeedEnv when it holds a valid one, otherwise the demo seed stamped at now.
+func initialBoard(now time.Time) BoardState {
+	raw := strings.TrimSpace(os.Getenv(SeedEnv))
+	if raw == "" {
+		return seedBoard(now)
+	}
+	var board BoardState
+	if err := json.Unmarshal([]byte(raw), &board); err != nil {
+		log.Printf("warning: ignoring %s: decode: %v", SeedEnv, err)
+		return seedBoard(now)
+	}
+	normalizeBoardState(&board)
+	if err := validateBoardState(board); err != nil {
+		log.Printf("warning: ignoring %s: %v", SeedEnv, err)
+		return seedBoard(now)
+	}
+	return board
+}
+
+// loadOrSeed reads the data file into s.state, seeding it when absent or
+// empty. Callers must hold s.mu or have exclusive access to s.
 func (s *Store) loadOrSeed() error {
 	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
 		return fmt.Errorf("create data dir: %w", err)
@@ -35,7 +121,11 @@ func (s *Store) loadOrSeed() error {
 	f, err := os.Open(s.path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			s.state = seedBoard()
+			s.state = initialBoard(s.now())
+			numberTasks(&s.state)
+			if err := s.rebuildIndexLocked(); err != nil {
+				return err
+			}
 			return s.saveLocked()
 		}
 		return fmt.Errorf("open data file: %w", err)
@@ -47,33 +137,410 @@ func (s *Store) loadOrSeed() error {
 		return fmt.Errorf("read data file: %w", err)
 	}
 	if len(data) == 0 {
-		s.state = seedBoard()
+		s.state = initialBoard(s.now())
+		numberTasks(&s.state)
+		if err := s.rebuildIndexLocked(); err != nil {
+			return err
+		}
 		return s.saveLocked()
 	}
 
-	var loaded BoardState
-	if err := json.Unmarshal(data, &loaded); err != nil {
-		return fmt.Errorf("decode data file: %w", err)
+	loaded, err := decodeBoardDocument(data)
+	if err != nil {
+		return fmt.Errorf("load data file: %w", err)
 	}
-
-	normalizeBoardState(&loaded)
 	s.state = loaded
-	return nil
+	numberTasks(&s.state)
+	return s.rebuildIndexLocked()
+}
+
+// SyncFromFile reloads the board from the data file, picking up edits made
+// outside the server, tells subscribers and reports what changed. A file that
+// is missing, empty, fails to decode or is over the size cap leaves the
+// served board in place.
+func (s *Store) SyncFromFile() (StateDiff, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	loaded, err := loadBoardFile(s.path)
+	if err != nil {
+		return StateDiff{}, err
+	}
+	// keep revisions rising so clients tracking them refetch
+	if loaded.Revision <= s.state.Revision {
+		loaded.Revision = s.state.Revision + 1
+	}
+	numberTasks(&loaded)
+	next := s.state
+	replaceBoard(&next, loaded)
+	if _, err := s.encodeCapped(next); err != nil {
+		return StateDiff{}, err
+	}
+	diff := s.state.Diff(next)
+	s.state = next
+	s.taskIndex, _ = buildTaskIndex(&s.state)
+	s.unloggedAt = s.now()
+	s.publish(BoardEvent{Revision: s.state.Revision})
+	return diff, nil
+}
+
+// Open validates and loads the board at path, a file inside the data
+// directory, then swaps it in as the served board and tells subscribers. On
+// failure the current board and path stay in service.
+func (s *Store) Open(path string) (BoardState, error) {
+	path, err := s.dataFilePath(path)
+	if err != nil {
+		return BoardState{}, err
+	}
+	loaded, err := loadBoardFile(path)
+	if err != nil {
+		return BoardState{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// keep revisions rising across the swap so clients tracking them refetch
+	if loaded.Revision <= s.state.Revision {
+		loaded.Revision = s.state.Revision + 1
+	}
+	next := s.state
+	replaceBoard(&next, loaded)
+	numberTasks(&next)
+	// a board over the cap could be served but never saved again
+	if _, err := s.encodeCapped(next); err != nil {
+		return BoardState{}, err
+	}
+	s.state = next
+	s.path = path
+	s.pathTemplate = ""
+	s.taskIndex, _ = buildTaskIndex(&s.state)
+	s.unloggedAt = s.now()
+	s.publish(BoardEvent{Revision: s.state.Revision})
+	return s.snapshotLocked(), nil
+}
+
+// loadBoardFile reads, normalizes and validates the board at path without
+// touching the served board.
+func loadBoardFile(path string) (BoardState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return BoardState{}, fmt.Errorf("%w: %s does not exist", ErrInvalidRequest, path)
+		}
+		return BoardState{}, fmt.Errorf("read data file: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return BoardState{}, fmt.Errorf("%w: %s is empty", ErrInvalidRequest, path)
+	}
+	loaded, err := decodeBoardDocument(data)
+	if err != nil {
+		return BoardState{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return loaded, nil
+}
+
+// Path reports the data file currently being served.
+func (s *Store) Path() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.path
 }
 
 func (s *Store) GetState() BoardState {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.state.Clone()
+	return s.snapshotLocked()
+}
+
+// ResetBoard replaces the current state with a fresh seed board, or an empty
+// board when the store is configured with WithEmptyReset.
+func (s *Store) ResetBoard() (BoardState, error) {
+	return s.withWrite(func(state *BoardState) error {
+		if s.resetEmpty {
+			replaceBoard(state, emptyBoard())
+		} else {
+			replaceBoard(state, seedBoard(s.now()))
+		}
+		return nil
+	})
+}
+
+// Wipe replaces the board with an empty one, regardless of WithEmptyReset,
+// discarding settings and the audit log along with every task and category.
+func (s *Store) Wipe() (BoardState, error) {
+	return s.withWrite(func(state *BoardState) error {
+		replaceBoard(state, emptyBoard())
+		return nil
+	})
+}
+
+// MaxRecentTasks caps how many tasks GetRecentlyModifiedTasks returns.
+const MaxRecentTasks = 100
+
+// GetRecentlyModifiedTasks returns the n most recently modified tasks across
+// every pool, newest first.
+func (s *Store) GetRecentlyModifiedTasks(n int) ([]SearchResult, error) {
+	if n <= 0 || n > MaxRecentTasks {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidRequest, MaxRecentTasks)
+	}
+	s.mu.RLock()
+	results := collectSearchResults(&s.state)
+	s.mu.RUnlock()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Task, results[j].Task
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	if len(results) > n {
+		results = results[:n]
+	}
+	return results, nil
+}
+
+// GetTasksCreatedToday returns every task created during the current calendar
+// day in the store's configured timezone.
+func (s *Store) GetTasksCreatedToday() ([]SearchResult, error) {
+	now := s.now().In(s.location)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+	end := start.AddDate(0, 0, 1)
+
+	s.mu.RLock()
+	all := collectSearchResults(&s.state)
+	s.mu.RUnlock()
+
+	results := []SearchResult{}
+	for _, result := range all {
+		created := result.Task.CreatedAt
+		if !created.Before(start) && created.Before(end) {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// validateBoardState checks that a loaded board is well formed. Column
+// capacity is left to checkBoardCapacity: forced creates and a switch back to
+// hard capacity leave columns over it, and the server must still reopen the
+// boards it wrote.
+func validateBoardState(state BoardState) error {
+	if len(state.Categories) > CategoryLimit {
+		return ErrCategoryLimit
+	}
+	switch state.CapacityMode {
+	case "", CapacityHard, CapacitySoft:
+	default:
+		return fmt.Errorf("%w: unknown capacity mode %q", ErrInvalidRequest, state.CapacityMode)
+	}
+	if state.Revision < 0 || state.StaleAfterDays < 0 || state.MaxTasks < 0 {
+		return fmt.Errorf("%w: revision, staleAfterDays and maxTasks cannot be negative", ErrInvalidRequest)
+	}
+	if len(state.States) > 0 {
+		if err := validateStateDefs(state.States); err != nil {
+			return err
+		}
+	}
+	if len(state.SizeScale) > 0 || state.Capacity != 0 {
+		if err := validateSizeScale(state.Sizes(), state.ColumnLimit()); err != nil {
+			return err
+		}
+	}
+	categoryIDs := map[string]struct{}{}
+	taskIDs := map[string]struct{}{}
+	checkTask := func(task Task) error {
+		if task.ID == "" {
+			return fmt.Errorf("%w: task missing id", ErrInvalidRequest)
+		}
+		if _, dup := taskIDs[task.ID]; dup {
+			return fmt.Errorf("%w: duplicate task id %s", ErrInvalidRequest, task.ID)
+		}
+		taskIDs[task.ID] = struct{}{}
+		if err := state.ValidateTaskState(task.State); err != nil {
+			return fmt.Errorf("task %s: %w", task.ID, err)
+		}
+		if _, err := state.NormalizeSize(task.Size); err != nil {
+			return fmt.Errorf("task %s: %w", task.ID, err)
+		}
+		return nil
+	}
+	checkCategories := func(categories []Category) error {
+		for _, cat := range categories {
+			if cat.ID == "" {
+				return fmt.Errorf("%w: category missing id", ErrInvalidRequest)
+			}
+			if cat.MaxTasks < 0 || (cat.LastBoardIndex != nil && *cat.LastBoardIndex < 0) {
+				return fmt.Errorf("%w: category %s: maxTasks and lastBoardIndex cannot be negative", ErrInvalidRequest, cat.ID)
+			}
+			if _, dup := categoryIDs[cat.ID]; dup {
+				return fmt.Errorf("%w: duplicate category id %s", ErrInvalidRequest, cat.ID)
+			}
+			categoryIDs[cat.ID] = struct{}{}
+			for _, task := range cat.Tasks {
+				if err := checkTask(task); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, categories := range [][]Category{state.Categories, state.CategoryBackburner, state.CategoryArchives} {
+		if err := checkCategories(categories); err != nil {
+			return err
+		}
+	}
+	for _, tasks := range [][]Task{state.Backburner, state.Archives, state.Inbox} {
+		for _, task := range tasks {
+			if err := checkTask(task); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkBoardCapacity reports the first active category of a hard-capacity
+// board that is over its column limit.
+func checkBoardCapacity(state BoardState) error {
+	if state.CapacityMode == CapacitySoft {
+		return nil
+	}
+	for _, cat := range state.Categories {
+		if err := ensureCapacity(cat, state.ColumnLimit()); err != nil {
+			return fmt.Errorf("category %s: %w", cat.ID, err)
+		}
+	}
+	return nil
+}
+
+// GetCompletedTasksBetween returns done or delegated tasks whose completion
+// time falls in [from, to).
+func (s *Store) GetCompletedTasksBetween(from, to time.Time) ([]SearchResult, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: range end precedes start", ErrInvalidRequest)
+	}
+	s.mu.RLock()
+	all := collectSearchResults(&s.state)
+	s.mu.RUnlock()
+
+	results := []SearchResult{}
+	for _, result := range all {
+		task := result.Task
+		if !IsCompletedState(task.State) || task.CompletedAt == nil {
+			continue
+		}
+		if !task.CompletedAt.Before(from) && task.CompletedAt.Before(to) {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// GetCompletedTasksThisWeek returns tasks completed during the current ISO
+// week (Monday through Sunday) in the store's configured timezone.
+func (s *Store) GetCompletedTasksThisWeek() ([]SearchResult, error) {
+	now := s.now().In(s.location)
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	start := time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, s.location)
+	return s.GetCompletedTasksBetween(start, start.AddDate(0, 0, 7))
+}
+
+// GetUrgentTasks returns the urgent tasks in active categories, in category
+// order. Tasks outside active categories never carry the urgent flag, and a
+// category holds at most one, so there are never more than CategoryLimit.
+func (s *Store) GetUrgentTasks() ([]Task, error) {
+	urgent := s.UrgentTasks()
+	tasks := make([]Task, len(urgent))
+	for i, u := range urgent {
+		tasks[i] = u.Task
+	}
+	return tasks, nil
+}
+
+// UrgentTasks is GetUrgentTasks with the category each task belongs to.
+func (s *Store) UrgentTasks() []UrgentTask {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	results := []UrgentTask{}
+	for _, cat := range s.state.Categories {
+		for _, task := range cat.Tasks {
+			if task.Urgent {
+				results = append(results, UrgentTask{Task: task.Clone(), CategoryID: cat.ID, CategoryName: cat.Name})
+			}
+		}
+	}
+	return results
+}
+
+// GetTasksByChecklist finds tasks anywhere on the board by their checklist.
+// A non-nil hasChecklist keeps only tasks with (true) or without (false)
+// checklist items. A non-nil allDone keeps tasks whose items are all done
+// (true) or that have at least one open item (false); either way a task
+// with no items does not match. Both filters must hold.
+func (s *Store) GetTasksByChecklist(hasChecklist, allDone *bool) ([]SearchResult, error) {
+	s.mu.RLock()
+	all := collectSearchResults(&s.state)
+	s.mu.RUnlock()
+
+	results := []SearchResult{}
+	for _, result := range all {
+		items := result.Task.Checklist
+		if hasChecklist != nil && (len(items) > 0) != *hasChecklist {
+			continue
+		}
+		if allDone != nil {
+			open := 0
+			for _, item := range items {
+				if !item.Done {
+					open++
+				}
+			}
+			if len(items) == 0 || (open == 0) != *allDone {
+				continue
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// GetArchivedByCategory gathers the archived tasks that came from one source
+// category into a synthetic category. The name comes from the category's own
+// archive entry when there is one, otherwise from the tasks' Source. A source
+// with no archived tasks yields an empty category.
+func (s *Store) GetArchivedByCategory(sourceID string) (Category, error) {
+	if sourceID == "" {
+		return Category{}, fmt.Errorf("%w: source category id required", ErrInvalidRequest)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cat := Category{ID: sourceID, Tasks: []Task{}}
+	for _, task := range s.state.Archives {
+		if task.SourceID != sourceID {
+			continue
+		}
+		if cat.Name == "" {
+			cat.Name = task.Source
+		}
+		cat.Tasks = append(cat.Tasks, task.Clone())
+	}
+	if idx := findCategoryIndex(s.state.CategoryArchives, sourceID); idx != -1 {
+		cat.Name = s.state.CategoryArchives[idx].Name
+	}
+	return cat, nil
 }
 
 func normalizeBoardState(state *BoardState) {
+	clearProjection(state)
 	if state.Categories == nil {
 		state.Categories = []Category{}
 	}
 	if state.Backburner == nil {
 		state.Backburner = []Task{}
 	}
+	if state.Inbox == nil {
+		state.Inbox = []Task{}
+	}
 	if state.Archives == nil {
 		state.Archives = []Task{}
 	}
@@ -86,12 +553,57 @@ func normalizeBoardState(state *BoardState) {
 }
 
 func (s *Store) saveLocked() error {
-	data, err := json.MarshalIndent(s.state, "", "  ")
+	// the first write after midnight lands in the new day's file
+	if _, err := s.rollLocked(); err != nil {
+		return err
+	}
+	start := time.Now()
+	data, err := s.encodeLocked()
+	if err == nil {
+		err = writeFileAtomic(s.path, data)
+	}
+	s.saveTimes.record(time.Since(start))
+	return err
+}
+
+// encodeLocked serializes the board as it would be saved, enforcing the
+// configured size cap.
+func (s *Store) encodeLocked() ([]byte, error) {
+	return s.encodeCapped(s.state)
+}
+
+// encodeCapped serializes state as a data file, failing with
+// ErrBoardTooLarge when it is over the configured cap.
+func (s *Store) encodeCapped(state BoardState) ([]byte, error) {
+	data, err := encodeBoardFile(state)
+	if err != nil {
+		return nil, err
+	}
+	if s.maxFileBytes > 0 && int64(len(data)) > s.maxFileBytes {
+		return nil, fmt.Errorf("%w: %d bytes is over the %d byte cap", ErrBoardTooLarge, len(data), s.maxFileBytes)
+	}
+	return data, nil
+}
+
+func encodeBoardFile(state BoardState) ([]byte, error) {
+	data, err := json.MarshalIndent(newBoardFile(state), "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshal board: %w", err)
+		return nil, fmt.Errorf("marshal board: %w", err)
 	}
+	return data, nil
+}
 
-	tmpFile, err := os.CreateTemp(filepath.Dir(s.path), "board-*.json")
+// writeBoardFile atomically writes state to path via a temp file and rename.
+func writeBoardFile(path string, state BoardState) error {
+	data, err := encodeBoardFile(state)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "board-*.json")
 	if err != nil {
 		return fmt.Errorf("create temp file: %w", err)
 	}
@@ -112,37 +624,163 @@ func (s *Store) saveLocked() error {
 		return fmt.Errorf("close temp file: %w", err)
 	}
 
-	if err := os.Rename(tmpPath, s.path); err != nil {
+	if err := os.Rename(tmpPath, path); err != nil {
 		os.Remove(tmpPath)
 		return fmt.Errorf("rename temp file: %w", err)
 	}
 	return nil
 }
 
+// CloneTo snapshots the current board into a new data file inside the data
+// directory while the store keeps serving its own, and returns the file
+// written. Existing files are only replaced when force is set.
+func (s *Store) CloneTo(path string, regenerateIDs, force bool) (string, BoardState, error) {
+	target, err := s.dataFilePath(path)
+	if err != nil {
+		return "", BoardState{}, err
+	}
+	current, err := filepath.Abs(s.Path())
+	if err != nil {
+		return "", BoardState{}, fmt.Errorf("resolve data path: %w", err)
+	}
+	if target == current {
+		return "", BoardState{}, fmt.Errorf("%w: cannot clone onto the served data file", ErrInvalidRequest)
+	}
+	if _, err := os.Stat(target); err == nil && !force {
+		return "", BoardState{}, ErrFileExists
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", BoardState{}, fmt.Errorf("stat clone path: %w", err)
+	}
+
+	s.mu.RLock()
+	snapshot := s.state.Clone()
+	s.mu.RUnlock()
+
+	normalizeBoardState(&snapshot)
+	if regenerateIDs {
+		regenerateBoardIDs(&snapshot)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", BoardState{}, fmt.Errorf("create clone dir: %w", err)
+	}
+	if err := writeBoardFile(target, snapshot); err != nil {
+		return "", BoardState{}, err
+	}
+	return target, snapshot, nil
+}
+
+// dataDirOf returns the directory holding the data file at path, stepping
+// above any component that rolls by date.
+func dataDirOf(path string) string {
+	dir := filepath.Dir(path)
+	for isPathTemplate(dir) {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// DataDir reports the directory that admin endpoints may write boards to.
+func (s *Store) DataDir() string {
+	return s.dataDir
+}
+
+// dataFilePath resolves path against the data directory, refusing any path
+// that would land outside it.
+func (s *Store) dataFilePath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("%w: path required", ErrInvalidRequest)
+	}
+	root, err := filepath.Abs(s.dataDir)
+	if err != nil {
+		return "", fmt.Errorf("resolve data dir: %w", err)
+	}
+	target := path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	target = filepath.Clean(target)
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is outside the data directory", ErrInvalidRequest, path)
+	}
+	return target, nil
+}
+
+// regenerateBoardIDs assigns fresh IDs to every category and task, keeping
+// task source references and blockers pointed at the renamed entities.
+func regenerateBoardIDs(state *BoardState) {
+	categoryIDs := map[string]string{}
+	taskIDs := map[string]string{}
+	for _, pool := range [][]Category{state.Categories, state.CategoryBackburner, state.CategoryArchives} {
+		for i := range pool {
+			id := NewID()
+			categoryIDs[pool[i].ID] = id
+			pool[i].ID = id
+		}
+	}
+	forEachPoolTask(state, func(task *Task, _ bool) {
+		id := NewID()
+		taskIDs[task.ID] = id
+		task.ID = id
+	})
+	forEachPoolTask(state, func(task *Task, _ bool) {
+		if id, ok := categoryIDs[task.SourceID]; ok {
+			task.SourceID = id
+		}
+		for i, blocker := range task.BlockedBy {
+			if id, ok := taskIDs[blocker]; ok {
+				task.BlockedBy[i] = id
+			}
+		}
+	})
+}
+
 func (s *Store) withWrite(lockFn func(state *BoardState) error) (BoardState, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	revision := s.state.Revision
+	// with a size cap a write can be refused after it is applied, so keep
+	// the board to put back
+	var prior *BoardState
+	if s.maxFileBytes > 0 {
+		saved := s.state.Clone()
+		normalizeBoardState(&saved)
+		prior = &saved
+	}
+	s.state.notes = s.notes
+	s.state.logged = 0
 	if err := lockFn(&s.state); err != nil {
 		return BoardState{}, err
 	}
+	// tasks brought in by imports and copies take their numbers here
+	numberTasks(&s.state)
+	s.state.Revision = revision + 1
+	s.taskIndex, _ = buildTaskIndex(&s.state)
 	if err := s.saveLocked(); err != nil {
+		if prior != nil {
+			s.state = *prior
+			s.taskIndex, _ = buildTaskIndex(&s.state)
+		}
 		return BoardState{}, err
 	}
-	return s.state.Clone(), nil
+	if s.state.logged == 0 {
+		s.unloggedAt = s.now()
+	}
+	s.publish(BoardEvent{Revision: s.state.Revision})
+	return s.snapshotLocked(), nil
 }
 
 // CreateTask inserts a task into the requested location.
 func (s *Store) CreateTask(req CreateTaskRequest) (Task, BoardState, error) {
-	req.Normalize()
-	if err := req.Validate(); err != nil {
+	if err := s.prepareCreate(&req); err != nil {
 		return Task{}, BoardState{}, err
 	}
-
 	var created Task
 	updatedState, err := s.withWrite(func(state *BoardState) error {
 		var err error
-		created, err = state.insertTask(req)
+		created, err = createTask(state, req)
 		return err
 	})
 	if err != nil {
@@ -151,16 +789,135 @@ func (s *Store) CreateTask(req CreateTaskRequest) (Task, BoardState, error) {
 	return created, updatedState, nil
 }
 
+// CreateTasks inserts a batch of tasks into one location, in order, in a
+// single write. Every task is checked before any is placed, and the batch is
+// placed on a copy of the board, so a task that fails validation or does not
+// fit alongside the others leaves the board untouched.
+func (s *Store) CreateTasks(req CreateTasksRequest) ([]Task, BoardState, error) {
+	if len(req.Tasks) == 0 {
+		return nil, BoardState{}, fmt.Errorf("%w: tasks required", ErrInvalidRequest)
+	}
+	items := make([]CreateTaskRequest, len(req.Tasks))
+	for i, task := range req.Tasks {
+		items[i] = CreateTaskRequest{Location: req.Location, CategoryID: req.CategoryID, Task: task, Force: req.Force, Actor: req.Actor}
+		if req.Position != nil {
+			position := *req.Position + i
+			items[i].Position = &position
+		}
+		if err := s.prepareCreate(&items[i]); err != nil {
+			return nil, BoardState{}, fmt.Errorf("tasks[%d]: %w", i, err)
+		}
+	}
+
+	created := make([]Task, 0, len(items))
+	updatedState, err := s.withWrite(func(current *BoardState) error {
+		next := current.Clone()
+		normalizeBoardState(&next)
+		for i, item := range items {
+			task, err := createTask(&next, item)
+			if err != nil {
+				return fmt.Errorf("tasks[%d]: %w", i, err)
+			}
+			created = append(created, task)
+		}
+		*current = next
+		return nil
+	})
+	if err != nil {
+		return nil, BoardState{}, err
+	}
+	return created, updatedState, nil
+}
+
+// prepareCreate normalizes and checks a create request against everything
+// that does not depend on the board, stamping the task's times.
+func (s *Store) prepareCreate(req *CreateTaskRequest) error {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if err := s.checkLinks(req.Task.Links); err != nil {
+		return err
+	}
+	var err error
+	if req.Task.BlockedBy, err = trimBlockers(req.Task.BlockedBy); err != nil {
+		return err
+	}
+	stamp := s.now()
+	if req.Task.CreatedAt.IsZero() {
+		req.Task.CreatedAt = stamp
+	}
+	req.Task.UpdatedAt = stamp
+	stampCompletion(&req.Task, "", stamp)
+	if req.Task.State != "blocked" {
+		req.Task.BlockedReason = ""
+	}
+	return nil
+}
+
+// createTask places a prepared task on the board and records its creation.
+func createTask(state *BoardState, req CreateTaskRequest) (Task, error) {
+	// only a caller-chosen ID can already be named as a blocker, so a
+	// generated one cannot close a cycle
+	if req.Task.ID != "" && len(req.Task.BlockedBy) > 0 {
+		_, blockers := dependencyGraph(state)
+		blockers[req.Task.ID] = req.Task.BlockedBy
+		if err := checkNoCycle(blockers, req.Task.ID); err != nil {
+			return Task{}, err
+		}
+	}
+	created, err := state.insertTask(req)
+	if err != nil {
+		return Task{}, err
+	}
+	taskPtr, _, err := findTask(state, created.ID, nil)
+	if err != nil {
+		return Task{}, err
+	}
+	recordEvent(state, taskPtr, AuditEvent{At: req.Task.UpdatedAt, Actor: req.Actor, Action: "create"})
+	return taskPtr.Clone(), nil
+}
+
 func (s *Store) UpdateTask(id string, patch TaskPatch) (Task, BoardState, error) {
-	var updated Task
-	updatedState, err := s.withWrite(func(state *BoardState) error {
-		taskPtr, loc, err := findTask(state, id)
+	update, board, err := s.PatchTask(id, patch)
+	return update.Task, board, err
+}
+
+// TaskUpdate is the outcome of PatchTask.
+type TaskUpdate struct {
+	Task Task
+	// PreviousState is the task's state before the patch was applied.
+	PreviousState string
+}
+
+// PatchTask applies patch like UpdateTask and also reports the state the task
+// was in beforehand.
+func (s *Store) PatchTask(id string, patch TaskPatch) (TaskUpdate, BoardState, error) {
+	if err := s.checkPatch(&patch); err != nil {
+		return TaskUpdate{}, BoardState{}, err
+	}
+	var updated TaskUpdate
+	updatedState, err := s.withWrite(func(current *BoardState) error {
+		// patch a copy so a rejected update leaves the board untouched
+		next := current.Clone()
+		normalizeBoardState(&next)
+		state := &next
+		taskPtr, loc, err := findTask(state, id, s.taskIndex)
 		if err != nil {
 			return err
 		}
-		if err := patch.Apply(taskPtr); err != nil {
+		previousState := taskPtr.State
+		sizeBefore := 0
+		if loc.Kind == LocationCategory {
+			sizeBefore = categorySize(state.Categories[loc.CategoryIndex])
+		}
+		if err := patch.Apply(taskPtr, state); err != nil {
 			return err
 		}
+		taskPtr.UpdatedAt = s.now()
+		stampCompletion(taskPtr, previousState, taskPtr.UpdatedAt)
+		releaseFocusIfDone(taskPtr)
+		recordEvent(state, taskPtr, patchEvent(*taskPtr, previousState, patch.Actor, taskPtr.UpdatedAt))
 		if loc.Kind == LocationCategory {
 			if taskPtr.Urgent {
 				normalizeUrgent(state, loc.CategoryIndex, taskPtr.ID)
@@ -169,61 +926,450 @@ func (s *Store) UpdateTask(id string, patch TaskPatch) (Task, BoardState, error)
 			}
 		}
 		if loc.Kind == LocationCategory {
-			if err := ensureCapacity(state.Categories[loc.CategoryIndex]); err != nil {
+			if err := state.checkCapacity(state.Categories[loc.CategoryIndex], sizeBefore, false); err != nil {
 				return err
 			}
 		}
-		updated = taskPtr.Clone()
+		updated = TaskUpdate{Task: taskPtr.Clone(), PreviousState: previousState}
+		*current = next
+		return nil
+	})
+	if err != nil {
+		return TaskUpdate{}, BoardState{}, err
+	}
+	return updated, updatedState, nil
+}
+
+// ReplaceTask overwrites every client-owned field of task id with task's, for
+// sync tools that hold a full copy of the task rather than a diff. Fields task
+// leaves empty are cleared; server-owned fields are kept. A task ID other than
+// id is rejected, and the result is checked as PatchTask checks a patch.
+func (s *Store) ReplaceTask(id string, task Task, actor string) (TaskUpdate, BoardState, error) {
+	if task.ID != "" && task.ID != id {
+		return TaskUpdate{}, BoardState{}, fmt.Errorf("%w: task id %s does not match %s", ErrInvalidRequest, task.ID, id)
+	}
+	if task.Size < 1 {
+		return TaskUpdate{}, BoardState{}, fmt.Errorf("%w: %d", ErrInvalidTaskSize, task.Size)
+	}
+	patch := replacementPatch(task)
+	patch.Actor = actor
+	return s.PatchTask(id, patch)
+}
+
+// SetTaskSize resizes a task, rechecking capacity when it sits on the active
+// board. A rejected resize leaves the board untouched.
+func (s *Store) SetTaskSize(id string, size int) (Task, BoardState, error) {
+	return s.UpdateTask(id, TaskPatch{Size: &size})
+}
+
+// TouchTask bumps a task's UpdatedAt to now without changing anything else.
+func (s *Store) TouchTask(id string) (Task, BoardState, error) {
+	var touched Task
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		taskPtr, _, err := findTask(state, id, s.taskIndex)
+		if err != nil {
+			return err
+		}
+		taskPtr.UpdatedAt = s.now()
+		touched = taskPtr.Clone()
+		return nil
+	})
+	if err != nil {
+		return Task{}, BoardState{}, err
+	}
+	return touched, updatedState, nil
+}
+
+// SetTaskUrgent flags or clears urgency on a task in an active category.
+// Setting it clears the flag on every other task in that category, since a
+// category holds at most one urgent task; clearing it touches only the
+// target.
+func (s *Store) SetTaskUrgent(id string, urgent bool) (Task, BoardState, error) {
+	var updated Task
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		taskPtr, loc, err := findTask(state, id, s.taskIndex)
+		if err != nil {
+			return err
+		}
+		if loc.Kind != LocationCategory {
+			return fmt.Errorf("%w: only tasks in active categories can be urgent", ErrInvalidRequest)
+		}
+		if urgent {
+			normalizeUrgent(state, loc.CategoryIndex, taskPtr.ID)
+		} else {
+			taskPtr.Urgent = false
+		}
+		taskPtr.UpdatedAt = s.now()
+		updated = taskPtr.Clone()
+		return nil
+	})
+	if err != nil {
+		return Task{}, BoardState{}, err
+	}
+	return updated, updatedState, nil
+}
+
+func (s *Store) MoveTask(id string, dest MoveTaskRequest) (Task, BoardState, error) {
+	var moved Task
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		if dest.Location == LocationCategory && dest.CategoryID == "" && dest.CategoryName != "" {
+			catID, err := resolveCategoryName(state.Categories, dest.CategoryName)
+			if err != nil {
+				return err
+			}
+			dest.CategoryID = catID
+		}
+		// capture the origin category by identity before removing the task;
+		// a location index is only meaningful against the board it was read from
+		_, origin, err := findTask(state, id, s.taskIndex)
+		if err != nil {
+			return err
+		}
+		var originID, originName string
+		if origin.Kind == LocationCategory {
+			originID = state.Categories[origin.CategoryIndex].ID
+			originName = state.Categories[origin.CategoryIndex].Name
+		}
+		task, loc, err := removeTask(state, id, s.taskIndex)
+		if err != nil {
+			return err
+		}
+		original := task.Clone()
+		task.UpdatedAt = s.now()
+		task.MoveCount++
+
+		destCopy := dest
+		if (destCopy.Location == LocationBackburner || destCopy.Location == LocationArchive) && destCopy.SourceID == "" {
+			destCopy.SourceID = originID
+			destCopy.Source = originName
+		}
+		if note := strings.TrimSpace(destCopy.ArchiveNote); note != "" && destCopy.Location == LocationArchive {
+			notes, err := state.notes.apply(appendNote(task.Notes, note))
+			if err != nil {
+				restoreTask(state, original, loc)
+				return err
+			}
+			task.Notes = notes
+		}
+
+		if err := state.placeTask(task, destCopy); err != nil {
+			// reinsert original task to preserve state
+			restoreTask(state, original, loc)
+			return err
+		}
+		// report the task as placed, with its source attribution
+		placed, at, err := findTask(state, id, nil)
+		if err != nil {
+			return err
+		}
+		// From and To name the category for active moves, else the pool
+		from, to := loc.Kind, at.Kind
+		if originID != "" {
+			from = originID
+		}
+		if at.Kind == LocationCategory {
+			to = state.Categories[at.CategoryIndex].ID
+		}
+		recordEvent(state, placed, AuditEvent{At: task.UpdatedAt, Actor: dest.Actor, Action: "move", From: from, To: to})
+		moved = placed.Clone()
+		return nil
+	})
+	if err != nil {
+		return Task{}, BoardState{}, err
+	}
+	return moved, updatedState, nil
+}
+
+// MoveTaskBetweenPools moves a task that must currently live in fromPool into
+// toPool. Category-backed destinations resolve their target category from the
+// category holding the task or, for a pool task, its source metadata; a task
+// whose category is not in the destination pool fails with
+// ErrCategoryNotFound.
+func (s *Store) MoveTaskBetweenPools(id, fromPool, toPool string) (Task, BoardState, error) {
+	if !validPool(fromPool) || !validPool(toPool) {
+		return Task{}, BoardState{}, ErrInvalidLocation
+	}
+	if fromPool == toPool {
+		return Task{}, BoardState{}, fmt.Errorf("%w: task is already in pool %s", ErrInvalidRequest, toPool)
+	}
+	var moved Task
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		tasks, idx, owner := locateTaskInPool(state, fromPool, id)
+		if tasks == nil {
+			for _, pool := range taskPools {
+				if list, _, _ := locateTaskInPool(state, pool, id); list != nil {
+					return fmt.Errorf("%w: task %s is in pool %s, not %s", ErrInvalidRequest, id, pool, fromPool)
+				}
+			}
+			return ErrTaskNotFound
+		}
+		if owner != nil && owner.Locked {
+			return errCategoryLocked
+		}
+
+		task := (*tasks)[idx].Clone()
+		sourceID, source := task.SourceID, task.Source
+		if owner != nil {
+			sourceID, source = owner.ID, owner.Name
+		}
+		*tasks = append((*tasks)[:idx], (*tasks)[idx+1:]...)
+
+		moving := task.Clone()
+		moving.UpdatedAt = s.now()
+		moving.MoveCount++
+		placed, err := state.placeTaskInPool(moving, toPool, sourceID, source)
+		if err != nil {
+			// reinsert original task to preserve state
+			*tasks = append(*tasks, Task{})
+			copy((*tasks)[idx+1:], (*tasks)[idx:])
+			(*tasks)[idx] = task
+			return err
+		}
+		list, at, _ := locateTaskInPool(state, toPool, placed.ID)
+		recordEvent(state, &(*list)[at], AuditEvent{At: moving.UpdatedAt, Action: "move", From: fromPool, To: toPool})
+		moved = (*list)[at].Clone()
+		return nil
+	})
+	if err != nil {
+		return Task{}, BoardState{}, err
+	}
+	return moved, updatedState, nil
+}
+
+// BulkPatchTasks applies one patch to every task matching the filter. The
+// patch is applied to a copy of the board so a validation failure on any task
+// leaves the stored state untouched. Dry runs report the outcome without
+// saving.
+func (s *Store) BulkPatchTasks(req BulkPatchRequest) (BulkPatchResult, BoardState, error) {
+	if err := req.Filter.Validate(); err != nil {
+		return BulkPatchResult{}, BoardState{}, err
+	}
+	if err := s.checkPatch(&req.Patch); err != nil {
+		return BulkPatchResult{}, BoardState{}, err
+	}
+	if req.DryRun {
+		s.mu.RLock()
+		next := s.state.Clone()
+		s.mu.RUnlock()
+		next.notes = s.notes
+		normalizeBoardState(&next)
+		result, err := s.bulkPatch(&next, req, s.now())
+		if err != nil {
+			return BulkPatchResult{}, BoardState{}, err
+		}
+		return result, s.GetState(), nil
+	}
+
+	var result BulkPatchResult
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		next := state.Clone()
+		normalizeBoardState(&next)
+		var err error
+		result, err = s.bulkPatch(&next, req, s.now())
+		if err != nil {
+			return err
+		}
+		*state = next
+		return nil
+	})
+	if err != nil {
+		return BulkPatchResult{}, BoardState{}, err
+	}
+	return result, updatedState, nil
+}
+
+// TagTasks adds and removes tags on every listed task in one write. Every ID
+// must exist or nothing changes. The updated tasks are returned in request
+// order.
+func (s *Store) TagTasks(req BulkTagRequest) ([]Task, BoardState, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, BoardState{}, err
+	}
+	var tagged []Task
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		seen := map[string]struct{}{}
+		var targets []*Task
+		for _, id := range req.TaskIDs {
+			if _, dup := seen[id]; dup {
+				continue
+			}
+			seen[id] = struct{}{}
+			task, _, err := findTask(state, id, s.taskIndex)
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, id)
+			}
+			targets = append(targets, task)
+		}
+		now := s.now()
+		tagged = make([]Task, 0, len(targets))
+		for _, task := range targets {
+			tags := make([]string, 0, len(task.Tags)+len(req.Add))
+			for _, tag := range task.Tags {
+				if !hasTag(req.Remove, tag) {
+					tags = append(tags, tag)
+				}
+			}
+			for _, tag := range req.Add {
+				if !hasTag(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+			if !slices.Equal(tags, task.Tags) {
+				task.Tags = tags
+				task.UpdatedAt = now
+				recordEvent(state, task, AuditEvent{At: now, Actor: req.Actor, Action: "update"})
+			}
+			tagged = append(tagged, task.Clone())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, BoardState{}, err
+	}
+	return tagged, updatedState, nil
+}
+
+func (s *Store) bulkPatch(state *BoardState, req BulkPatchRequest, now time.Time) (BulkPatchResult, error) {
+	if req.Filter.State != "" {
+		if err := state.ValidateTaskState(req.Filter.State); err != nil {
+			return BulkPatchResult{}, err
+		}
+	}
+	result := BulkPatchResult{Tasks: []Task{}, DryRun: req.DryRun}
+	// touched maps each patched active category to its size before patching
+	touched := map[int]int{}
+	var patchErr error
+	forEachTask(state, func(task *Task, loc taskLocation) bool {
+		if !req.Filter.Matches(*task, loc, state) {
+			return true
+		}
+		if _, seen := touched[loc.CategoryIndex]; loc.Kind == LocationCategory && !seen {
+			touched[loc.CategoryIndex] = categorySize(state.Categories[loc.CategoryIndex])
+		}
+		before := task.Clone()
+		if err := req.Patch.Apply(task, state); err != nil {
+			patchErr = fmt.Errorf("task %s: %w", task.ID, err)
+			return false
+		}
+		if loc.Kind != LocationCategory {
+			task.Urgent = false
+		}
+		result.Matched++
+		if !sameJSON(before, *task) {
+			task.UpdatedAt = now
+			stampCompletion(task, before.State, now)
+			releaseFocusIfDone(task)
+			recordEvent(state, task, patchEvent(*task, before.State, req.Patch.Actor, now))
+			result.Changed++
+		}
+		result.Tasks = append(result.Tasks, task.Clone())
+		return true
+	})
+	if patchErr != nil {
+		return BulkPatchResult{}, patchErr
+	}
+	for ci, sizeBefore := range touched {
+		urgent := 0
+		for _, task := range state.Categories[ci].Tasks {
+			if task.Urgent {
+				urgent++
+			}
+		}
+		if urgent > 1 {
+			return BulkPatchResult{}, fmt.Errorf("%w: category %s would have more than one urgent task", ErrInvalidRequest, state.Categories[ci].ID)
+		}
+		if err := state.checkCapacity(state.Categories[ci], sizeBefore, false); err != nil {
+			return BulkPatchResult{}, err
+		}
+	}
+	return result, nil
+}
+
+// ArchiveAllDoneTasks moves every done or delegated task on the active board
+// into the archive in one write, preserving board order. Categories left
+// empty stay on the board; locked categories are skipped.
+func (s *Store) ArchiveAllDoneTasks() ([]Task, BoardState, error) {
+	archived := []Task{}
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		now := s.now()
+		for ci := range state.Categories {
+			cat := &state.Categories[ci]
+			if cat.Locked {
+				continue
+			}
+			kept := cat.Tasks[:0]
+			for _, task := range cat.Tasks {
+				if !IsCompletedState(task.State) {
+					kept = append(kept, task)
+					continue
+				}
+				task.Urgent = false
+				task.Focused = false
+				task.SourceID = cat.ID
+				task.Source = cat.Name
+				task.UpdatedAt = now
+				state.Archives = append(state.Archives, task)
+				archived = append(archived, task.Clone())
+			}
+			cat.Tasks = kept
+		}
 		return nil
 	})
 	if err != nil {
-		return Task{}, BoardState{}, err
+		return nil, BoardState{}, err
 	}
-	return updated, updatedState, nil
+	return archived, updatedState, nil
 }
 
-func (s *Store) MoveTask(id string, dest MoveTaskRequest) (Task, BoardState, error) {
-	var moved Task
+// ParkCategory moves every task in an active category to the backburner,
+// in order and remembering where each came from, and leaves the category on
+// the board empty.
+func (s *Store) ParkCategory(id string) ([]Task, BoardState, error) {
+	parked := []Task{}
 	updatedState, err := s.withWrite(func(state *BoardState) error {
-		task, loc, err := removeTask(state, id)
-		if err != nil {
-			return err
+		idx := findCategoryIndex(state.Categories, id)
+		if idx == -1 {
+			return ErrCategoryNotFound
 		}
-
-		destCopy := dest
-		if (destCopy.Location == LocationBackburner || destCopy.Location == LocationArchive) && destCopy.SourceID == "" {
-			if loc.Kind == LocationCategory {
-				cat := state.Categories[loc.CategoryIndex]
-				destCopy.SourceID = cat.ID
-				destCopy.Source = cat.Name
-			}
+		cat := &state.Categories[idx]
+		if cat.Locked {
+			return errCategoryLocked
 		}
-
-		if err := state.placeTask(task, destCopy); err != nil {
-			// reinsert original task to preserve state
-			restoreTask(state, task, loc)
-			return err
+		now := s.now()
+		for _, task := range cat.Tasks {
+			task.Urgent = false
+			task.Focused = false
+			task.SourceID = cat.ID
+			task.Source = cat.Name
+			task.UpdatedAt = now
+			state.Backburner = append(state.Backburner, task)
+			parked = append(parked, task.Clone())
 		}
-		moved = task.Clone()
+		cat.Tasks = []Task{}
 		return nil
 	})
 	if err != nil {
-		return Task{}, BoardState{}, err
+		return nil, BoardState{}, err
 	}
-	return moved, updatedState, nil
+	return parked, updatedState, nil
 }
 
 func (s *Store) DeleteTask(id string) (BoardState, error) {
 	updatedState, err := s.withWrite(func(state *BoardState) error {
-		_, loc, err := findTask(state, id)
+		_, loc, err := findTask(state, id, s.taskIndex)
 		if err != nil {
 			return err
 		}
 		if loc.Kind != LocationArchive {
 			return fmt.Errorf("task %s is not in archive", id)
 		}
-		_, _, err = removeTask(state, id)
-		return err
+		removed, _, err := removeTask(state, id, s.taskIndex)
+		if err != nil {
+			return err
+		}
+		recordEvent(state, &removed, AuditEvent{At: s.now(), Action: "delete"})
+		return nil
 	})
 	return updatedState, err
 }
@@ -265,6 +1411,50 @@ func (s *Store) RenameCategory(id, name string) (Category, BoardState, error) {
 	return cat, updatedState, nil
 }
 
+// SetCategoryTaskLimit sets the category's own task limit; zero falls back to
+// the board default. Lowering it below the current count is allowed and only
+// stops further tasks being added.
+func (s *Store) SetCategoryTaskLimit(id string, max int) (Category, BoardState, error) {
+	if max < 0 {
+		return Category{}, BoardState{}, fmt.Errorf("%w: maxTasks must not be negative", ErrInvalidRequest)
+	}
+	var cat Category
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		for _, pool := range [][]Category{state.Categories, state.CategoryBackburner, state.CategoryArchives} {
+			if idx := findCategoryIndex(pool, id); idx != -1 {
+				pool[idx].MaxTasks = max
+				cat = pool[idx].Clone()
+				return nil
+			}
+		}
+		return ErrCategoryNotFound
+	})
+	if err != nil {
+		return Category{}, BoardState{}, err
+	}
+	return cat, updatedState, nil
+}
+
+// LockCategory sets or clears a category's lock wherever it lives. A locked
+// category rejects tasks being added, removed or moved.
+func (s *Store) LockCategory(id string, locked bool) (Category, BoardState, error) {
+	var cat Category
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		for _, pool := range [][]Category{state.Categories, state.CategoryBackburner, state.CategoryArchives} {
+			if idx := findCategoryIndex(pool, id); idx != -1 {
+				pool[idx].Locked = locked
+				cat = pool[idx].Clone()
+				return nil
+			}
+		}
+		return ErrCategoryNotFound
+	})
+	if err != nil {
+		return Category{}, BoardState{}, err
+	}
+	return cat, updatedState, nil
+}
+
 func (s *Store) CreateCategory(name string) (Category, BoardState, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
@@ -315,6 +1505,9 @@ func (s *Store) MoveCategory(id string, dest MoveCategoryRequest) (Category, Boa
 		if err != nil {
 			return err
 		}
+		if s.restorePosition {
+			rememberBoardIndex(&cat, loc, &dest)
+		}
 		if err := state.placeCategory(cat, dest); err != nil {
 			restoreCategory(state, cat, loc)
 			return err
@@ -328,6 +1521,312 @@ func (s *Store) MoveCategory(id string, dest MoveCategoryRequest) (Category, Boa
 	return moved, updatedState, nil
 }
 
+// CloneCategory deep-copies a category from any pool, with fresh category and
+// task IDs, into the board, the category backburner or the category archive.
+func (s *Store) CloneCategory(id, newName, location string) (Category, BoardState, error) {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return Category{}, BoardState{}, fmt.Errorf("%w: name required", ErrInvalidRequest)
+	}
+	switch location {
+	case LocationCategoryBoard, LocationBackburner, LocationArchive:
+	default:
+		return Category{}, BoardState{}, ErrInvalidLocation
+	}
+
+	var clone Category
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		source, ok := findCategory(state, id)
+		if !ok {
+			return ErrCategoryNotFound
+		}
+		pool := &state.Categories
+		switch location {
+		case LocationBackburner:
+			pool = &state.CategoryBackburner
+		case LocationArchive:
+			pool = &state.CategoryArchives
+		}
+		if findCategoryIndexByName(*pool, newName) != -1 {
+			return ErrDuplicateCategory
+		}
+
+		clone = freshCategoryCopy(source, newName, s.now(), location == LocationCategoryBoard)
+		if location == LocationCategoryBoard {
+			if len(state.Categories) >= CategoryLimit {
+				return ErrCategoryLimit
+			}
+			if err := state.checkCapacity(clone, 0, false); err != nil {
+				return err
+			}
+			if err := state.checkTaskLimit(clone, 0); err != nil {
+				return err
+			}
+		}
+		*pool = append(*pool, clone)
+		clone = clone.Clone()
+		return nil
+	})
+	if err != nil {
+		return Category{}, BoardState{}, err
+	}
+	return clone, updatedState, nil
+}
+
+// DeleteCategoryArchive permanently removes a category from the category
+// archive. Its tasks are discarded, or moved to the task archive when
+// keepTasks is set.
+func (s *Store) DeleteCategoryArchive(id string, keepTasks bool) (BoardState, error) {
+	return s.withWrite(func(state *BoardState) error {
+		idx := findCategoryIndex(state.CategoryArchives, id)
+		if idx == -1 {
+			return ErrCategoryNotFound
+		}
+		cat := state.CategoryArchives[idx]
+		if keepTasks {
+			for _, task := range cat.Tasks {
+				task = task.Clone()
+				task.Urgent = false
+				task.Focused = false
+				task.SourceID = cat.ID
+				task.Source = cat.Name
+				state.Archives = append(state.Archives, task)
+			}
+		}
+		state.CategoryArchives = append(state.CategoryArchives[:idx], state.CategoryArchives[idx+1:]...)
+		return nil
+	})
+}
+
+// ExportCategory returns a standalone copy of a category from any pool.
+func (s *Store) ExportCategory(id string) (Category, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cat, ok := findCategory(&s.state, id)
+	if !ok {
+		return Category{}, ErrCategoryNotFound
+	}
+	clearCategoryFocus(&cat)
+	return cat, nil
+}
+
+// GetTaskAtPosition returns the task at a zero-based position within a
+// category in any pool.
+func (s *Store) GetTaskAtPosition(categoryID string, position int) (Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, pool := range [][]Category{s.state.Categories, s.state.CategoryBackburner, s.state.CategoryArchives} {
+		idx := findCategoryIndex(pool, categoryID)
+		if idx == -1 {
+			continue
+		}
+		tasks := pool[idx].Tasks
+		if position < 0 || position >= len(tasks) {
+			return Task{}, fmt.Errorf("%w: position %d out of range (category has %d tasks)", ErrInvalidRequest, position, len(tasks))
+		}
+		return tasks[position].Clone(), nil
+	}
+	return Task{}, ErrCategoryNotFound
+}
+
+// ImportCategory adds an exported category to the board under fresh IDs,
+// subject to the same name, limit and capacity rules as CreateCategory.
+func (s *Store) ImportCategory(in Category) (Category, BoardState, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return Category{}, BoardState{}, fmt.Errorf("%w: name required", ErrInvalidRequest)
+	}
+	for _, task := range in.Tasks {
+		if err := s.checkLinks(task.Links); err != nil {
+			return Category{}, BoardState{}, err
+		}
+	}
+
+	var cat Category
+	updatedState, err := s.withWrite(func(state *BoardState) error {
+		for _, pool := range [][]Category{state.Categories, state.CategoryBackburner, state.CategoryArchives} {
+			if findCategoryIndexByName(pool, name) != -1 {
+				return ErrDuplicateCategory
+			}
+		}
+		if len(state.Categories) >= CategoryLimit {
+			return ErrCategoryLimit
+		}
+		for _, task := range in.Tasks {
+			if err := state.ValidateTaskState(task.State); err != nil {
+				return err
+			}
+			if _, err := state.NormalizeSize(task.Size); err != nil {
+				return err
+			}
+		}
+		cat = freshCategoryCopy(in, name, s.now(), true)
+		if err := state.checkCapacity(cat, 0, false); err != nil {
+			return err
+		}
+		if err := state.checkTaskLimit(cat, 0); err != nil {
+			return err
+		}
+		state.Categories = append(state.Categories, cat)
+		cat = cat.Clone()
+		return nil
+	})
+	if err != nil {
+		return Category{}, BoardState{}, err
+	}
+	return cat, updatedState, nil
+}
+
+// SetCapacityMode switches the board between hard and soft capacity
+// enforcement. Switching to hard leaves over-limit categories as they are.
+func (s *Store) SetCapacityMode(mode string) (BoardState, error) {
+	return s.UpdateSettings(BoardSettingsPatch{CapacityMode: &mode})
+}
+
+// UpdateSettings applies board-level settings. Every field is validated
+// before any is written.
+func (s *Store) UpdateSettings(patch BoardSettingsPatch) (BoardState, error) {
+	if patch.CapacityMode != nil {
+		switch *patch.CapacityMode {
+		case CapacityHard, CapacitySoft:
+		default:
+			return BoardState{}, fmt.Errorf("%w: capacity mode must be %q or %q", ErrInvalidRequest, CapacityHard, CapacitySoft)
+		}
+	}
+	if patch.States != nil {
+		if err := validateStateDefs(*patch.States); err != nil {
+			return BoardState{}, err
+		}
+	}
+	if patch.StaleAfterDays != nil && *patch.StaleAfterDays < 0 {
+		return BoardState{}, fmt.Errorf("%w: staleAfterDays must not be negative", ErrInvalidRequest)
+	}
+	if patch.MaxTasks != nil && *patch.MaxTasks < 0 {
+		return BoardState{}, fmt.Errorf("%w: maxTasks must not be negative", ErrInvalidRequest)
+	}
+	return s.withWrite(func(state *BoardState) error {
+		if patch.States != nil {
+			if err := state.setStates(*patch.States); err != nil {
+				return err
+			}
+		}
+		if patch.StaleAfterDays != nil {
+			state.StaleAfterDays = *patch.StaleAfterDays
+		}
+		if patch.MaxTasks != nil {
+			state.MaxTasks = *patch.MaxTasks
+		}
+		if patch.SizeScale != nil || patch.Capacity != nil {
+			if err := state.setSizeScale(patch.SizeScale, patch.Capacity); err != nil {
+				return err
+			}
+		}
+		transitions := state.Transitions
+		if patch.Transitions != nil {
+			transitions = *patch.Transitions
+		}
+		// checked after any state change so transitions cannot name a
+		// state that was just removed
+		if err := state.validateTransitions(transitions); err != nil {
+			return err
+		}
+		if patch.CapacityMode != nil {
+			state.CapacityMode = *patch.CapacityMode
+		}
+		if patch.Transitions != nil {
+			state.Transitions = nil
+			if len(*patch.Transitions) > 0 {
+				state.Transitions = make(map[string][]string, len(*patch.Transitions))
+				for from, to := range *patch.Transitions {
+					state.Transitions[from] = append([]string{}, to...)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// setStates replaces the board's state list, refusing to drop a state that
+// tasks still use.
+func (state *BoardState) setStates(states []StateDef) error {
+	keep := make(map[string]struct{}, len(states))
+	for _, def := range states {
+		keep[def.ID] = struct{}{}
+	}
+	for _, def := range state.StateList() {
+		if _, ok := keep[def.ID]; ok {
+			continue
+		}
+		if n := countTasksInState(state, def.ID); n > 0 {
+			return fmt.Errorf("%w: state %q is used by %d tasks", ErrStateInUse, def.ID, n)
+		}
+	}
+	state.States = append([]StateDef{}, states...)
+	return nil
+}
+
+// setSizeScale replaces the board's size scale and capacity, leaving either
+// unchanged when nil. A scale that would strand an existing task size is
+// rejected with the offending tasks.
+func (state *BoardState) setSizeScale(scale *[]int, capacity *int) error {
+	nextScale := state.Sizes()
+	if scale != nil {
+		nextScale = *scale
+	}
+	nextCapacity := state.ColumnLimit()
+	if capacity != nil {
+		nextCapacity = *capacity
+	}
+	if err := validateSizeScale(nextScale, nextCapacity); err != nil {
+		return err
+	}
+	next := BoardState{SizeScale: nextScale}
+	var offending []string
+	for _, result := range collectSearchResults(state) {
+		if _, err := next.NormalizeSize(result.Task.Size); err != nil {
+			offending = append(offending, fmt.Sprintf("%s (%d)", result.Task.ID, result.Task.Size))
+		}
+	}
+	if len(offending) > 0 {
+		return fmt.Errorf("%w: sizes not on the new scale: %s", ErrSizeInUse, strings.Join(offending, ", "))
+	}
+	if scale != nil {
+		state.SizeScale = append([]int{}, nextScale...)
+	}
+	if capacity != nil {
+		state.Capacity = nextCapacity
+	}
+	return nil
+}
+
+// countTasksInState counts tasks in any pool, including those inside
+// backburnered and archived categories, that are in the given state.
+func countTasksInState(state *BoardState, id string) int {
+	n := 0
+	forEachPoolTask(state, func(task *Task, _ bool) {
+		if task.State == id {
+			n++
+		}
+	})
+	return n
+}
+
+// SwapCategories exchanges the positions of two active categories.
+func (s *Store) SwapCategories(aID, bID string) (BoardState, error) {
+	if aID == bID {
+		return BoardState{}, fmt.Errorf("%w: cannot swap a category with itself", ErrInvalidRequest)
+	}
+	return s.withWrite(func(state *BoardState) error {
+		a := findCategoryIndex(state.Categories, aID)
+		b := findCategoryIndex(state.Categories, bID)
+		if a == -1 || b == -1 {
+			return ErrCategoryNotFound
+		}
+		state.Categories[a], state.Categories[b] = state.Categories[b], state.Categories[a]
+		return nil
+	})
+}
+
 func (s *Store) ReorderCategoryTasks(id string, order []string) (Category, BoardState, error) {
 	var cat Category
 	updatedState, err := s.withWrite(func(state *BoardState) error {
@@ -348,34 +1847,237 @@ func (s *Store) ReorderCategoryTasks(id string, order []string) (Category, Board
 	return cat, updatedState, nil
 }
 
-func (s *Store) SetFocused(taskID string) (Task, BoardState, error) {
+// SetFocused focuses taskID, or clears focus when it is empty. changed
+// reports whether the focused task differs from before, so clearing twice
+// reports no change the second time.
+func (s *Store) SetFocused(taskID string) (Task, bool, BoardState, error) {
 	var focused Task
+	changed := false
 	updatedState, err := s.withWrite(func(state *BoardState) error {
+		prior := focusedTaskID(state)
 		if taskID == "" {
 			clearFocus(state)
+			changed = prior != ""
 			return nil
 		}
-		taskPtr, _, err := findTask(state, taskID)
+		notFocusable := fmt.Errorf("%w: task %s is not in an active category", ErrNotFocusable, taskID)
+		taskPtr, loc, err := findTask(state, taskID, s.taskIndex)
 		if err != nil {
+			if errors.Is(err, ErrTaskNotFound) && inShelvedCategory(state, taskID) {
+				return notFocusable
+			}
 			return err
 		}
+		if loc.Kind != LocationCategory {
+			return notFocusable
+		}
 		clearFocus(state)
 		taskPtr.Focused = true
 		focused = taskPtr.Clone()
+		changed = prior != taskID
 		return nil
 	})
 	if err != nil {
-		return Task{}, BoardState{}, err
+		return Task{}, false, BoardState{}, err
+	}
+	return focused, changed, updatedState, nil
+}
+
+// FocusContext is the focused task with its neighbours in its category.
+// CategoryPosition is the task's zero-based index there and CategorySize the
+// number of tasks the category holds. Previous and Next are nil at either
+// end of the category.
+type FocusContext struct {
+	Focused          Task   `json:"focused"`
+	CategoryID       string `json:"categoryId"`
+	CategoryName     string `json:"categoryName"`
+	Previous         *Task  `json:"previous"`
+	Next             *Task  `json:"next"`
+	CategoryPosition int    `json:"categoryPosition"`
+	CategorySize     int    `json:"categorySize"`
+}
+
+// GetFocusedTaskContext returns the focused task's FocusContext, or the zero
+// FocusContext when nothing is focused.
+func (s *Store) GetFocusedTaskContext() (FocusContext, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	// only tasks in active categories can hold focus
+	for _, cat := range s.state.Categories {
+		for i, task := range cat.Tasks {
+			if !task.Focused {
+				continue
+			}
+			ctx := FocusContext{
+				Focused:          task.Clone(),
+				CategoryID:       cat.ID,
+				CategoryName:     cat.Name,
+				CategoryPosition: i,
+				CategorySize:     len(cat.Tasks),
+			}
+			if i > 0 {
+				previous := cat.Tasks[i-1].Clone()
+				ctx.Previous = &previous
+			}
+			if i < len(cat.Tasks)-1 {
+				next := cat.Tasks[i+1].Clone()
+				ctx.Next = &next
+			}
+			return ctx, nil
+		}
+	}
+	return FocusContext{}, nil
+}
+
+// MoveFocusedTask moves whichever task holds focus, as MoveTask would, for
+// clients that act on the focused task without knowing its ID.
+func (s *Store) MoveFocusedTask(dest MoveTaskRequest) (Task, BoardState, error) {
+	s.mu.RLock()
+	id := focusedTaskID(&s.state)
+	s.mu.RUnlock()
+	if id == "" {
+		return Task{}, BoardState{}, fmt.Errorf("%w: no task is focused", ErrTaskNotFound)
+	}
+	return s.MoveTask(id, dest)
+}
+
+// focusedTaskID returns the ID of the focused task, or "" when none is.
+func focusedTaskID(state *BoardState) string {
+	id := ""
+	forEachPoolTask(state, func(task *Task, _ bool) {
+		if task.Focused && id == "" {
+			id = task.ID
+		}
+	})
+	return id
+}
+
+// inShelvedCategory reports whether a task sits inside a backburnered or
+// archived category.
+func inShelvedCategory(state *BoardState, taskID string) bool {
+	for _, pool := range [][]Category{state.CategoryBackburner, state.CategoryArchives} {
+		for _, cat := range pool {
+			for _, task := range cat.Tasks {
+				if task.ID == taskID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// notesLimit caps task notes at max runes, rejecting longer notes or
+// truncating them with an ellipsis. A zero max disables it.
+type notesLimit struct {
+	max      int
+	truncate bool
+}
+
+// apply enforces the limit on notes, counted in runes.
+func (l notesLimit) apply(notes string) (string, error) {
+	if l.max <= 0 || utf8.RuneCountInString(notes) <= l.max {
+		return notes, nil
+	}
+	if !l.truncate {
+		return "", fmt.Errorf("%w: notes exceed %d characters", ErrInvalidRequest, l.max)
+	}
+	runes := []rune(notes)
+	return string(runes[:l.max-1]) + "…", nil
+}
+
+// applyBoard enforces the limit on every task of a board about to be merged
+// in, stopping at the first task it rejects.
+func (l notesLimit) applyBoard(board *BoardState) error {
+	var err error
+	forEachPoolTask(board, func(task *Task, _ bool) {
+		if err != nil {
+			return
+		}
+		notes, limitErr := l.apply(task.Notes)
+		if limitErr != nil {
+			err = fmt.Errorf("task %s: %w", task.ID, limitErr)
+			return
+		}
+		task.Notes = notes
+	})
+	return err
+}
+
+// checkLinks rejects links whose URL scheme is not on the allow list.
+func (s *Store) checkLinks(links []TaskLink) error {
+	for _, link := range links {
+		u, err := url.Parse(strings.TrimSpace(link.URL))
+		if err != nil {
+			return fmt.Errorf("%w: invalid link url %q", ErrInvalidRequest, link.URL)
+		}
+		scheme := strings.ToLower(u.Scheme)
+		if _, ok := s.linkSchemes[scheme]; !ok {
+			if scheme == "" {
+				return fmt.Errorf("%w: link url %q has no scheme", ErrInvalidRequest, link.URL)
+			}
+			return fmt.Errorf("%w: link scheme %q not allowed", ErrInvalidRequest, scheme)
+		}
+	}
+	return nil
+}
+
+// checkBoardLinks runs checkLinks over every task of a board being brought
+// in whole.
+func (s *Store) checkBoardLinks(board *BoardState) error {
+	var err error
+	forEachPoolTask(board, func(task *Task, _ bool) {
+		if err != nil {
+			return
+		}
+		if linkErr := s.checkLinks(task.Links); linkErr != nil {
+			err = fmt.Errorf("task %s: %w", task.ID, linkErr)
+		}
+	})
+	return err
+}
+
+// checkPatch applies the store's input policies to a patch before it is
+// applied to any task.
+func (s *Store) checkPatch(patch *TaskPatch) error {
+	if patch.Links != nil {
+		if err := s.checkLinks(*patch.Links); err != nil {
+			return err
+		}
+	}
+	if patch.DueDateText != nil {
+		if patch.DueDate != nil {
+			return fmt.Errorf("%w: send either dueDate or dueDateText, not both", ErrInvalidRequest)
+		}
+		if strings.TrimSpace(*patch.DueDateText) == "" {
+			patch.ClearFields = append(patch.ClearFields, "dueDate")
+		} else {
+			due, err := parseDueDate(*patch.DueDateText, s.now(), s.location)
+			if err != nil {
+				return err
+			}
+			patch.DueDate = &due
+		}
 	}
-	return focused, updatedState, nil
+	return nil
 }
 
 func reorderTasks(cat *Category, order []string) error {
 	if len(order) != len(cat.Tasks) {
 		return fmt.Errorf("%w: task order length mismatch", ErrInvalidRequest)
 	}
+	present := make(map[string]struct{}, len(cat.Tasks))
+	for _, task := range cat.Tasks {
+		present[task.ID] = struct{}{}
+	}
 	index := map[string]int{}
 	for i, id := range order {
+		if _, ok := present[id]; !ok {
+			return fmt.Errorf("%w: task %s is not in category %s", ErrInvalidRequest, id, cat.Name)
+		}
+		if _, dup := index[id]; dup {
+			return fmt.Errorf("%w: task id %s appears more than once", ErrInvalidRequest, id)
+		}
 		index[id] = i
 	}
 	reordered := make([]Task, len(cat.Tasks))
@@ -390,11 +2092,91 @@ func reorderTasks(cat *Category, order []string) error {
 	return nil
 }
 
-func ensureCapacity(cat Category) error {
+// checkCapacity applies the board's capacity mode to cat, whose size was
+// sizeBefore prior to the change being checked. Soft boards never reject;
+// hard boards reject growth past the board's capacity but tolerate a category
+// that is already over the limit from an earlier soft period as long as it
+// does not grow. A forced change may grow a category up to twice the limit.
+func (state *BoardState) checkCapacity(cat Category, sizeBefore int, force bool) error {
+	if state.CapacityMode == CapacitySoft {
+		return nil
+	}
+	limit := state.ColumnLimit()
+	if force {
+		limit = state.forcedLimit()
+	}
+	size := categorySize(cat)
+	if size > limit && size > sizeBefore {
+		return &CapacityError{Suggestions: state.roomFor(size-sizeBefore, cat.ID)}
+	}
+	return nil
+}
+
+// CapacityError is ErrCapacityExceeded with the active categories that could
+// take the rejected growth instead, for clients to offer as alternatives.
+type CapacityError struct {
+	Suggestions []CapacitySuggestion
+}
+
+// CapacitySuggestion is a category with Remaining points of room.
+type CapacitySuggestion struct {
+	CategoryID string `json:"categoryId"`
+	Name       string `json:"name"`
+	Remaining  int    `json:"remaining"`
+}
+
+func (e *CapacityError) Error() string {
+	return ErrCapacityExceeded.Error()
+}
+
+func (e *CapacityError) Unwrap() error {
+	return ErrCapacityExceeded
+}
+
+// roomFor lists active categories other than exclude with at least size
+// points of room and space under their task limit, in board order.
+func (state *BoardState) roomFor(size int, exclude string) []CapacitySuggestion {
+	out := []CapacitySuggestion{}
+	for _, cat := range state.Categories {
+		if cat.ID == exclude || cat.Locked {
+			continue
+		}
+		if limit := state.TaskLimit(cat); limit > 0 && len(cat.Tasks) >= limit {
+			continue
+		}
+		remaining := state.ColumnLimit() - categorySize(cat)
+		if remaining >= size {
+			out = append(out, CapacitySuggestion{CategoryID: cat.ID, Name: cat.Name, Remaining: remaining})
+		}
+	}
+	return out
+}
+
+// checkPlacement checks a category that has just gained one task against
+// both its size capacity and its task limit.
+func (state *BoardState) checkPlacement(cat Category, sizeBefore int, force bool) error {
+	if err := state.checkCapacity(cat, sizeBefore, force); err != nil {
+		return err
+	}
+	return state.checkTaskLimit(cat, len(cat.Tasks)-1)
+}
+
+// checkTaskLimit rejects growing a category past its task limit. Like the
+// size check, a category already over the limit may keep what it has.
+func (state *BoardState) checkTaskLimit(cat Category, countBefore int) error {
+	limit := state.TaskLimit(cat)
+	count := len(cat.Tasks)
+	if limit > 0 && count > limit && count > countBefore {
+		return fmt.Errorf("%w: %s would hold %d tasks, limit is %d", ErrTaskLimit, cat.Name, count, limit)
+	}
+	return nil
+}
+
+func ensureCapacity(cat Category, limit int) error {
 	total := 0
 	for _, t := range cat.Tasks {
 		total += t.Size
-		if total > ColumnCapacity {
+		if total > limit {
 			return ErrCapacityExceeded
 		}
 	}
@@ -455,6 +2237,14 @@ func clearFocus(state *BoardState) {
 	}
 }
 
+// releaseFocusIfDone drops focus from a task that has been finished, so the
+// board is left with nothing focused.
+func releaseFocusIfDone(task *Task) {
+	if task.State == "done" {
+		task.Focused = false
+	}
+}
+
 func clearCategoryFocus(cat *Category) {
 	for i := range cat.Tasks {
 		cat.Tasks[i].Focused = false
@@ -465,23 +2255,32 @@ func restoreTask(state *BoardState, task Task, loc taskLocation) {
 	switch loc.Kind {
 	case LocationCategory:
 		cat := &state.Categories[loc.CategoryIndex]
-		if loc.TaskIndex >= len(cat.Tasks) {
-			cat.Tasks = append(cat.Tasks, task)
-		} else {
-			cat.Tasks = append(cat.Tasks[:loc.TaskIndex+1], cat.Tasks[loc.TaskIndex:]...)
-			cat.Tasks[loc.TaskIndex] = task
-		}
+		cat.Tasks = insertAt(cat.Tasks, loc.TaskIndex, task)
 	case LocationBackburner:
-		state.Backburner = append(state.Backburner, Task{})
-		copy(state.Backburner[loc.TaskIndex+1:], state.Backburner[loc.TaskIndex:])
-		state.Backburner[loc.TaskIndex] = task
+		state.Backburner = insertAt(state.Backburner, loc.TaskIndex, task)
 	case LocationArchive:
-		state.Archives = append(state.Archives, Task{})
-		copy(state.Archives[loc.TaskIndex+1:], state.Archives[loc.TaskIndex:])
-		state.Archives[loc.TaskIndex] = task
+		state.Archives = insertAt(state.Archives, loc.TaskIndex, task)
+	case LocationInbox:
+		state.Inbox = insertAt(state.Inbox, loc.TaskIndex, task)
 	}
 }
 
+// insertAt returns list with v inserted at index i, appending when i is past
+// the end. It never writes through list's spare capacity, so slices sharing
+// the backing array are left intact.
+func insertAt[T any](list []T, i int, v T) []T {
+	if i < 0 {
+		i = 0
+	}
+	if i > len(list) {
+		i = len(list)
+	}
+	out := make([]T, 0, len(list)+1)
+	out = append(out, list[:i]...)
+	out = append(out, v)
+	return append(out, list[i:]...)
+}
+
 type categoryLocation struct {
 	Kind  string
 	Index int
@@ -515,21 +2314,11 @@ func removeCategory(state *BoardState, id string) (Category, categoryLocation, e
 func restoreCategory(state *BoardState, cat Category, loc categoryLocation) {
 	switch loc.Kind {
 	case LocationCategoryBoard:
-		if loc.Index >= len(state.Categories) {
-			state.Categories = append(state.Categories, cat)
-			return
-		}
-		state.Categories = append(state.Categories, Category{})
-		copy(state.Categories[loc.Index+1:], state.Categories[loc.Index:])
-		state.Categories[loc.Index] = cat
+		state.Categories = insertAt(state.Categories, loc.Index, cat)
 	case LocationBackburner:
-		state.CategoryBackburner = append(state.CategoryBackburner, Category{})
-		copy(state.CategoryBackburner[loc.Index+1:], state.CategoryBackburner[loc.Index:])
-		state.CategoryBackburner[loc.Index] = cat
+		state.CategoryBackburner = insertAt(state.CategoryBackburner, loc.Index, cat)
 	case LocationArchive:
-		state.CategoryArchives = append(state.CategoryArchives, Category{})
-		copy(state.CategoryArchives[loc.Index+1:], state.CategoryArchives[loc.Index:])
-		state.CategoryArchives[loc.Index] = cat
+		state.CategoryArchives = insertAt(state.CategoryArchives, loc.Index, cat)
 	}
 }
 
@@ -539,7 +2328,99 @@ type taskLocation struct {
 	TaskIndex     int
 }
 
-func findTask(state *BoardState, id string) (*Task, taskLocation, error) {
+// RebuildIndex recomputes the task ID lookup index from the current board.
+// It reports an error if the board holds duplicate task IDs.
+func (s *Store) RebuildIndex() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rebuildIndexLocked()
+}
+
+func (s *Store) rebuildIndexLocked() error {
+	index, err := buildTaskIndex(&s.state)
+	s.taskIndex = index
+	return err
+}
+
+// buildTaskIndex maps every task findTask can locate to its position. On a
+// duplicate ID the first occurrence wins, matching a linear scan.
+func buildTaskIndex(state *BoardState) (map[string]taskLocation, error) {
+	index := make(map[string]taskLocation)
+	var dup error
+	add := func(id string, loc taskLocation) {
+		if _, ok := index[id]; ok {
+			if dup == nil {
+				dup = fmt.Errorf("%w: duplicate task id %s", ErrInvalidRequest, id)
+			}
+			return
+		}
+		index[id] = loc
+	}
+	for ci := range state.Categories {
+		for ti, task := range state.Categories[ci].Tasks {
+			add(task.ID, taskLocation{Kind: LocationCategory, CategoryIndex: ci, TaskIndex: ti})
+		}
+	}
+	for i, task := range state.Backburner {
+		add(task.ID, taskLocation{Kind: LocationBackburner, TaskIndex: i})
+	}
+	for i, task := range state.Archives {
+		add(task.ID, taskLocation{Kind: LocationArchive, TaskIndex: i})
+	}
+	for i, task := range state.Inbox {
+		add(task.ID, taskLocation{Kind: LocationInbox, TaskIndex: i})
+	}
+	return index, dup
+}
+
+// checkTaskIndex reports whether index agrees exactly with state.
+func checkTaskIndex(state *BoardState, index map[string]taskLocation) error {
+	want, _ := buildTaskIndex(state)
+	if len(want) != len(index) {
+		return fmt.Errorf("task index has %d entries, board has %d tasks", len(index), len(want))
+	}
+	for id, loc := range want {
+		if got, ok := index[id]; !ok || got != loc {
+			return fmt.Errorf("task index entry for %s is %+v, want %+v", id, got, loc)
+		}
+	}
+	return nil
+}
+
+// taskAt returns the task at loc, or nil if loc is out of range.
+func taskAt(state *BoardState, loc taskLocation) *Task {
+	var tasks []Task
+	switch loc.Kind {
+	case LocationCategory:
+		if loc.CategoryIndex < 0 || loc.CategoryIndex >= len(state.Categories) {
+			return nil
+		}
+		tasks = state.Categories[loc.CategoryIndex].Tasks
+	case LocationBackburner:
+		tasks = state.Backburner
+	case LocationArchive:
+		tasks = state.Archives
+	case LocationInbox:
+		tasks = state.Inbox
+	}
+	if loc.TaskIndex < 0 || loc.TaskIndex >= len(tasks) {
+		return nil
+	}
+	return &tasks[loc.TaskIndex]
+}
+
+// findTask consults index first, trusting a hit only when the task at that
+// location still carries id, and falls back to a linear scan.
+func findTask(state *BoardState, id string, index map[string]taskLocation) (*Task, taskLocation, error) {
+	if loc, ok := index[id]; ok {
+		if task := taskAt(state, loc); task != nil && task.ID == id {
+			return task, loc, nil
+		}
+	}
+	return scanTask(state, id)
+}
+
+func scanTask(state *BoardState, id string) (*Task, taskLocation, error) {
 	for ci := range state.Categories {
 		for ti := range state.Categories[ci].Tasks {
 			if state.Categories[ci].Tasks[ti].ID == id {
@@ -557,20 +2438,92 @@ func findTask(state *BoardState, id string) (*Task, taskLocation, error) {
 			return &state.Archives[i], taskLocation{Kind: LocationArchive, TaskIndex: i}, nil
 		}
 	}
+	for i := range state.Inbox {
+		if state.Inbox[i].ID == id {
+			return &state.Inbox[i], taskLocation{Kind: LocationInbox, TaskIndex: i}, nil
+		}
+	}
 	return nil, taskLocation{}, ErrTaskNotFound
 }
 
-func removeTask(state *BoardState, id string) (Task, taskLocation, error) {
-	if taskPtr, loc, err := findTask(state, id); err == nil {
+// hasTaskID reports whether any task on the board, including those inside
+// backburnered and archived categories, has id.
+func hasTaskID(state *BoardState, id string) bool {
+	found := false
+	forEachPoolTask(state, func(task *Task, _ bool) {
+		found = found || task.ID == id
+	})
+	return found
+}
+
+// collectSearchResults copies every task on the board, including those inside
+// backburnered and archived categories, along with where each one lives.
+func collectSearchResults(state *BoardState) []SearchResult {
+	results := []SearchResult{}
+	addCategories := func(pool string, categories []Category) {
+		for _, cat := range categories {
+			for _, task := range cat.Tasks {
+				results = append(results, SearchResult{Task: task.Clone(), Pool: pool, CategoryID: cat.ID, CategoryName: cat.Name})
+			}
+		}
+	}
+	addTasks := func(pool string, tasks []Task) {
+		for _, task := range tasks {
+			results = append(results, SearchResult{Task: task.Clone(), Pool: pool, CategoryID: task.SourceID, CategoryName: task.Source})
+		}
+	}
+	addCategories(PoolActive, state.Categories)
+	addTasks(PoolBackburner, state.Backburner)
+	addTasks(PoolArchive, state.Archives)
+	addTasks(PoolInbox, state.Inbox)
+	addCategories(PoolCategoryBackburner, state.CategoryBackburner)
+	addCategories(PoolCategoryArchive, state.CategoryArchives)
+	return results
+}
+
+// forEachTask visits every task in active categories, the backburner and the
+// archive, stopping early when fn returns false.
+func forEachTask(state *BoardState, fn func(task *Task, loc taskLocation) bool) {
+	for ci := range state.Categories {
+		for ti := range state.Categories[ci].Tasks {
+			if !fn(&state.Categories[ci].Tasks[ti], taskLocation{Kind: LocationCategory, CategoryIndex: ci, TaskIndex: ti}) {
+				return
+			}
+		}
+	}
+	for i := range state.Backburner {
+		if !fn(&state.Backburner[i], taskLocation{Kind: LocationBackburner, TaskIndex: i}) {
+			return
+		}
+	}
+	for i := range state.Archives {
+		if !fn(&state.Archives[i], taskLocation{Kind: LocationArchive, TaskIndex: i}) {
+			return
+		}
+	}
+	for i := range state.Inbox {
+		if !fn(&state.Inbox[i], taskLocation{Kind: LocationInbox, TaskIndex: i}) {
+			return
+		}
+	}
+}
+
+func removeTask(state *BoardState, id string, index map[string]taskLocation) (Task, taskLocation, error) {
+	if taskPtr, loc, err := findTask(state, id, index); err == nil {
 		task := taskPtr.Clone()
 		switch loc.Kind {
 		case LocationCategory:
 			cat := &state.Categories[loc.CategoryIndex]
+			if cat.Locked {
+				return Task{}, taskLocation{}, errCategoryLocked
+			}
 			cat.Tasks = append(cat.Tasks[:loc.TaskIndex], cat.Tasks[loc.TaskIndex+1:]...)
 		case LocationBackburner:
 			state.Backburner = append(state.Backburner[:loc.TaskIndex], state.Backburner[loc.TaskIndex+1:]...)
 		case LocationArchive:
 			state.Archives = append(state.Archives[:loc.TaskIndex], state.Archives[loc.TaskIndex+1:]...)
+		case LocationInbox:
+			state.Inbox = append(state.Inbox[:loc.TaskIndex], state.Inbox[loc.TaskIndex+1:]...)
 		}
 		return task, loc, nil
 	}
@@ -581,16 +2534,23 @@ func (state *BoardState) insertTask(req CreateTaskRequest) (Task, error) {
 	task := req.Task
 	if task.ID == "" {
 		task.ID = NewID()
+	} else if hasTaskID(state, task.ID) {
+		return Task{}, fmt.Errorf("%w: task id %s already exists", ErrInvalidRequest, task.ID)
 	}
+	// the number is only taken once the task is placed
+	task.Ref = formatTaskRef(state.LastTaskSeq + 1)
 	if task.Size == 0 {
-		task.Size = 1
+		task.Size = state.Sizes()[0]
 	}
 	var err error
-	task.Size, err = NormalizeSize(task.Size)
+	if task.Notes, err = state.notes.apply(task.Notes); err != nil {
+		return Task{}, err
+	}
+	task.Size, err = state.NormalizeSize(task.Size)
 	if err != nil {
 		return Task{}, err
 	}
-	if err := ValidateTaskState(task.State); err != nil {
+	if err := state.ValidateTaskState(task.State); err != nil {
 		return Task{}, err
 	}
 
@@ -600,15 +2560,19 @@ func (state *BoardState) insertTask(req CreateTaskRequest) (Task, error) {
 		if idx == -1 {
 			return Task{}, ErrCategoryNotFound
 		}
+		if state.Categories[idx].Locked {
+			return Task{}, errCategoryLocked
+		}
 		insertIndex := len(state.Categories[idx].Tasks)
 		if req.Position != nil && *req.Position >= 0 && *req.Position <= len(state.Categories[idx].Tasks) {
 			insertIndex = *req.Position
 		}
 		cat := &state.Categories[idx]
+		sizeBefore := categorySize(*cat)
 		cat.Tasks = append(cat.Tasks, Task{})
 		copy(cat.Tasks[insertIndex+1:], cat.Tasks[insertIndex:])
 		cat.Tasks[insertIndex] = task
-		if err := ensureCapacity(*cat); err != nil {
+		if err := state.checkPlacement(*cat, sizeBefore, req.Force); err != nil {
 			cat.Tasks = append(cat.Tasks[:insertIndex], cat.Tasks[insertIndex+1:]...)
 			return Task{}, err
 		}
@@ -626,9 +2590,16 @@ func (state *BoardState) insertTask(req CreateTaskRequest) (Task, error) {
 		task.Urgent = false
 		task.Focused = false
 		state.Archives = append(state.Archives, task)
+	case LocationInbox:
+		task.Urgent = false
+		task.Focused = false
+		task.SourceID = ""
+		task.Source = ""
+		state.Inbox = append(state.Inbox, task)
 	default:
 		return Task{}, ErrInvalidLocation
 	}
+	state.LastTaskSeq++
 	return task.Clone(), nil
 }
 
@@ -638,8 +2609,8 @@ func (state *BoardState) placeTask(task Task, dest MoveTaskRequest) error {
 		return err
 	}
 
-	task.Focused = false
-
+	// focus survives moves between active categories and is dropped below
+	// when the task leaves them
 	switch dest.Location {
 	case LocationCategory:
 		idx := findCategoryIndex(state.Categories, dest.CategoryID)
@@ -647,24 +2618,28 @@ func (state *BoardState) placeTask(task Task, dest MoveTaskRequest) error {
 			return ErrCategoryNotFound
 		}
 		cat := &state.Categories[idx]
+		if cat.Locked {
+			return errCategoryLocked
+		}
 		insertIndex := len(cat.Tasks)
 		if dest.Position != nil && *dest.Position >= 0 && *dest.Position <= len(cat.Tasks) {
 			insertIndex = *dest.Position
 		}
 		task.SourceID = ""
 		task.Source = ""
+		sizeBefore := categorySize(*cat)
+		before := cat.Tasks
+		cat.Tasks = insertAt(cat.Tasks, insertIndex, task)
+		if err := state.checkPlacement(*cat, sizeBefore, dest.Force); err != nil {
+			cat.Tasks = before
+			return err
+		}
+		// only touch the destination's urgent flags once the move is certain
 		if task.Urgent {
 			normalizeUrgent(state, idx, task.ID)
 		} else {
 			normalizeUrgent(state, idx, "")
 		}
-		cat.Tasks = append(cat.Tasks, Task{})
-		copy(cat.Tasks[insertIndex+1:], cat.Tasks[insertIndex:])
-		cat.Tasks[insertIndex] = task
-		if err := ensureCapacity(*cat); err != nil {
-			cat.Tasks = append(cat.Tasks[:insertIndex], cat.Tasks[insertIndex+1:]...)
-			return err
-		}
 	case LocationBackburner:
 		task.Urgent = false
 		task.Focused = false
@@ -677,25 +2652,174 @@ func (state *BoardState) placeTask(task Task, dest MoveTaskRequest) error {
 		task.SourceID = dest.SourceID
 		task.Source = dest.Source
 		state.Archives = append(state.Archives, task)
+	case LocationInbox:
+		task.Urgent = false
+		task.Focused = false
+		task.SourceID = ""
+		task.Source = ""
+		state.Inbox = append(state.Inbox, task)
 	default:
 		return ErrInvalidLocation
 	}
 	return nil
 }
 
+var taskPools = []string{
+	PoolActive,
+	PoolBackburner,
+	PoolArchive,
+	PoolCategoryBackburner,
+	PoolCategoryArchive,
+	PoolInbox,
+}
+
+func validPool(pool string) bool {
+	for _, p := range taskPools {
+		if p == pool {
+			return true
+		}
+	}
+	return false
+}
+
+// locateTaskInPool returns the slice holding the task, its index and, for
+// category-backed pools, the owning category.
+func locateTaskInPool(state *BoardState, pool, id string) (*[]Task, int, *Category) {
+	var categories []Category
+	switch pool {
+	case PoolActive:
+		categories = state.Categories
+	case PoolCategoryBackburner:
+		categories = state.CategoryBackburner
+	case PoolCategoryArchive:
+		categories = state.CategoryArchives
+	case PoolBackburner:
+		for i := range state.Backburner {
+			if state.Backburner[i].ID == id {
+				return &state.Backburner, i, nil
+			}
+		}
+		return nil, -1, nil
+	case PoolArchive:
+		for i := range state.Archives {
+			if state.Archives[i].ID == id {
+				return &state.Archives, i, nil
+			}
+		}
+		return nil, -1, nil
+	case PoolInbox:
+		for i := range state.Inbox {
+			if state.Inbox[i].ID == id {
+				return &state.Inbox, i, nil
+			}
+		}
+		return nil, -1, nil
+	}
+	for ci := range categories {
+		for ti := range categories[ci].Tasks {
+			if categories[ci].Tasks[ti].ID == id {
+				return &categories[ci].Tasks, ti, &categories[ci]
+			}
+		}
+	}
+	return nil, -1, nil
+}
+
+func (state *BoardState) placeTaskInPool(task Task, pool, sourceID, source string) (Task, error) {
+	task.Focused = false
+
+	switch pool {
+	case PoolActive:
+		idx := findCategoryIndex(state.Categories, sourceID)
+		if idx == -1 {
+			return Task{}, ErrCategoryNotFound
+		}
+		cat := &state.Categories[idx]
+		if cat.Locked {
+			return Task{}, errCategoryLocked
+		}
+		task.SourceID = ""
+		task.Source = ""
+		sizeBefore := categorySize(*cat)
+		cat.Tasks = append(cat.Tasks, task)
+		if err := state.checkPlacement(*cat, sizeBefore, false); err != nil {
+			cat.Tasks = cat.Tasks[:len(cat.Tasks)-1]
+			return Task{}, err
+		}
+		if task.Urgent {
+			normalizeUrgent(state, idx, task.ID)
+		}
+	case PoolCategoryBackburner, PoolCategoryArchive:
+		categories := state.CategoryBackburner
+		if pool == PoolCategoryArchive {
+			categories = state.CategoryArchives
+		}
+		idx := findCategoryIndex(categories, sourceID)
+		if idx == -1 {
+			return Task{}, ErrCategoryNotFound
+		}
+		if categories[idx].Locked {
+			return Task{}, errCategoryLocked
+		}
+		task.Urgent = false
+		task.SourceID = ""
+		task.Source = ""
+		categories[idx].Tasks = append(categories[idx].Tasks, task)
+	case PoolBackburner:
+		task.Urgent = false
+		task.SourceID = sourceID
+		task.Source = source
+		state.Backburner = append(state.Backburner, task)
+	case PoolArchive:
+		task.Urgent = false
+		task.SourceID = sourceID
+		task.Source = source
+		state.Archives = append(state.Archives, task)
+	case PoolInbox:
+		task.Urgent = false
+		task.SourceID = ""
+		task.Source = ""
+		state.Inbox = append(state.Inbox, task)
+	default:
+		return Task{}, ErrInvalidLocation
+	}
+	return task, nil
+}
+
+// rememberBoardIndex records where a category sat when it leaves the board,
+// and aims an unpositioned return to the board at that slot. A slot past the
+// end of a board that has since shrunk falls back to appending.
+func rememberBoardIndex(cat *Category, from categoryLocation, dest *MoveCategoryRequest) {
+	if dest.Location != LocationCategoryBoard {
+		if from.Kind == LocationCategoryBoard {
+			index := from.Index
+			cat.LastBoardIndex = &index
+		}
+		return
+	}
+	if dest.Position == nil && cat.LastBoardIndex != nil {
+		position := *cat.LastBoardIndex
+		dest.Position = &position
+	}
+}
+
 func (state *BoardState) placeCategory(cat Category, dest MoveCategoryRequest) error {
 	switch dest.Location {
 	case LocationCategoryBoard:
 		if len(state.Categories) >= CategoryLimit {
 			return ErrCategoryLimit
 		}
-		if err := ensureCapacity(cat); err != nil {
+		if err := state.checkCapacity(cat, 0, false); err != nil {
+			return err
+		}
+		if err := state.checkTaskLimit(cat, 0); err != nil {
 			return err
 		}
 		insertIndex := len(state.Categories)
 		if dest.Position != nil && *dest.Position >= 0 && *dest.Position <= len(state.Categories) {
 			insertIndex = *dest.Position
 		}
+		cat.LastBoardIndex = nil
 		state.Categories = append(state.Categories, Category{})
 		copy(state.Categories[insertIndex+1:], state.Categories[insertIndex:])
 		state.Categories[insertIndex] = cat
@@ -711,6 +2835,131 @@ func (state *BoardState) placeCategory(cat Category, dest MoveCategoryRequest) e
 	return nil
 }
 
+// sortCategories orders the active categories of a board copy by name or by
+// total task size without touching the stored order.
+func sortCategories(board *BoardState, key string) error {
+	var less func(a, b Category) bool
+	switch key {
+	case "name":
+		less = func(a, b Category) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+	case "size":
+		less = func(a, b Category) bool {
+			return categorySize(a) < categorySize(b)
+		}
+	default:
+		return fmt.Errorf("%w: unknown category sort key %q", ErrInvalidRequest, key)
+	}
+	sort.SliceStable(board.Categories, func(i, j int) bool {
+		return less(board.Categories[i], board.Categories[j])
+	})
+	return nil
+}
+
+// filterBoardStates keeps only the tasks of a board copy whose state is in
+// states, across categories and task pools. Categories stay even when they
+// end up empty, and their projected counts still describe the whole
+// category.
+func filterBoardStates(board *BoardState, states []string) error {
+	keep := map[string]bool{}
+	for _, state := range states {
+		if err := board.ValidateTaskState(state); err != nil {
+			return err
+		}
+		keep[state] = true
+	}
+	filter := func(tasks []Task) []Task {
+		out := []Task{}
+		for _, task := range tasks {
+			if keep[task.State] {
+				out = append(out, task)
+			}
+		}
+		return out
+	}
+	for _, pool := range [][]Category{board.Categories, board.CategoryBackburner, board.CategoryArchives} {
+		for i := range pool {
+			pool[i].Tasks = filter(pool[i].Tasks)
+		}
+	}
+	board.Backburner = filter(board.Backburner)
+	board.Archives = filter(board.Archives)
+	board.Inbox = filter(board.Inbox)
+	return nil
+}
+
+func categorySize(cat Category) int {
+	total := 0
+	for _, t := range cat.Tasks {
+		total += t.Size
+	}
+	return total
+}
+
+// freshCategoryCopy copies src under a new name with new category and task
+// IDs. Focus is always cleared; urgency survives only when keepUrgent is set,
+// and then only on the first urgent task.
+func freshCategoryCopy(src Category, name string, now time.Time, keepUrgent bool) Category {
+	out := Category{ID: NewID(), Name: name, Tasks: make([]Task, 0, len(src.Tasks))}
+	urgentSeen := false
+	for _, task := range src.Tasks {
+		task = task.Clone()
+		task.ID = NewID()
+		task.Ref = ""
+		task.Focused = false
+		task.SourceID = ""
+		task.Source = ""
+		task.History = nil
+		task.CreatedAt = now
+		task.UpdatedAt = now
+		if task.Urgent {
+			task.Urgent = keepUrgent && !urgentSeen
+			urgentSeen = true
+		}
+		out.Tasks = append(out.Tasks, task)
+	}
+	return out
+}
+
+// appendNote adds note to notes as its own paragraph.
+func appendNote(notes, note string) string {
+	if strings.TrimSpace(notes) == "" {
+		return note
+	}
+	return strings.TrimRight(notes, "\n") + "\n\n" + note
+}
+
+// findCategory returns a copy of the category with id from any pool.
+func findCategory(state *BoardState, id string) (Category, bool) {
+	for _, pool := range [][]Category{state.Categories, state.CategoryBackburner, state.CategoryArchives} {
+		if idx := findCategoryIndex(pool, id); idx != -1 {
+			return pool[idx].Clone(), true
+		}
+	}
+	return Category{}, false
+}
+
+// resolveCategoryName maps a case-insensitive category name to the ID of the
+// single active category carrying it.
+func resolveCategoryName(categories []Category, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	var matches []string
+	for _, cat := range categories {
+		if strings.EqualFold(cat.Name, name) {
+			matches = append(matches, cat.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: no active category named %q", ErrCategoryNotFound, name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%w: category name %q is ambiguous", ErrInvalidRequest, name)
+	}
+}
+
 func findCategoryIndex(categories []Category, id string) int {
 	for i := range categories {
 		if categories[i].ID == id {
@@ -720,7 +2969,18 @@ func findCategoryIndex(categories []Category, id string) int {
 	return -1
 }
 
-func seedBoard() BoardState {
+func emptyBoard() BoardState {
+	return BoardState{
+		Categories:         []Category{},
+		Backburner:         []Task{},
+		Archives:           []Task{},
+		Inbox:              []Task{},
+		CategoryBackburner: []Category{},
+		CategoryArchives:   []Category{},
+	}
+}
+
+func seedBoard(stamp time.Time) BoardState {
 	newTask := func(name, desc, state string, size int) Task {
 		return Task{
 			ID:          NewID(),
@@ -728,6 +2988,8 @@ func seedBoard() BoardState {
 			Description: desc,
 			State:       state,
 			Size:        size,
+			CreatedAt:   stamp,
+			UpdatedAt:   stamp,
 		}
 	}
 	newCategory := func(name string, tasks []Task) Category {
@@ -766,6 +3028,7 @@ func seedBoard() BoardState {
 		},
 		Backburner:         []Task{},
 		Archives:           []Task{},
+		Inbox:              []Task{},
 		CategoryBackburner: []Category{},
 		CategoryArchives:   []Category{},
 	}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const agingBoard = `{
+	"categories": [
+		{"id":"a","name":"Alpha","tasks":[
+			{"id":"fresh","name":"Fresh","description":"","notes":"","state":"doing","size":1,"createdAt":"2025-03-09T12:00:00Z"},
+			{"id":"old","name":"Old","description":"","notes":"","state":"doing","size":1,"createdAt":"2025-03-01T12:00:00Z"},
+			{"id":"moved","name":"Moved","description":"","notes":"","state":"blocked","size":1,"createdAt":"2025-02-20T12:00:00Z",
+				"history":[{"at":"2025-03-08T12:00:00Z","actor":"sam","action":"state","from":"doing","to":"blocked"}]},
+			{"id":"idle","name":"Idle","description":"","notes":"","state":"todo","size":1,"createdAt":"2025-01-01T12:00:00Z"},
+			{"id":"legacy","name":"Legacy","description":"","notes":"","state":"doing","size":1}
+		]}
+	],
+	"backburner": [
+		{"id":"parked","name":"Parked","description":"","notes":"","state":"doing","size":1,"createdAt":"2025-01-01T12:00:00Z"}
+	],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": [],
+	"staleAfterDays": 5
+}`
+
+func TestTaskAgingProjected(t *testing.T) {
+	store := newTestStore(t, agingBoard, WithClock(fixedClock("2025-03-10T13:00:00Z")))
+	board := store.GetState()
+
+	want := map[string]struct {
+		age, inState int
+		stale        bool
+	}{
+		"fresh": {1, 1, false},
+		"old":   {9, 9, true},
+		"moved": {18, 2, false},
+		"idle":  {68, 68, false},
+		// written before tasks carried timestamps
+		"legacy": {0, 0, false},
+	}
+	for _, task := range board.Categories[0].Tasks {
+		w := want[task.ID]
+		if task.AgeDays != w.age || task.DaysInState != w.inState || task.Stale != w.stale {
+			t.Fatalf("%s: expected age %d, in state %d, stale %v; got %d, %d, %v",
+				task.ID, w.age, w.inState, w.stale, task.AgeDays, task.DaysInState, task.Stale)
+		}
+	}
+	if board.Backburner[0].Stale {
+		t.Fatalf("expected only active tasks flagged stale")
+	}
+
+	stats := store.GetStats()
+	if stats.Stale["a"] != 1 {
+		t.Fatalf("expected one stale task in Alpha, got %v", stats.Stale)
+	}
+}
+
+func TestTaskAgingNeverPersisted(t *testing.T) {
+	store := newTestStore(t, agingBoard, WithClock(fixedClock("2025-03-10T13:00:00Z")))
+	name := "Renamed"
+	if _, _, err := store.UpdateTask("old", TaskPatch{Name: &name}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	data, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatalf("read data: %v", err)
+	}
+	for _, field := range []string{"ageDays", "daysInState", `"stale"`} {
+		if strings.Contains(string(data), field) {
+			t.Fatalf("expected %s kept out of the data file", field)
+		}
+	}
+
+	// a board echoed back by a client must not smuggle them in either
+	board := store.GetState()
+	body, err := json.Marshal(map[string]any{"mode": "replace", "board": board})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if rec := doRequest(t, NewServer(store), http.MethodPost, "/api/v1/import", string(body)); rec.Code != http.StatusOK {
+		t.Fatalf("import: %d %s", rec.Code, rec.Body.String())
+	}
+	data, _ = os.ReadFile(store.path)
+	if strings.Contains(string(data), "ageDays") {
+		t.Fatalf("expected imported projection stripped")
+	}
+}
+
+func TestStaleAfterDaysSetting(t *testing.T) {
+	store := newTestStore(t, agingBoard, WithClock(fixedClock("2025-03-10T13:00:00Z")))
+	srv := NewServer(store)
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/board/settings", `{"staleAfterDays":-1}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/board/settings", `{"staleAfterDays":0}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	for _, task := range store.GetState().Categories[0].Tasks {
+		if task.Stale {
+			t.Fatalf("expected no stale tasks with the setting off, got %s", task.ID)
+		}
+	}
+}
+
+func TestTouchTaskBumpsOnlyUpdatedAt(t *testing.T) {
+	clock := fixedClock("2025-03-10T13:00:00Z")
+	store := newTestStore(t, agingBoard, WithClock(func() time.Time { return clock() }))
+	before, _, err := findTask(&store.state, "parked", nil)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	want := before.Clone()
+
+	clock = fixedClock("2025-03-11T09:30:00Z")
+	srv := NewServer(store)
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks/parked/touch", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	decodeBody(t, rec, &resp)
+	if !resp.Task.UpdatedAt.Equal(clock()) {
+		t.Fatalf("expected updatedAt %v, got %v", clock(), resp.Task.UpdatedAt)
+	}
+
+	got, _, err := findTask(&store.state, "parked", nil)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	touched := got.Clone()
+	touched.UpdatedAt = want.UpdatedAt
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(touched)
+	if string(gotJSON) != string(wantJSON) {
+		t.Fatalf("expected only updatedAt to change\nbefore %s\nafter  %s", wantJSON, gotJSON)
+	}
+
+	if rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks/missing/touch", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing task, got %d", rec.Code)
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/tasks/parked/touch", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
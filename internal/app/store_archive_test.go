@@ -0,0 +1,109 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func joinTaskIDs(tasks []Task) string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+const doneBoard = `{
+	"categories": [
+		{"id":"a","name":"Alpha","tasks":[
+			{"id":"a1","name":"A1","description":"","notes":"","state":"done","size":1},
+			{"id":"a2","name":"A2","description":"","notes":"","state":"doing","size":1},
+			{"id":"a3","name":"A3","description":"","notes":"","state":"delegated","size":1,"urgent":true}
+		]},
+		{"id":"b","name":"Beta","tasks":[
+			{"id":"b1","name":"B1","description":"","notes":"","state":"done","size":1,"focused":true}
+		]}
+	],
+	"backburner": [],
+	"archives": [
+		{"id":"old","name":"Old","description":"","notes":"","state":"done","size":1}
+	],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestArchiveAllDoneTasks(t *testing.T) {
+	store := newTestStore(t, doneBoard)
+
+	archived, board, err := store.ArchiveAllDoneTasks()
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if got := joinTaskIDs(archived); got != "a1,a3,b1" {
+		t.Fatalf("expected a1,a3,b1 archived, got %s", got)
+	}
+	if got := joinTaskIDs(board.Archives); got != "old,a1,a3,b1" {
+		t.Fatalf("expected archive order old,a1,a3,b1, got %s", got)
+	}
+	for _, task := range board.Archives[1:] {
+		if task.Urgent || task.Focused {
+			t.Fatalf("expected urgent/focus cleared on %s", task.ID)
+		}
+	}
+	if board.Archives[3].SourceID != "b" || board.Archives[3].Source != "Beta" {
+		t.Fatalf("expected b1 sourced from Beta, got %s/%s", board.Archives[3].SourceID, board.Archives[3].Source)
+	}
+	if len(board.Categories) != 2 || len(board.Categories[1].Tasks) != 0 {
+		t.Fatalf("expected emptied Beta to remain on the board")
+	}
+	if got := joinTaskIDs(board.Categories[0].Tasks); got != "a2" {
+		t.Fatalf("expected a2 left in Alpha, got %s", got)
+	}
+
+	rec := doRequest(t, NewServer(store), http.MethodPost, "/api/v1/board/archive-done", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestGetArchivedByCategory(t *testing.T) {
+	store := newTestStore(t, doneBoard)
+	if _, _, err := store.ArchiveAllDoneTasks(); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	cat, err := store.GetArchivedByCategory("a")
+	if err != nil {
+		t.Fatalf("alpha archive: %v", err)
+	}
+	if cat.ID != "a" || cat.Name != "Alpha" || joinTaskIDs(cat.Tasks) != "a1,a3" {
+		t.Fatalf("expected Alpha with a1,a3, got %s %q %s", cat.ID, cat.Name, joinTaskIDs(cat.Tasks))
+	}
+	cat, err = store.GetArchivedByCategory("b")
+	if err != nil || cat.Name != "Beta" || joinTaskIDs(cat.Tasks) != "b1" {
+		t.Fatalf("expected Beta with b1, got %q %s (%v)", cat.Name, joinTaskIDs(cat.Tasks), err)
+	}
+
+	cat, err = store.GetArchivedByCategory("nothing-here")
+	if err != nil {
+		t.Fatalf("expected empty category, got %v", err)
+	}
+	if cat.ID != "nothing-here" || cat.Tasks == nil || len(cat.Tasks) != 0 {
+		t.Fatalf("expected empty task list, got %+v", cat)
+	}
+}
+
+func TestArchivedByCategoryEndpoint(t *testing.T) {
+	store := newTestStore(t, doneBoard)
+	if _, _, err := store.ArchiveAllDoneTasks(); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	var resp struct {
+		Category Category `json:"category"`
+	}
+	decodeBody(t, doRequest(t, NewServer(store), http.MethodGet, "/api/v1/archives/categories/a", ""), &resp)
+	if joinTaskIDs(resp.Category.Tasks) != "a1,a3" {
+		t.Fatalf("expected a1,a3, got %s", joinTaskIDs(resp.Category.Tasks))
+	}
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBlockedReasonRequired(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	blocked := "blocked"
+	if _, _, err := store.UpdateTask("t1", TaskPatch{State: &blocked}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest without reason, got %v", err)
+	}
+	if store.GetState().Categories[0].Tasks[0].State != "doing" {
+		t.Fatalf("expected rejected patch to leave state unchanged")
+	}
+
+	reason := "waiting on vendor"
+	task, _, err := store.UpdateTask("t1", TaskPatch{State: &blocked, BlockedReason: &reason})
+	if err != nil {
+		t.Fatalf("block with reason: %v", err)
+	}
+	if task.BlockedReason != reason {
+		t.Fatalf("expected blocked reason %q, got %q", reason, task.BlockedReason)
+	}
+
+	if _, _, err := store.CreateTask(CreateTaskRequest{
+		CategoryID: "cat2",
+		Task:       Task{Name: "New", State: "blocked", Size: 1},
+	}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest on create, got %v", err)
+	}
+}
+
+func TestBlockedReasonClearedWhenUnblocked(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	blocked := "blocked"
+	reason := "waiting on review"
+	if _, _, err := store.UpdateTask("t1", TaskPatch{State: &blocked, BlockedReason: &reason}); err != nil {
+		t.Fatalf("block task: %v", err)
+	}
+	doing := "doing"
+	task, _, err := store.UpdateTask("t1", TaskPatch{State: &doing})
+	if err != nil {
+		t.Fatalf("unblock task: %v", err)
+	}
+	if task.BlockedReason != "" {
+		t.Fatalf("expected blocked reason cleared, got %q", task.BlockedReason)
+	}
+}
+
+func TestBlockedReasonRequiredOnTransition(t *testing.T) {
+	store := newTestStore(t, legacyBlockedBoard)
+
+	blocked := "blocked"
+	if _, _, err := store.UpdateTask("t1", TaskPatch{State: &blocked}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest without reason, got %v", err)
+	}
+	blank := "   "
+	if _, _, err := store.UpdateTask("t1", TaskPatch{State: &blocked, BlockedReason: &blank}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest for blank reason, got %v", err)
+	}
+	long := strings.Repeat("x", MaxBlockedReasonLength+1)
+	if _, _, err := store.UpdateTask("t1", TaskPatch{State: &blocked, BlockedReason: &long}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest for long reason, got %v", err)
+	}
+	padded := "  waiting on legal  "
+	task, _, err := store.UpdateTask("t1", TaskPatch{State: &blocked, BlockedReason: &padded})
+	if err != nil {
+		t.Fatalf("block with reason: %v", err)
+	}
+	if task.BlockedReason != "waiting on legal" {
+		t.Fatalf("expected trimmed reason, got %q", task.BlockedReason)
+	}
+
+	// a task loaded blocked without a reason can still be edited, even when
+	// the client echoes its state back
+	urgent := true
+	if _, _, err := store.UpdateTask("legacy", TaskPatch{State: &blocked, Urgent: &urgent}); err != nil {
+		t.Fatalf("edit legacy blocked task: %v", err)
+	}
+	if _, _, err := store.CreateTask(CreateTaskRequest{CategoryID: "cat1", Task: Task{Name: "New", State: "blocked", Size: 1}}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest creating blocked task without reason, got %v", err)
+	}
+}
+
+const legacyBlockedBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Work","tasks":[
+			{"id":"t1","name":"One","description":"","notes":"","state":"doing","size":1},
+			{"id":"legacy","name":"Old","description":"","notes":"","state":"blocked","size":1}
+		]}
+	],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
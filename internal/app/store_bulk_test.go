@@ -0,0 +1,246 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const bulkBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"t1","name":"One","description":"","notes":"","state":"doing","size":1,"tags":["sprint"]},
+			{"id":"t2","name":"Two","description":"","notes":"","state":"doing","size":1},
+			{"id":"t3","name":"Three","description":"","notes":"","state":"todo","size":1}
+		]},
+		{"id":"cat2","name":"Beta","tasks":[
+			{"id":"t4","name":"Four","description":"","notes":"","state":"doing","size":1,"urgent":true}
+		]}
+	],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestBulkPatchTasksResetsCategory(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	todo := "todo"
+
+	result, board, err := store.BulkPatchTasks(BulkPatchRequest{
+		Filter: TaskFilter{CategoryID: "cat1", State: "doing"},
+		Patch:  TaskPatch{State: &todo},
+	})
+	if err != nil {
+		t.Fatalf("bulk patch: %v", err)
+	}
+	if result.Matched != 2 || result.Changed != 2 {
+		t.Fatalf("expected 2 matched/2 changed, got %d/%d", result.Matched, result.Changed)
+	}
+	for _, task := range board.Categories[0].Tasks {
+		if task.State != "todo" {
+			t.Fatalf("expected task %s reset to todo, got %q", task.ID, task.State)
+		}
+	}
+	if board.Categories[1].Tasks[0].State != "doing" {
+		t.Fatalf("expected other category untouched")
+	}
+}
+
+func TestBulkPatchTasksDryRunAndEmptyMatch(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	urgent := false
+
+	result, _, err := store.BulkPatchTasks(BulkPatchRequest{Patch: TaskPatch{Urgent: &urgent}, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if result.Matched != 4 || result.Changed != 1 {
+		t.Fatalf("expected 4 matched/1 changed, got %d/%d", result.Matched, result.Changed)
+	}
+	if !store.GetState().Categories[1].Tasks[0].Urgent {
+		t.Fatalf("expected dry run to leave state unchanged")
+	}
+
+	result, _, err = store.BulkPatchTasks(BulkPatchRequest{Filter: TaskFilter{Tag: "missing"}, Patch: TaskPatch{Urgent: &urgent}})
+	if err != nil {
+		t.Fatalf("empty match: %v", err)
+	}
+	if result.Matched != 0 || result.Changed != 0 {
+		t.Fatalf("expected zero counts, got %d/%d", result.Matched, result.Changed)
+	}
+}
+
+func TestBulkPatchTasksIgnoresEmptyLists(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	before := store.GetState().Categories[0].Tasks[1]
+	empty := []string{}
+
+	// t2 has no tags or blockers, so setting both to empty lists changes nothing
+	result, board, err := store.BulkPatchTasks(BulkPatchRequest{
+		Filter: TaskFilter{CategoryID: "cat1", State: "doing"},
+		Patch:  TaskPatch{Tags: &empty, BlockedBy: &empty},
+	})
+	if err != nil {
+		t.Fatalf("bulk patch: %v", err)
+	}
+	if result.Matched != 2 || result.Changed != 1 {
+		t.Fatalf("expected 2 matched/1 changed, got %d/%d", result.Matched, result.Changed)
+	}
+	if after := board.Categories[0].Tasks[1]; !after.UpdatedAt.Equal(before.UpdatedAt) {
+		t.Fatalf("expected t2 left unstamped, got %v", after.UpdatedAt)
+	}
+}
+
+func TestBulkPatchTasksRejectsInvalidPatch(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	size := 5
+
+	if _, _, err := store.BulkPatchTasks(BulkPatchRequest{Filter: TaskFilter{CategoryID: "cat1"}, Patch: TaskPatch{Size: &size}}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected ErrCapacityExceeded, got %v", err)
+	}
+	if store.GetState().Categories[0].Tasks[0].Size != 1 {
+		t.Fatalf("expected failed bulk patch to leave sizes unchanged")
+	}
+}
+
+func TestTagTasks(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	tasks, board, err := store.TagTasks(BulkTagRequest{TaskIDs: []string{"t1", "t4"}, Add: []string{"q3", " "}, Remove: []string{"sprint"}})
+	if err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+	if len(tasks) != 2 || strings.Join(tasks[0].Tags, ",") != "q3" || strings.Join(tasks[1].Tags, ",") != "q3" {
+		t.Fatalf("expected q3 on t1 and t4 with sprint gone, got %+v", tasks)
+	}
+	if got := board.Categories[0].Tasks[0].Tags; strings.Join(got, ",") != "q3" {
+		t.Fatalf("expected board updated, got %v", got)
+	}
+	if len(board.Categories[0].Tasks[1].Tags) != 0 {
+		t.Fatalf("expected unlisted tasks untouched")
+	}
+
+	// adding a tag a task already has is a no-op for that task
+	tasks, _, err = store.TagTasks(BulkTagRequest{TaskIDs: []string{"t1"}, Add: []string{"q3"}})
+	if err != nil || len(tasks[0].Tags) != 1 {
+		t.Fatalf("expected no duplicate tag, got %v (%v)", tasks[0].Tags, err)
+	}
+}
+
+func TestTagTasksValidatesEveryID(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, _, err := store.TagTasks(BulkTagRequest{TaskIDs: []string{"t2", "missing"}, Add: []string{"q3"}}); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+	if len(store.GetState().Categories[0].Tasks[1].Tags) != 0 {
+		t.Fatalf("expected no task tagged when any ID is unknown")
+	}
+
+	srv := NewServer(store)
+	for _, body := range []string{
+		`{"taskIds":[],"add":["q3"]}`,
+		`{"taskIds":["t1"]}`,
+		`{"taskIds":["t1"],"add":["x"],"remove":["x"]}`,
+	} {
+		if rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks/tag", body); rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", body, rec.Code)
+		}
+	}
+	if rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks/tag", `{"taskIds":["t2","t3"],"add":["q3"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateTasksInsertsBatchInOrder(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	revision := store.GetState().Revision
+	position := 0
+	tasks, board, err := store.CreateTasks(CreateTasksRequest{
+		Location:   LocationCategory,
+		CategoryID: "cat2",
+		Position:   &position,
+		Tasks: []Task{
+			{Name: "Plan", State: "todo", Size: 1},
+			{Name: "Build", State: "todo", Size: 2},
+			{Name: "Ship", State: "todo", Size: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	if len(tasks) != 3 || tasks[0].Name != "Plan" || tasks[2].Name != "Ship" {
+		t.Fatalf("expected the created tasks in order, got %+v", tasks)
+	}
+	got := []string{}
+	for _, task := range board.Categories[1].Tasks {
+		got = append(got, task.Name)
+	}
+	if strings.Join(got, ",") != "Plan,Build,Ship,Four" {
+		t.Fatalf("expected the batch inserted in order at the position, got %v", got)
+	}
+	if board.Revision != revision+1 {
+		t.Fatalf("expected one write for the batch, revision went %d -> %d", revision, board.Revision)
+	}
+}
+
+func TestCreateTasksRejectsWholeBatch(t *testing.T) {
+	cases := map[string][]Task{
+		// cat2 already holds one point, so the batch only overflows as a whole
+		"combined size": {
+			{Name: "A", State: "todo", Size: 2},
+			{Name: "B", State: "todo", Size: 2},
+			{Name: "C", State: "todo", Size: 1},
+		},
+		"invalid item": {
+			{Name: "A", State: "todo", Size: 1},
+			{Name: "B", State: "todo", Size: 0},
+		},
+		"unknown state": {
+			{Name: "A", State: "todo", Size: 1},
+			{Name: "B", State: "someday", Size: 1},
+		},
+	}
+	for name, batch := range cases {
+		store := newTestStore(t, bulkBoard)
+		before := boardJSON(t, store)
+		if _, _, err := store.CreateTasks(CreateTasksRequest{Location: LocationCategory, CategoryID: "cat2", Tasks: batch}); err == nil {
+			t.Fatalf("%s: expected the batch to be rejected", name)
+		}
+		if after := boardJSON(t, store); after != before {
+			t.Fatalf("%s: expected a rejected batch to create nothing", name)
+		}
+	}
+
+	store := newTestStore(t, bulkBoard)
+	_, _, err := store.CreateTasks(CreateTasksRequest{Location: LocationCategory, CategoryID: "cat2", Tasks: cases["combined size"]})
+	if !errors.Is(err, ErrCapacityExceeded) || !strings.Contains(err.Error(), "tasks[2]") {
+		t.Fatalf("expected a capacity error naming the item that overflowed, got %v", err)
+	}
+}
+
+func TestCreateTasksEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	rec := doRequest(t, srv, http.MethodPost, "/api/tasks", `{"location":"backburner","tasks":[{"name":"A","state":"todo","size":1},{"name":"B","state":"todo","size":3}]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Tasks []Task     `json:"tasks"`
+		Board BoardState `json:"board"`
+	}
+	decodeBody(t, rec, &resp)
+	if len(resp.Tasks) != 2 || resp.Tasks[1].Name != "B" || len(resp.Board.Backburner) != 2 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/tasks", `{"location":"category","categoryId":"cat1","task":{"name":"One more","state":"todo","size":1}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected a single create to work as before, got %d", rec.Code)
+	}
+	rec = doRequest(t, srv, http.MethodPost, "/api/tasks", `{"location":"inbox","task":{"name":"A","state":"todo","size":1},"tasks":[{"name":"B","state":"todo","size":1}]}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected task and tasks together to be rejected, got %d", rec.Code)
+	}
+}
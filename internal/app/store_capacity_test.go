@@ -0,0 +1,323 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const softBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Ideas","tasks":[
+			{"id":"t1","name":"One","description":"","notes":"","state":"todo","size":4}
+		]}
+	],
+	"backburner": [
+		{"id":"b1","name":"Parked","description":"","notes":"","state":"todo","size":2,"sourceId":"cat1","source":"Ideas"}
+	],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": [],
+	"capacityMode": "soft"
+}`
+
+func TestSoftCapacityAllowsOverflow(t *testing.T) {
+	store := newTestStore(t, softBoard)
+
+	_, board, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: Task{Name: "Two", State: "todo", Size: 2}})
+	if err != nil {
+		t.Fatalf("create in soft mode: %v", err)
+	}
+	if !board.Categories[0].OverCapacity {
+		t.Fatalf("expected category flagged over capacity")
+	}
+	if _, _, err := store.MoveTask("b1", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat1"}); err != nil {
+		t.Fatalf("move in soft mode: %v", err)
+	}
+	size := 5
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Size: &size}); err != nil {
+		t.Fatalf("update in soft mode: %v", err)
+	}
+}
+
+func TestHardCapacityAfterSoftBlocksOnlyGrowth(t *testing.T) {
+	store := newTestStore(t, softBoard)
+	if _, _, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: Task{ID: "t2", Name: "Two", State: "todo", Size: 2}}); err != nil {
+		t.Fatalf("create in soft mode: %v", err)
+	}
+	board, err := store.SetCapacityMode(CapacityHard)
+	if err != nil {
+		t.Fatalf("set mode: %v", err)
+	}
+	if len(board.Categories[0].Tasks) != 2 || !board.Categories[0].OverCapacity {
+		t.Fatalf("expected over-limit category left intact")
+	}
+
+	if _, _, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: Task{Name: "Three", State: "todo", Size: 1}}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected ErrCapacityExceeded, got %v", err)
+	}
+	size := 5
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Size: &size}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected growth rejected, got %v", err)
+	}
+	name := "Renamed"
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Name: &name}); err != nil {
+		t.Fatalf("expected rename allowed on over-limit category: %v", err)
+	}
+	if _, err := store.SetCapacityMode("loose"); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest for unknown mode, got %v", err)
+	}
+}
+
+func TestSoftCapacityFlagsResponse(t *testing.T) {
+	srv := NewServer(newTestStore(t, softBoard))
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks?includeBoard=false", `{"location":"category","categoryId":"cat1","task":{"name":"Two","state":"todo","size":2}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		OverCapacity []string `json:"overCapacity"`
+	}
+	decodeBody(t, rec, &resp)
+	if len(resp.OverCapacity) != 1 || resp.OverCapacity[0] != "cat1" {
+		t.Fatalf("expected cat1 flagged, got %v", resp.OverCapacity)
+	}
+}
+
+const fullBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Work","tasks":[
+			{"id":"t1","name":"One","description":"","notes":"","state":"todo","size":5}
+		]}
+	],
+	"backburner": [
+		{"id":"b1","name":"Parked","description":"","notes":"","state":"todo","size":5,"sourceId":"cat1","source":"Work"}
+	],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestForceExceedsCapacityOnce(t *testing.T) {
+	store := newTestStore(t, fullBoard)
+	extra := Task{Name: "Extra", State: "todo", Size: 1}
+
+	if _, _, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: extra}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected ErrCapacityExceeded without force, got %v", err)
+	}
+	_, board, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: extra, Force: true})
+	if err != nil {
+		t.Fatalf("forced create: %v", err)
+	}
+	if !board.Categories[0].OverCapacity {
+		t.Fatalf("expected category flagged over capacity")
+	}
+
+	// the next unforced addition is still rejected
+	if _, _, err := store.MoveTask("b1", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat1"}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected ErrCapacityExceeded without force, got %v", err)
+	}
+	// forcing past MaxForcedCapacity is rejected too (5 + 1 + 5 > 10)
+	if _, _, err := store.MoveTask("b1", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat1", Force: true}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected ErrCapacityExceeded beyond forced maximum, got %v", err)
+	}
+}
+
+func TestBoardsWrittenOverCapacityReopen(t *testing.T) {
+	forced := newTestStore(t, fullBoard)
+	if _, _, err := forced.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: Task{Name: "Extra", State: "todo", Size: 1}, Force: true}); err != nil {
+		t.Fatalf("forced create: %v", err)
+	}
+
+	switched := newTestStore(t, softBoard)
+	if _, _, err := switched.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: Task{Name: "Two", State: "todo", Size: 2}}); err != nil {
+		t.Fatalf("create in soft mode: %v", err)
+	}
+	if _, err := switched.SetCapacityMode(CapacityHard); err != nil {
+		t.Fatalf("switch to hard: %v", err)
+	}
+
+	for name, store := range map[string]*Store{"forced": forced, "switched": switched} {
+		reopened, err := NewStore(store.path)
+		if err != nil {
+			t.Fatalf("%s: reopen: %v", name, err)
+		}
+		if !reopened.GetState().Categories[0].OverCapacity {
+			t.Fatalf("%s: expected the reopened category still over capacity", name)
+		}
+	}
+}
+
+func TestCreateTaskRejectsDuplicateID(t *testing.T) {
+	store := newTestStore(t, softBoard)
+	for _, id := range []string{"t1", "b1"} {
+		if _, _, err := store.CreateTask(CreateTaskRequest{Location: LocationBackburner, Task: Task{ID: id, Name: "Copy", State: "todo", Size: 1}}); !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("%s: expected ErrInvalidRequest, got %v", id, err)
+		}
+	}
+	if _, err := NewStore(store.path); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+}
+
+func TestBoardTaskLimit(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	limit := 3
+	board, err := store.UpdateSettings(BoardSettingsPatch{MaxTasks: &limit})
+	if err != nil {
+		t.Fatalf("set maxTasks: %v", err)
+	}
+	if got := board.Categories[0]; got.TaskCount != 3 || got.TaskLimit != 3 {
+		t.Fatalf("expected taskCount 3 and taskLimit 3, got %d/%d", got.TaskCount, got.TaskLimit)
+	}
+
+	if _, _, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: Task{Name: "Extra", State: "todo", Size: 1}}); !errors.Is(err, ErrTaskLimit) {
+		t.Fatalf("expected ErrTaskLimit on create, got %v", err)
+	}
+	if _, _, err := store.MoveTask("t4", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat1"}); !errors.Is(err, ErrTaskLimit) {
+		t.Fatalf("expected ErrTaskLimit on move, got %v", err)
+	}
+	if got := len(store.GetState().Categories[1].Tasks); got != 1 {
+		t.Fatalf("expected t4 restored to its category, got %d tasks", got)
+	}
+	// reordering within a full category is not growth
+	first := 0
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat1", Position: &first}); err != nil {
+		t.Fatalf("reorder in full category: %v", err)
+	}
+}
+
+func TestCategoryTaskLimitOverridesBoard(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	limit := 1
+	if _, err := store.UpdateSettings(BoardSettingsPatch{MaxTasks: &limit}); err != nil {
+		t.Fatalf("set maxTasks: %v", err)
+	}
+	cat, board, err := store.SetCategoryTaskLimit("cat2", 2)
+	if err != nil {
+		t.Fatalf("set category limit: %v", err)
+	}
+	if cat.MaxTasks != 2 || board.Categories[1].TaskLimit != 2 {
+		t.Fatalf("expected category limit 2, got %d/%d", cat.MaxTasks, board.Categories[1].TaskLimit)
+	}
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat2"}); err != nil {
+		t.Fatalf("expected move under category limit, got %v", err)
+	}
+	if _, _, err := store.MoveTask("t2", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat2"}); !errors.Is(err, ErrTaskLimit) {
+		t.Fatalf("expected ErrTaskLimit, got %v", err)
+	}
+}
+
+func TestTaskLimitOnCategoryRestore(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, _, err := store.MoveCategory("cat1", MoveCategoryRequest{Location: LocationBackburner}); err != nil {
+		t.Fatalf("backburner: %v", err)
+	}
+	limit := 2
+	if _, err := store.UpdateSettings(BoardSettingsPatch{MaxTasks: &limit}); err != nil {
+		t.Fatalf("set maxTasks: %v", err)
+	}
+	if _, _, err := store.MoveCategory("cat1", MoveCategoryRequest{Location: LocationCategoryBoard}); !errors.Is(err, ErrTaskLimit) {
+		t.Fatalf("expected ErrTaskLimit restoring a full category, got %v", err)
+	}
+}
+
+func TestTaskLimitEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/categories/cat2", `{"maxTasks":1}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks", `{"location":"category","categoryId":"cat2","task":{"name":"Five","state":"todo","size":1}}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "would hold 2 tasks, limit is 1") {
+		t.Fatalf("expected count and limit in error, got %s", rec.Body.String())
+	}
+}
+
+func TestCreateTaskIfFits(t *testing.T) {
+	srv := NewServer(newTestStore(t, strings.Replace(softBoard, `"soft"`, `"hard"`, 1)))
+
+	// Ideas holds 4 of 5 points
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks?ifFits=true&includeBoard=false", `{"location":"category","categoryId":"cat1","task":{"id":"fits","name":"Fits","state":"todo","size":1}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 when the task fits, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Created bool `json:"created"`
+		Task    Task `json:"task"`
+	}
+	decodeBody(t, rec, &resp)
+	if !resp.Created || resp.Task.ID != "fits" {
+		t.Fatalf("expected created task, got %+v", resp)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/api/v1/tasks?ifFits=true", `{"location":"category","categoryId":"cat1","task":{"id":"full","name":"Full","state":"todo","size":1}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 when full, got %d: %s", rec.Code, rec.Body.String())
+	}
+	resp.Created = true
+	decodeBody(t, rec, &resp)
+	if resp.Created {
+		t.Fatalf("expected created false, got %s", rec.Body.String())
+	}
+
+	// without the flag a full category is still an error
+	if rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks", `{"location":"category","categoryId":"cat1","task":{"id":"full","name":"Full","state":"todo","size":1}}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 by default, got %d", rec.Code)
+	}
+}
+
+const suggestBoard = `{
+	"categories": [
+		{"id":"full","name":"Full","tasks":[
+			{"id":"f1","name":"F1","description":"","notes":"","state":"todo","size":5}
+		]},
+		{"id":"roomy","name":"Roomy","tasks":[
+			{"id":"r1","name":"R1","description":"","notes":"","state":"todo","size":1}
+		]},
+		{"id":"tight","name":"Tight","tasks":[
+			{"id":"t1","name":"T1","description":"","notes":"","state":"todo","size":4}
+		]},
+		{"id":"empty","name":"Empty","tasks":[]}
+	],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestCapacityConflictSuggestsCategoriesWithRoom(t *testing.T) {
+	srv := NewServer(newTestStore(t, suggestBoard))
+
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks", `{"location":"category","categoryId":"full","task":{"name":"Big","state":"todo","size":2}}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Error       string               `json:"error"`
+		Suggestions []CapacitySuggestion `json:"suggestions"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Error != ErrCapacityExceeded.Error() {
+		t.Fatalf("expected the usual error message, got %q", resp.Error)
+	}
+	got := []string{}
+	for _, s := range resp.Suggestions {
+		got = append(got, s.CategoryID)
+	}
+	// Full is the target and Tight has only 1 point left
+	if strings.Join(got, ",") != "roomy,empty" {
+		t.Fatalf("expected roomy and empty suggested, got %v", got)
+	}
+	if resp.Suggestions[0].Remaining != 4 || resp.Suggestions[1].Remaining != 5 {
+		t.Fatalf("unexpected remaining room %+v", resp.Suggestions)
+	}
+
+	_, _, err := newTestStore(t, suggestBoard).CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "full", Task: Task{Name: "Big", State: "todo", Size: 2}})
+	if !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected the error to still match ErrCapacityExceeded, got %v", err)
+	}
+}
@@ -2,8 +2,10 @@ package app
 
 import (
 	"errors"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -202,3 +204,294 @@ func TestMoveCategoryCapacityCheck(t *testing.T) {
 		t.Fatalf("expected ErrCapacityExceeded, got %v", err)
 	}
 }
+
+func TestBoardProjectionHasFocus(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	_, _, board, err := store.SetFocused("t4")
+	if err != nil {
+		t.Fatalf("set focus: %v", err)
+	}
+	if board.Categories[0].HasFocus {
+		t.Fatalf("expected unfocused category to report HasFocus false")
+	}
+	if !board.Categories[1].HasFocus {
+		t.Fatalf("expected focused task's category to report HasFocus true")
+	}
+
+	reloaded, err := NewStore(store.Path())
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	if reloaded.state.Categories[1].HasFocus {
+		t.Fatalf("expected HasFocus not to be persisted")
+	}
+}
+
+func TestCloneCategoryToEachLocation(t *testing.T) {
+	for _, location := range []string{LocationCategoryBoard, LocationBackburner, LocationArchive} {
+		t.Run(location, func(t *testing.T) {
+			store := newTestStore(t, bulkBoard)
+
+			clone, board, err := store.CloneCategory("cat2", "Beta template", location)
+			if err != nil {
+				t.Fatalf("clone category: %v", err)
+			}
+			if clone.ID == "cat2" || clone.Tasks[0].ID == "t4" {
+				t.Fatalf("expected fresh category and task ids")
+			}
+			var pool []Category
+			switch location {
+			case LocationCategoryBoard:
+				pool = board.Categories
+			case LocationBackburner:
+				pool = board.CategoryBackburner
+			case LocationArchive:
+				pool = board.CategoryArchives
+			}
+			if idx := findCategoryIndex(pool, clone.ID); idx == -1 {
+				t.Fatalf("expected clone in %s pool", location)
+			}
+			if location != LocationCategoryBoard && clone.Tasks[0].Urgent {
+				t.Fatalf("expected urgent flag cleared outside the board")
+			}
+			if !board.Categories[1].Tasks[0].Urgent || board.Categories[1].ID != "cat2" {
+				t.Fatalf("expected source category untouched")
+			}
+
+			if _, _, err := store.CloneCategory("cat2", "Beta template", location); !errors.Is(err, ErrDuplicateCategory) {
+				t.Fatalf("expected ErrDuplicateCategory for repeated name, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCloneCategoryRespectsLimit(t *testing.T) {
+	full := `{
+		"categories": [
+			{"id":"c1","name":"One","tasks":[]},
+			{"id":"c2","name":"Two","tasks":[]},
+			{"id":"c3","name":"Three","tasks":[]},
+			{"id":"c4","name":"Four","tasks":[]},
+			{"id":"c5","name":"Five","tasks":[]}
+		]
+	}`
+	store := newTestStore(t, full)
+
+	if _, _, err := store.CloneCategory("c1", "Six", LocationCategoryBoard); !errors.Is(err, ErrCategoryLimit) {
+		t.Fatalf("expected ErrCategoryLimit, got %v", err)
+	}
+	if _, _, err := store.CloneCategory("c1", "Six", LocationBackburner); err != nil {
+		t.Fatalf("expected backburner clone to ignore the board limit: %v", err)
+	}
+}
+
+const archivedCategoryBoard = `{
+	"categories": [],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [
+		{"id":"shelved","name":"Shelved","tasks":[]}
+	],
+	"categoryArchives": [
+		{"id":"old","name":"Old","tasks":[
+			{"id":"t1","name":"One","description":"","notes":"","state":"done","size":1}
+		]}
+	]
+}`
+
+func TestDeleteCategoryArchiveDiscardsTasks(t *testing.T) {
+	store := newTestStore(t, archivedCategoryBoard)
+
+	board, err := store.DeleteCategoryArchive("old", false)
+	if err != nil {
+		t.Fatalf("delete archived category: %v", err)
+	}
+	if len(board.CategoryArchives) != 0 || len(board.Archives) != 0 {
+		t.Fatalf("expected category and tasks discarded, got %+v / %+v", board.CategoryArchives, board.Archives)
+	}
+	if _, err := store.DeleteCategoryArchive("shelved", false); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected backburnered category to be out of reach, got %v", err)
+	}
+}
+
+func TestDeleteCategoryArchiveKeepsTasks(t *testing.T) {
+	srv := NewServer(newTestStore(t, archivedCategoryBoard))
+
+	rec := doRequest(t, srv, http.MethodDelete, "/api/category-archives/old?keepTasks=true", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Board BoardState `json:"board"`
+	}
+	decodeBody(t, rec, &resp)
+	if len(resp.Board.CategoryArchives) != 0 {
+		t.Fatalf("expected archived category removed")
+	}
+	if len(resp.Board.Archives) != 1 || resp.Board.Archives[0].SourceID != "old" {
+		t.Fatalf("expected task moved to archives with its source, got %+v", resp.Board.Archives)
+	}
+}
+
+const swapBoard = `{
+	"categories": [
+		{"id":"a","name":"A","tasks":[]},
+		{"id":"b","name":"B","tasks":[]},
+		{"id":"c","name":"C","tasks":[]},
+		{"id":"d","name":"D","tasks":[]}
+	],
+	"backburner": [],
+	"archives": [],
+	"categoryBackburner": [{"id":"shelved","name":"Shelved","tasks":[]}],
+	"categoryArchives": []
+}`
+
+func categoryIDs(categories []Category) string {
+	ids := make([]string, len(categories))
+	for i, cat := range categories {
+		ids[i] = cat.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSwapCategories(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"ends", "a", "d", "d,b,c,a"},
+		{"adjacent", "b", "c", "a,c,b,d"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newTestStore(t, swapBoard)
+			board, err := store.SwapCategories(tc.a, tc.b)
+			if err != nil {
+				t.Fatalf("swap: %v", err)
+			}
+			if got := categoryIDs(board.Categories); got != tc.want {
+				t.Fatalf("expected order %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSwapCategoriesRejectsInvalid(t *testing.T) {
+	store := newTestStore(t, swapBoard)
+
+	if _, err := store.SwapCategories("a", "shelved"); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound for non-active category, got %v", err)
+	}
+	if _, err := store.SwapCategories("a", "a"); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest for same id, got %v", err)
+	}
+
+	srv := NewServer(store)
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/categories/swap", `{"categoryAId":"a","categoryBId":"missing"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestRestoreCategoryToPreviousSlot(t *testing.T) {
+	store := newTestStore(t, swapBoard, WithRestorePosition(true))
+	if _, _, err := store.MoveCategory("b", MoveCategoryRequest{Location: LocationArchive}); err != nil {
+		t.Fatalf("archive b: %v", err)
+	}
+	archived := store.GetState().CategoryArchives[0]
+	if archived.LastBoardIndex == nil || *archived.LastBoardIndex != 1 {
+		t.Fatalf("expected last board index 1 recorded, got %v", archived.LastBoardIndex)
+	}
+
+	_, board, err := store.MoveCategory("b", MoveCategoryRequest{Location: LocationCategoryBoard})
+	if err != nil {
+		t.Fatalf("restore b: %v", err)
+	}
+	if got := categoryIDs(board.Categories); got != "a,b,c,d" {
+		t.Fatalf("expected b back in its slot, got %s", got)
+	}
+	if board.Categories[1].LastBoardIndex != nil {
+		t.Fatalf("expected last board index cleared once back on the board")
+	}
+}
+
+func TestRestoreCategoryAppendsByDefault(t *testing.T) {
+	store := newTestStore(t, swapBoard)
+	if _, _, err := store.MoveCategory("b", MoveCategoryRequest{Location: LocationArchive}); err != nil {
+		t.Fatalf("archive b: %v", err)
+	}
+	_, board, err := store.MoveCategory("b", MoveCategoryRequest{Location: LocationCategoryBoard})
+	if err != nil {
+		t.Fatalf("restore b: %v", err)
+	}
+	if got := categoryIDs(board.Categories); got != "a,c,d,b" {
+		t.Fatalf("expected b appended, got %s", got)
+	}
+}
+
+func TestReorderCategoryTasks(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	cat, _, err := store.ReorderCategoryTasks("cat1", []string{"t3", "t1", "t2"})
+	if err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	if got := cat.Tasks[0].ID + cat.Tasks[1].ID + cat.Tasks[2].ID; got != "t3t1t2" {
+		t.Fatalf("expected t3 t1 t2, got %s", got)
+	}
+}
+
+func TestReorderRejectsForeignTaskID(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	// right length, but t4 lives in Beta
+	_, _, err := store.ReorderCategoryTasks("cat1", []string{"t1", "t4", "t3"})
+	if !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), "t4 is not in category Alpha") {
+		t.Fatalf("expected foreign id rejected, got %v", err)
+	}
+	if _, _, err := store.ReorderCategoryTasks("cat1", []string{"t1", "t1", "t3"}); !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), "more than once") {
+		t.Fatalf("expected duplicate id rejected, got %v", err)
+	}
+	if ids := store.GetState().Categories[0].Tasks; ids[0].ID != "t1" || ids[1].ID != "t2" {
+		t.Fatalf("expected order untouched after rejected reorder")
+	}
+}
+
+func TestParkCategoryKeepsEmptyCategory(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/categories/cat1/park", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Tasks []Task     `json:"tasks"`
+		Board BoardState `json:"board"`
+	}
+	decodeBody(t, rec, &resp)
+	if len(resp.Tasks) != 3 {
+		t.Fatalf("expected 3 tasks parked, got %d", len(resp.Tasks))
+	}
+	if got := categoryIDs(resp.Board.Categories); got != "cat1,cat2" {
+		t.Fatalf("expected the category kept on the board, got %s", got)
+	}
+	if n := len(resp.Board.Categories[0].Tasks); n != 0 {
+		t.Fatalf("expected cat1 empty, got %d tasks", n)
+	}
+	ids := []string{}
+	for _, task := range resp.Board.Backburner {
+		if task.SourceID != "cat1" || task.Source != "Alpha" {
+			t.Fatalf("expected source cat1/Alpha, got %s/%s", task.SourceID, task.Source)
+		}
+		ids = append(ids, task.ID)
+	}
+	if strings.Join(ids, ",") != "t1,t2,t3" {
+		t.Fatalf("expected tasks parked in order, got %v", ids)
+	}
+
+	if rec := doRequest(t, srv, http.MethodPost, "/api/v1/categories/missing/park", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
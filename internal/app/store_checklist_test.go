@@ -0,0 +1,81 @@
+package app
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const checklistBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"none","name":"No list","state":"todo","size":1},
+			{"id":"open","name":"Open items","state":"delegated","size":1,"delegatedTo":"sam","checklist":[{"text":"a","done":true},{"text":"b"}]},
+			{"id":"done","name":"All done","state":"doing","size":1,"checklist":[{"text":"a","done":true}]}
+		]}
+	],
+	"backburner": [
+		{"id":"later","name":"Later","state":"todo","size":1,"checklist":[{"text":"a"}]}
+	]
+}`
+
+func checklistResultIDs(results []SearchResult) []string {
+	ids := []string{}
+	for _, result := range results {
+		ids = append(ids, result.Task.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestGetTasksByChecklist(t *testing.T) {
+	store := newTestStore(t, checklistBoard)
+	yes, no := true, false
+	cases := []struct {
+		name                  string
+		hasChecklist, allDone *bool
+		want                  []string
+	}{
+		{"no filters", nil, nil, []string{"done", "later", "none", "open"}},
+		{"has checklist", &yes, nil, []string{"done", "later", "open"}},
+		{"no checklist", &no, nil, []string{"none"}},
+		{"all done", nil, &yes, []string{"done"}},
+		{"open items", nil, &no, []string{"later", "open"}},
+		{"has checklist and all done", &yes, &yes, []string{"done"}},
+		{"has checklist and open items", &yes, &no, []string{"later", "open"}},
+		{"no checklist and all done", &no, &yes, []string{}},
+		{"no checklist and open items", &no, &no, []string{}},
+	}
+	for _, tc := range cases {
+		results, err := store.GetTasksByChecklist(tc.hasChecklist, tc.allDone)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := checklistResultIDs(results); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestGetTasksByChecklistEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, checklistBoard))
+	rec := doRequest(t, srv, http.MethodGet, "/api/tasks?hasChecklist=true&allDone=false", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Tasks []SearchResult `json:"tasks"`
+	}
+	decodeBody(t, rec, &resp)
+	if got := checklistResultIDs(resp.Tasks); !reflect.DeepEqual(got, []string{"later", "open"}) {
+		t.Fatalf("expected tasks with open items, got %v", got)
+	}
+	if resp.Tasks[0].Pool == "" {
+		t.Fatalf("expected results to say where each task lives")
+	}
+
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/tasks?allDone=maybe", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad flag, got %d", rec.Code)
+	}
+}
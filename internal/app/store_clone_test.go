@@ -0,0 +1,118 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCloneToWritesSnapshot(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	target := filepath.Join(store.DataDir(), "nested", "experiment.json")
+
+	written, clone, err := store.CloneTo("nested/experiment.json", true, false)
+	if err != nil {
+		t.Fatalf("clone board: %v", err)
+	}
+	if written != target {
+		t.Fatalf("expected the clone resolved to %s, got %s", target, written)
+	}
+	if clone.Categories[0].ID == "active" {
+		t.Fatalf("expected category ids regenerated")
+	}
+	if clone.Backburner[0].SourceID != clone.Categories[0].ID {
+		t.Fatalf("expected backburner source to follow regenerated category id")
+	}
+
+	reopened, err := NewStore(target)
+	if err != nil {
+		t.Fatalf("open clone: %v", err)
+	}
+	if got := reopened.GetState().Categories[0].ID; got != clone.Categories[0].ID {
+		t.Fatalf("expected clone on disk to match snapshot, got %q", got)
+	}
+	if store.GetState().Categories[0].ID != "active" {
+		t.Fatalf("expected original board untouched")
+	}
+}
+
+func TestCloneToRemapsBlockedBy(t *testing.T) {
+	store := newTestStore(t, strings.Replace(bulkBoard, `"name":"Two"`, `"name":"Two","blockedBy":["t1"]`, 1))
+
+	_, clone, err := store.CloneTo("blocked.json", true, false)
+	if err != nil {
+		t.Fatalf("clone board: %v", err)
+	}
+	first, second := clone.Categories[0].Tasks[0], clone.Categories[0].Tasks[1]
+	if first.ID == "t1" {
+		t.Fatalf("expected task ids regenerated")
+	}
+	if len(second.BlockedBy) != 1 || second.BlockedBy[0] != first.ID {
+		t.Fatalf("expected blockedBy to follow the regenerated id %s, got %v", first.ID, second.BlockedBy)
+	}
+	if got := store.GetState().Categories[0].Tasks[1].BlockedBy; len(got) != 1 || got[0] != "t1" {
+		t.Fatalf("expected original board untouched, got %v", got)
+	}
+}
+
+func TestCloneToRefusesOverwrite(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	target := filepath.Join(store.DataDir(), "existing.json")
+	if err := os.WriteFile(target, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write existing: %v", err)
+	}
+
+	if _, _, err := store.CloneTo(target, false, false); !errors.Is(err, ErrFileExists) {
+		t.Fatalf("expected ErrFileExists, got %v", err)
+	}
+	if _, _, err := store.CloneTo(target, false, true); err != nil {
+		t.Fatalf("forced clone: %v", err)
+	}
+	if _, _, err := store.CloneTo(store.path, false, true); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected cloning onto the served file to be rejected, got %v", err)
+	}
+}
+
+func TestCloneToStaysInDataDir(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	outside := filepath.Join(t.TempDir(), "escape.json")
+	for _, path := range []string{outside, "../escape.json", "nested/../../escape.json", ".", ""} {
+		if _, _, err := store.CloneTo(path, false, true); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%q: expected ErrInvalidRequest, got %v", path, err)
+		}
+	}
+	if _, err := os.Stat(outside); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected nothing written outside the data dir, got %v", err)
+	}
+}
+
+func TestCloneEndpoint(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	body := `{"path":"experiment.json","serve":true}`
+
+	rec := doRequest(t, NewServer(store), http.MethodPost, "/admin/board/clone", body)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without -allow-file-admin, got %d", rec.Code)
+	}
+
+	original := store.Path()
+	rec = doRequest(t, NewServer(store, WithFileAdmin()), http.MethodPost, "/admin/board/clone", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Path    string `json:"path"`
+		Serving bool   `json:"serving"`
+	}
+	decodeBody(t, rec, &resp)
+	want := filepath.Join(store.DataDir(), "experiment.json")
+	if resp.Path != want || !resp.Serving || store.Path() != want {
+		t.Fatalf("expected the clone at %s served, got %+v serving %s", want, resp, store.Path())
+	}
+	if _, err := os.Stat(original); err != nil {
+		t.Fatalf("expected the original file kept: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCompactShrinksBoardFile(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	// leave cat2 empty so the saved file carries an empty task list
+	if _, _, err := store.MoveTask("t4", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat1"}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	before, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatalf("read board: %v", err)
+	}
+	board := boardJSON(t, store)
+
+	if err := store.Compact(); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	after, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatalf("read board: %v", err)
+	}
+	if len(after) >= len(before) {
+		t.Fatalf("expected the file to shrink, went %d -> %d bytes", len(before), len(after))
+	}
+	if strings.Contains(string(after), "[]") || strings.Contains(string(after), "null") {
+		t.Fatalf("expected no empty lists left in the file:\n%s", after)
+	}
+	if got := boardJSON(t, store); got != board {
+		t.Fatalf("expected compacting to leave the board unchanged\nbefore %s\nafter  %s", board, got)
+	}
+
+	reopened, err := NewStore(store.path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := boardJSON(t, reopened); got != board {
+		t.Fatalf("expected the reloaded board to match\nbefore %s\nafter  %s", board, got)
+	}
+	if tasks := reopened.GetState().Categories[1].Tasks; tasks == nil {
+		t.Fatalf("expected the empty category to still serve an empty task list")
+	}
+}
+
+func TestCompactEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	rec := doRequest(t, srv, http.MethodPost, "/api/board/compact", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/v1/board/compact", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
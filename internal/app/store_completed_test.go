@@ -0,0 +1,76 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+const completedBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"sundayLate","name":"Sunday","description":"","notes":"","state":"done","size":1,
+				"completedAt":"2024-03-10T23:59:00Z"},
+			{"id":"mondayStart","name":"Monday","description":"","notes":"","state":"done","size":1,
+				"completedAt":"2024-03-11T00:00:00Z"},
+			{"id":"reopened","name":"Reopened","description":"","notes":"","state":"doing","size":1,
+				"completedAt":"2024-03-12T10:00:00Z"}
+		]}
+	],
+	"backburner": [],
+	"archives": [
+		{"id":"delegated","name":"Delegated","description":"","notes":"","state":"delegated","size":1,
+			"completedAt":"2024-03-17T23:59:00Z"},
+		{"id":"nextMonday","name":"Next Monday","description":"","notes":"","state":"done","size":1,
+			"completedAt":"2024-03-18T00:00:00Z"}
+	],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestGetCompletedTasksThisWeekBoundaries(t *testing.T) {
+	// Wednesday 2024-03-13; the ISO week runs Monday 11th to Sunday 17th.
+	store := newTestStore(t, completedBoard, WithClock(fixedClock("2024-03-13T12:00:00Z")), WithLocation(time.UTC))
+
+	results, err := store.GetCompletedTasksThisWeek()
+	if err != nil {
+		t.Fatalf("completed this week: %v", err)
+	}
+	ids := taskIDs(results)
+	if len(ids) != 2 || ids[0] != "mondayStart" || ids[1] != "delegated" {
+		t.Fatalf("expected mondayStart and delegated, got %v", ids)
+	}
+}
+
+func TestGetCompletedTasksThisWeekOnSunday(t *testing.T) {
+	store := newTestStore(t, completedBoard, WithClock(fixedClock("2024-03-17T22:00:00Z")), WithLocation(time.UTC))
+
+	results, err := store.GetCompletedTasksThisWeek()
+	if err != nil {
+		t.Fatalf("completed this week: %v", err)
+	}
+	if ids := taskIDs(results); len(ids) != 2 {
+		t.Fatalf("expected Sunday to stay in the Monday-started week, got %v", ids)
+	}
+}
+
+func TestCompletionStampFollowsState(t *testing.T) {
+	store := newTestStore(t, bulkBoard, WithClock(fixedClock("2024-03-13T12:00:00Z")))
+
+	done := "done"
+	task, _, err := store.UpdateTask("t1", TaskPatch{State: &done})
+	if err != nil {
+		t.Fatalf("complete task: %v", err)
+	}
+	if task.CompletedAt == nil || !task.CompletedAt.Equal(fixedClock("2024-03-13T12:00:00Z")()) {
+		t.Fatalf("expected completion stamp, got %v", task.CompletedAt)
+	}
+
+	todo := "todo"
+	task, _, err = store.UpdateTask("t1", TaskPatch{State: &todo})
+	if err != nil {
+		t.Fatalf("reopen task: %v", err)
+	}
+	if task.CompletedAt != nil {
+		t.Fatalf("expected completion stamp cleared on reopen")
+	}
+}
@@ -0,0 +1,230 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func focusedTaskIDs(board BoardState) []string {
+	var ids []string
+	forEachPoolTask(&board, func(task *Task, _ bool) {
+		if task.Focused {
+			ids = append(ids, task.ID)
+		}
+	})
+	return ids
+}
+
+func TestFocusClearedWhenFocusedTaskLeavesBoard(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(s *Store) (Task, BoardState, error)
+	}{
+		{"patch to done", func(s *Store) (Task, BoardState, error) {
+			done := "done"
+			return s.UpdateTask("t1", TaskPatch{State: &done})
+		}},
+		{"move to archive", func(s *Store) (Task, BoardState, error) {
+			return s.MoveTask("t1", MoveTaskRequest{Location: LocationArchive})
+		}},
+		{"move to backburner", func(s *Store) (Task, BoardState, error) {
+			return s.MoveTask("t1", MoveTaskRequest{Location: LocationBackburner})
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newTestStore(t, bulkBoard)
+			if _, _, _, err := store.SetFocused("t1"); err != nil {
+				t.Fatalf("focus: %v", err)
+			}
+			revision := store.GetState().Revision
+
+			task, board, err := tc.mutate(store)
+			if err != nil {
+				t.Fatalf("mutate: %v", err)
+			}
+			if task.Focused || len(focusedTaskIDs(board)) != 0 {
+				t.Fatalf("expected focus cleared, still focused: %v", focusedTaskIDs(board))
+			}
+			if board.Categories[0].HasFocus {
+				t.Fatalf("expected category to report no focus")
+			}
+			if board.Revision != revision+1 {
+				t.Fatalf("expected focus cleared in the same write, revision went %d -> %d", revision, board.Revision)
+			}
+		})
+	}
+}
+
+func TestFocusKeptAcrossActiveCategories(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, _, _, err := store.SetFocused("t1"); err != nil {
+		t.Fatalf("focus: %v", err)
+	}
+	_, board, err := store.MoveTask("t1", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat2"})
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if got := focusedTaskIDs(board); len(got) != 1 || got[0] != "t1" {
+		t.Fatalf("expected t1 to keep focus, got %v", got)
+	}
+	doing := "doing"
+	if _, board, err = store.UpdateTask("t1", TaskPatch{State: &doing}); err != nil || len(focusedTaskIDs(board)) != 1 {
+		t.Fatalf("expected non-done patch to keep focus, got %v (%v)", focusedTaskIDs(board), err)
+	}
+}
+
+func TestSetFocusedRejectsTasksOffTheBoard(t *testing.T) {
+	for _, id := range []string{"b1", "r1", "ca1", "cb1"} {
+		store := newTestStore(t, poolBoard)
+		if _, _, _, err := store.SetFocused(id); !errors.Is(err, ErrNotFocusable) {
+			t.Fatalf("%s: expected ErrNotFocusable, got %v", id, err)
+		}
+		if got := focusedTaskIDs(store.GetState()); len(got) != 0 {
+			t.Fatalf("%s: expected nothing focused, got %v", id, got)
+		}
+	}
+
+	store := newTestStore(t, poolBoard)
+	if _, _, _, err := store.SetFocused("missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+	if _, _, _, err := store.SetFocused("a1"); err != nil {
+		t.Fatalf("focus active task: %v", err)
+	}
+	if _, _, board, err := store.SetFocused(""); err != nil || len(focusedTaskIDs(board)) != 0 {
+		t.Fatalf("expected empty ID to clear focus, got %v (%v)", focusedTaskIDs(board), err)
+	}
+
+	srv := NewServer(store)
+	if rec := doRequest(t, srv, http.MethodPost, "/api/v1/board/focus", `{"taskId":"r1"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestSetFocusedReportsChange(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, changed, _, err := store.SetFocused(""); err != nil || changed {
+		t.Fatalf("expected clearing with nothing focused to report no change, got %v (%v)", changed, err)
+	}
+	if _, changed, _, err := store.SetFocused("t1"); err != nil || !changed {
+		t.Fatalf("expected focusing t1 to report a change, got %v (%v)", changed, err)
+	}
+	if _, changed, _, err := store.SetFocused("t1"); err != nil || changed {
+		t.Fatalf("expected refocusing t1 to report no change, got %v (%v)", changed, err)
+	}
+
+	srv := NewServer(store)
+	for i, want := range []bool{true, false} {
+		rec := doRequest(t, srv, http.MethodPost, "/api/v1/board/focus", `{"taskId":""}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("clear %d: expected 200, got %d", i, rec.Code)
+		}
+		var resp struct {
+			Changed bool `json:"changed"`
+		}
+		decodeBody(t, rec, &resp)
+		if resp.Changed != want {
+			t.Fatalf("clear %d: expected changed=%v, got %v", i, want, resp.Changed)
+		}
+	}
+}
+
+func TestGetFocusedTaskContext(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	ctx, err := store.GetFocusedTaskContext()
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if ctx.Focused.ID != "" || ctx.Previous != nil || ctx.Next != nil || ctx.CategoryID != "" {
+		t.Fatalf("expected an empty context with nothing focused, got %+v", ctx)
+	}
+
+	cases := []struct {
+		id             string
+		previous, next string
+		position       int
+	}{
+		{"t1", "", "t2", 0},
+		{"t2", "t1", "t3", 1},
+		{"t3", "t2", "", 2},
+	}
+	for _, tc := range cases {
+		if _, _, _, err := store.SetFocused(tc.id); err != nil {
+			t.Fatalf("focus %s: %v", tc.id, err)
+		}
+		ctx, err := store.GetFocusedTaskContext()
+		if err != nil {
+			t.Fatalf("%s: context: %v", tc.id, err)
+		}
+		if ctx.Focused.ID != tc.id || ctx.CategoryID != "cat1" || ctx.CategoryName != "Alpha" || ctx.CategoryPosition != tc.position || ctx.CategorySize != 3 {
+			t.Fatalf("%s: unexpected context %+v", tc.id, ctx)
+		}
+		if got := taskIDOrEmpty(ctx.Previous); got != tc.previous {
+			t.Fatalf("%s: expected previous %q, got %q", tc.id, tc.previous, got)
+		}
+		if got := taskIDOrEmpty(ctx.Next); got != tc.next {
+			t.Fatalf("%s: expected next %q, got %q", tc.id, tc.next, got)
+		}
+	}
+
+	rec := doRequest(t, NewServer(store), http.MethodGet, "/api/board/focus/context", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	decodeBody(t, rec, &ctx)
+	if ctx.Focused.ID != "t3" || ctx.Next != nil {
+		t.Fatalf("unexpected context %+v", ctx)
+	}
+}
+
+func taskIDOrEmpty(task *Task) string {
+	if task == nil {
+		return ""
+	}
+	return task.ID
+}
+
+func TestMoveFocusedTask(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, _, err := store.MoveFocusedTask(MoveTaskRequest{Location: LocationArchive}); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound with nothing focused, got %v", err)
+	}
+
+	if _, _, _, err := store.SetFocused("t2"); err != nil {
+		t.Fatalf("focus: %v", err)
+	}
+	task, board, err := store.MoveFocusedTask(MoveTaskRequest{Location: LocationArchive, Actor: "kim"})
+	if err != nil {
+		t.Fatalf("move focused: %v", err)
+	}
+	if task.ID != "t2" || len(board.Archives) != 1 || board.Archives[0].ID != "t2" {
+		t.Fatalf("expected t2 archived, got %+v", board.Archives)
+	}
+	if ids := focusedTaskIDs(board); len(ids) != 0 {
+		t.Fatalf("expected focus released by the archive, got %v", ids)
+	}
+}
+
+func TestMoveFocusedTaskEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	rec := doRequest(t, srv, http.MethodPost, "/api/board/focus/move", `{"location":"archive"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 with nothing focused, got %d", rec.Code)
+	}
+
+	doRequest(t, srv, http.MethodPost, "/api/board/focus", `{"taskId":"t3"}`)
+	rec = doRequest(t, srv, http.MethodPost, "/api/board/focus/move", `{"location":"category","categoryId":"cat2"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task  Task       `json:"task"`
+		Board BoardState `json:"board"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Task.ID != "t3" || len(resp.Board.Categories[1].Tasks) != 2 || resp.Board.Categories[1].Tasks[1].ID != "t3" {
+		t.Fatalf("expected t3 moved into Beta, got %+v", resp.Board.Categories[1].Tasks)
+	}
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestInboxTaskMovesToCategory(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	task, board, err := store.CreateTask(CreateTaskRequest{Location: LocationInbox, Task: Task{ID: "in1", Name: "Triage me", State: "todo", Size: 1, Urgent: true, Focused: true}})
+	if err != nil {
+		t.Fatalf("create in inbox: %v", err)
+	}
+	if task.Urgent || task.Focused {
+		t.Fatalf("expected inbox task without urgent or focus")
+	}
+	if len(board.Inbox) != 1 || board.Inbox[0].ID != "in1" {
+		t.Fatalf("expected task in inbox, got %+v", board.Inbox)
+	}
+
+	moved, board, err := store.MoveTask("in1", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat2"})
+	if err != nil {
+		t.Fatalf("move to category: %v", err)
+	}
+	if moved.SourceID != "" || len(board.Inbox) != 0 {
+		t.Fatalf("expected inbox emptied, got %+v", board.Inbox)
+	}
+	if got := board.Categories[1].Tasks; len(got) != 2 || got[1].ID != "in1" {
+		t.Fatalf("expected in1 appended to Beta, got %+v", got)
+	}
+}
+
+func TestMoveToInboxDropsCategory(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	moved, board, err := store.MoveTask("t4", MoveTaskRequest{Location: LocationInbox})
+	if err != nil {
+		t.Fatalf("move to inbox: %v", err)
+	}
+	if moved.Urgent || moved.SourceID != "" || moved.Source != "" {
+		t.Fatalf("expected no urgency or source in inbox, got %+v", moved)
+	}
+	if len(board.Inbox) != 1 || len(board.Categories[1].Tasks) != 0 {
+		t.Fatalf("expected t4 in inbox only")
+	}
+	if _, _, _, err := store.SetFocused("t4"); !errors.Is(err, ErrNotFocusable) {
+		t.Fatalf("expected ErrNotFocusable for inbox task, got %v", err)
+	}
+}
+
+func TestCreateInboxTaskEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks", `{"location":"inbox","task":{"name":"Later","state":"todo","size":1}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Board BoardState `json:"board"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Board.Inbox) != 1 || resp.Board.Inbox[0].Name != "Later" {
+		t.Fatalf("expected task in inbox, got %+v", resp.Board.Inbox)
+	}
+}
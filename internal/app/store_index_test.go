@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTaskIndexConsistentAfterMutations(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	check := func(step string) {
+		t.Helper()
+		store.mu.RLock()
+		defer store.mu.RUnlock()
+		if err := checkTaskIndex(&store.state, store.taskIndex); err != nil {
+			t.Fatalf("after %s: %v", step, err)
+		}
+	}
+	check("load")
+
+	created, _, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "active", Task: Task{Name: "New", State: "todo", Size: 1}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	check("create")
+	if _, _, err := store.MoveTask("a1", MoveTaskRequest{Location: LocationBackburner}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	check("move")
+	if _, _, err := store.MoveTaskBetweenPools("r1", PoolArchive, PoolActive); err != nil {
+		t.Fatalf("move between pools: %v", err)
+	}
+	check("move between pools")
+	if _, err := store.DeleteTask("r2"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	check("delete")
+	renamed := "Renamed"
+	if _, _, err := store.UpdateTask(created.ID, TaskPatch{Name: &renamed}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	check("update")
+
+	store.mu.RLock()
+	task, _, err := findTask(&store.state, created.ID, store.taskIndex)
+	store.mu.RUnlock()
+	if err != nil || task.Name != "Renamed" {
+		t.Fatalf("expected indexed lookup of renamed task, got %v %v", task, err)
+	}
+}
+
+func TestFindTaskFallsBackOnStaleIndex(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	stale := map[string]taskLocation{"b2": {Kind: LocationBackburner, TaskIndex: 0}}
+	task, loc, err := findTask(&store.state, "b2", stale)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if task.ID != "b2" || loc.TaskIndex != 1 {
+		t.Fatalf("expected b2 at backburner index 1, got %s at %d", task.ID, loc.TaskIndex)
+	}
+}
+
+func BenchmarkFindTask(b *testing.B) {
+	state := emptyBoard()
+	for i := 0; i < 1000; i++ {
+		state.Archives = append(state.Archives, Task{ID: fmt.Sprintf("task-%d", i), State: "done", Size: 1})
+	}
+	index, err := buildTaskIndex(&state)
+	if err != nil {
+		b.Fatalf("build index: %v", err)
+	}
+	const target = "task-999"
+
+	b.Run("indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := findTask(&state, target, index); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := findTask(&state, target, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
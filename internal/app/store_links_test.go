@@ -0,0 +1,80 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLinkSchemesDefaultToHTTP(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	ok := []TaskLink{{Text: "Doc", URL: "https://example.com/doc"}}
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Links: &ok}); err != nil {
+		t.Fatalf("https link rejected: %v", err)
+	}
+	bad := []TaskLink{{Text: "Vault", URL: "obsidian://open?vault=notes"}}
+	_, _, err := store.UpdateTask("t1", TaskPatch{Links: &bad})
+	if !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), "obsidian") {
+		t.Fatalf("expected ErrInvalidRequest naming the scheme, got %v", err)
+	}
+	if _, _, err := store.CreateTask(CreateTaskRequest{
+		CategoryID: "cat2",
+		Task:       Task{Name: "New", State: "todo", Size: 1, Links: bad},
+	}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest on create, got %v", err)
+	}
+}
+
+func TestLinkSchemesConfigured(t *testing.T) {
+	board := `{
+		"categories": [
+			{"id":"cat1","name":"Alpha","tasks":[
+				{"id":"t1","name":"One","description":"","notes":"","state":"todo","size":1,
+					"links":[{"text":"Old","url":"http://example.com"}]}
+			]}
+		]
+	}`
+	store := newTestStore(t, board, WithLinkSchemes("https", "obsidian", "file"))
+
+	if got := store.GetState().Categories[0].Tasks[0].Links; len(got) != 1 {
+		t.Fatalf("expected persisted http link to still load, got %+v", got)
+	}
+	vault := []TaskLink{{Text: "Vault", URL: "obsidian://open?vault=notes"}, {Text: "File", URL: "file:///tmp/notes.md"}}
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Links: &vault}); err != nil {
+		t.Fatalf("configured schemes rejected: %v", err)
+	}
+	old := []TaskLink{{Text: "Old", URL: "http://example.com"}}
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Links: &old}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected resubmitted http link rejected, got %v", err)
+	}
+}
+
+func TestLinkSchemesCheckedOnImports(t *testing.T) {
+	incoming := `{
+		"categories": [
+			{"id":"cat9","name":"Gamma","tasks":[
+				{"id":"g1","name":"G1","state":"todo","size":1,
+					"links":[{"text":"Run","url":"javascript:alert(1)"}]}
+			]}
+		]
+	}`
+	store := newTestStore(t, bulkBoard)
+	before := boardJSON(t, store)
+
+	for _, mode := range []string{ImportModeReplace, ImportModeMerge} {
+		if _, _, err := store.Import(ImportRequest{Mode: mode, Board: decodeBoard(t, incoming)}); !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("%s: expected ErrInvalidRequest, got %v", mode, err)
+		}
+	}
+	if _, err := store.MergeBoardFromFile(writeMergeSource(t, incoming)); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("file merge: expected ErrInvalidRequest, got %v", err)
+	}
+	cat := decodeBoard(t, incoming).Categories[0]
+	if _, _, err := store.ImportCategory(cat); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("category import: expected ErrInvalidRequest, got %v", err)
+	}
+	if boardJSON(t, store) != before {
+		t.Fatalf("expected rejected imports to leave the board as it was")
+	}
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"errors"
+	"testing"
+)
+
+func lockedStore(t *testing.T) *Store {
+	t.Helper()
+	store := newTestStore(t, bulkBoard)
+	cat, _, err := store.LockCategory("cat1", true)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if !cat.Locked {
+		t.Fatalf("expected category reported locked")
+	}
+	return store
+}
+
+func TestLockedCategoryRejectsAdd(t *testing.T) {
+	store := lockedStore(t)
+
+	_, _, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "cat1", Task: Task{Name: "Extra", State: "todo", Size: 1}})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if got := len(store.GetState().Categories[0].Tasks); got != 3 {
+		t.Fatalf("expected 3 tasks in locked category, got %d", got)
+	}
+}
+
+func TestLockedCategoryRejectsMoves(t *testing.T) {
+	store := lockedStore(t)
+
+	// out of the locked category
+	if _, _, err := store.MoveTask("t1", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat2"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest moving out, got %v", err)
+	}
+	// into the locked category
+	if _, _, err := store.MoveTask("t4", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat1"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest moving in, got %v", err)
+	}
+	board := store.GetState()
+	if len(board.Categories[0].Tasks) != 3 || len(board.Categories[1].Tasks) != 1 {
+		t.Fatalf("expected both categories unchanged, got %d and %d tasks", len(board.Categories[0].Tasks), len(board.Categories[1].Tasks))
+	}
+}
+
+func TestLockedCategoryRejectsRemove(t *testing.T) {
+	store := lockedStore(t)
+
+	if _, _, err := store.MoveTask("t2", MoveTaskRequest{Location: LocationArchive}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest archiving, got %v", err)
+	}
+	if _, _, err := store.MoveTaskBetweenPools("t2", PoolActive, PoolBackburner); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest moving between pools, got %v", err)
+	}
+	if got := len(store.GetState().Backburner) + len(store.GetState().Archives); got != 0 {
+		t.Fatalf("expected nothing removed, got %d tasks out of the category", got)
+	}
+}
+
+func TestLockedCategoryAllowsUpdates(t *testing.T) {
+	store := lockedStore(t)
+	done := "done"
+
+	task, _, err := store.UpdateTask("t3", TaskPatch{State: &done})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if task.State != "done" {
+		t.Fatalf("expected state done, got %s", task.State)
+	}
+
+	if _, _, err := store.LockCategory("cat1", false); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationArchive}); err != nil {
+		t.Fatalf("expected move allowed once unlocked, got %v", err)
+	}
+}
+
+func TestLockUnknownCategory(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, _, err := store.LockCategory("nope", true); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMoveTaskByCategoryName(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	_, board, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationCategory, CategoryName: "beta"})
+	if err != nil {
+		t.Fatalf("move by name: %v", err)
+	}
+	if len(board.Categories[1].Tasks) != 2 || board.Categories[1].Tasks[1].ID != "t3" {
+		t.Fatalf("expected t3 moved into Beta, got %+v", board.Categories[1].Tasks)
+	}
+}
+
+func TestMoveTaskByUnknownCategoryName(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationCategory, CategoryName: "Gamma"}); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+	if len(store.GetState().Categories[0].Tasks) != 3 {
+		t.Fatalf("expected task to stay put after failed move")
+	}
+}
+
+func TestMoveTaskCountsMoves(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if task := store.GetState().Categories[0].Tasks[2]; task.MoveCount != 0 {
+		t.Fatalf("expected a legacy task to read zero moves, got %d", task.MoveCount)
+	}
+
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationCategory, CategoryID: "cat2"}); err != nil {
+		t.Fatalf("first move: %v", err)
+	}
+	task, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationBackburner})
+	if err != nil {
+		t.Fatalf("second move: %v", err)
+	}
+	if task.MoveCount != 2 {
+		t.Fatalf("expected 2 moves, got %d", task.MoveCount)
+	}
+
+	// edits keep the count and a reload reads it back
+	name := "Renamed"
+	if task, _, err = store.UpdateTask("t3", TaskPatch{Name: &name}); err != nil || task.MoveCount != 2 {
+		t.Fatalf("expected the count kept across an edit, got %d (%v)", task.MoveCount, err)
+	}
+	if _, err := store.SyncFromFile(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got := store.GetState().Backburner[0].MoveCount; got != 2 {
+		t.Fatalf("expected 2 moves after reload, got %d", got)
+	}
+}
+
+func TestMoveTaskAttributesSourceAfterCategoryRemovals(t *testing.T) {
+	store := newTestStore(t, swapBoard)
+	if _, _, err := store.CreateTask(CreateTaskRequest{Location: LocationCategory, CategoryID: "d", Task: Task{ID: "d1", Name: "D1", State: "todo", Size: 1}}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// shift d down the active list before moving its task out
+	for _, id := range []string{"a", "b"} {
+		if _, _, err := store.MoveCategory(id, MoveCategoryRequest{Location: LocationBackburner}); err != nil {
+			t.Fatalf("move category %s: %v", id, err)
+		}
+	}
+
+	moved, board, err := store.MoveTask("d1", MoveTaskRequest{Location: LocationArchive})
+	if err != nil {
+		t.Fatalf("move to archive: %v", err)
+	}
+	if moved.SourceID != "d" {
+		t.Fatalf("expected returned task to carry source d, got %q", moved.SourceID)
+	}
+	list, idx, _ := locateTaskInPool(&board, PoolArchive, "d1")
+	if list == nil {
+		t.Fatalf("expected d1 archived")
+	}
+	if got := (*list)[idx]; got.SourceID != "d" || got.Source != "D" {
+		t.Fatalf("expected source d/D, got %s/%s", got.SourceID, got.Source)
+	}
+}
+
+func TestArchiveTaskWithNote(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	task, _, err := store.MoveTask("t1", MoveTaskRequest{Location: LocationArchive, ArchiveNote: "superseded by t2"})
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if task.Notes != "superseded by t2" {
+		t.Fatalf("expected archive note recorded, got %q", task.Notes)
+	}
+
+	// the note only applies to archive moves
+	task, _, err = store.MoveTask("t2", MoveTaskRequest{Location: LocationBackburner, ArchiveNote: "ignored"})
+	if err != nil {
+		t.Fatalf("backburner: %v", err)
+	}
+	if task.Notes != "" {
+		t.Fatalf("expected note ignored for backburner, got %q", task.Notes)
+	}
+}
+
+func TestArchiveNoteRespectsNotesLimit(t *testing.T) {
+	store := newTestStore(t, bulkBoard, WithNotesLimit(5, false))
+
+	if _, _, err := store.MoveTask("t1", MoveTaskRequest{Location: LocationArchive, ArchiveNote: "far too long"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if got := store.GetState().Categories[0].Tasks[0].ID; got != "t1" {
+		t.Fatalf("expected t1 restored to its category, got %s first", got)
+	}
+}
+
+const rollbackBoard = `{
+	"categories": [
+		{"id":"src","name":"Source","tasks":[
+			{"id":"s1","name":"S1","description":"","notes":"","state":"todo","size":1},
+			{"id":"s2","name":"S2","description":"","notes":"","state":"doing","size":2,"urgent":true},
+			{"id":"s3","name":"S3","description":"","notes":"","state":"todo","size":1}
+		]},
+		{"id":"full","name":"Full","tasks":[
+			{"id":"f1","name":"F1","description":"","notes":"","state":"todo","size":4,"urgent":true},
+			{"id":"f2","name":"F2","description":"","notes":"","state":"todo","size":1}
+		]}
+	],
+	"backburner": [
+		{"id":"b1","name":"B1","description":"","notes":"","state":"todo","size":1},
+		{"id":"b2","name":"B2","description":"","notes":"","state":"todo","size":2},
+		{"id":"b3","name":"B3","description":"","notes":"","state":"todo","size":1}
+	],
+	"archives": [
+		{"id":"a1","name":"A1","description":"","notes":"","state":"done","size":1},
+		{"id":"a2","name":"A2","description":"","notes":"","state":"done","size":3},
+		{"id":"a3","name":"A3","description":"","notes":"","state":"done","size":1}
+	],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func boardJSON(t *testing.T, store *Store) string {
+	t.Helper()
+	data, err := json.Marshal(store.GetState())
+	if err != nil {
+		t.Fatalf("marshal board: %v", err)
+	}
+	return string(data)
+}
+
+func TestFailedMoveLeavesBoardUnchanged(t *testing.T) {
+	// each task is in the middle of its list so a bad reinsert shows up as
+	// a duplicate, a gap or a reordering
+	for _, id := range []string{"s2", "b2", "a2"} {
+		t.Run(id, func(t *testing.T) {
+			store := newTestStore(t, rollbackBoard)
+			before := boardJSON(t, store)
+
+			_, _, err := store.MoveTask(id, MoveTaskRequest{Location: LocationCategory, CategoryID: "full"})
+			if !errors.Is(err, ErrCapacityExceeded) {
+				t.Fatalf("expected ErrCapacityExceeded, got %v", err)
+			}
+			if after := boardJSON(t, store); after != before {
+				t.Fatalf("board changed by failed move\nbefore: %s\nafter:  %s", before, after)
+			}
+			// the persisted file must not have been touched either
+			reopened, err := NewStore(store.path)
+			if err != nil {
+				t.Fatalf("reopen: %v", err)
+			}
+			if after := boardJSON(t, reopened); after != before {
+				t.Fatalf("data file changed by failed move")
+			}
+		})
+	}
+}
+
+func TestFailedMoveWithinLockedCategoryLeavesBoardUnchanged(t *testing.T) {
+	store := newTestStore(t, rollbackBoard)
+	if _, _, err := store.LockCategory("full", true); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	before := boardJSON(t, store)
+	if _, _, err := store.MoveTask("b2", MoveTaskRequest{Location: LocationCategory, CategoryID: "full", Force: true}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if after := boardJSON(t, store); after != before {
+		t.Fatalf("board changed by failed move\nbefore: %s\nafter:  %s", before, after)
+	}
+}
+
+func TestInsertAtDoesNotClobberSharedBacking(t *testing.T) {
+	backing := make([]int, 3, 8)
+	copy(backing, []int{1, 2, 3})
+	alias := backing[:3]
+	got := insertAt(backing[:2], 1, 9)
+	if len(got) != 3 || got[0] != 1 || got[1] != 9 || got[2] != 2 {
+		t.Fatalf("unexpected insert result %v", got)
+	}
+	if alias[2] != 3 {
+		t.Fatalf("expected shared backing array untouched, got %v", alias)
+	}
+	if got := insertAt([]int{1}, 5, 2); len(got) != 2 || got[1] != 2 {
+		t.Fatalf("expected out-of-range index to append, got %v", got)
+	}
+}
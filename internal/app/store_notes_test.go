@@ -0,0 +1,101 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNotesLimitRejectsByDefault(t *testing.T) {
+	store := newTestStore(t, bulkBoard, WithNotesLimit(5, false))
+
+	long := "ééééééé"
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Notes: &long}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	_, _, err := store.CreateTask(CreateTaskRequest{
+		CategoryID: "cat2",
+		Task:       Task{Name: "New", State: "todo", Size: 1, Notes: long},
+	})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest on create, got %v", err)
+	}
+
+	// five two-byte runes fit even though they are ten bytes
+	fits := "ééééé"
+	task, _, err := store.UpdateTask("t1", TaskPatch{Notes: &fits})
+	if err != nil {
+		t.Fatalf("update within limit: %v", err)
+	}
+	if task.Notes != fits {
+		t.Fatalf("expected notes unchanged, got %q", task.Notes)
+	}
+}
+
+func TestNotesLimitTruncates(t *testing.T) {
+	store := newTestStore(t, bulkBoard, WithNotesLimit(5, true))
+
+	long := strings.Repeat("é", 20)
+	task, _, err := store.UpdateTask("t1", TaskPatch{Notes: &long})
+	if err != nil {
+		t.Fatalf("update task: %v", err)
+	}
+	if task.Notes != "éééé…" {
+		t.Fatalf("expected truncated notes, got %q", task.Notes)
+	}
+	if n := utf8.RuneCountInString(task.Notes); n != 5 {
+		t.Fatalf("expected 5 runes, got %d", n)
+	}
+
+	created, _, err := store.CreateTask(CreateTaskRequest{
+		CategoryID: "cat2",
+		Task:       Task{Name: "New", State: "todo", Size: 1, Notes: "abcdefgh"},
+	})
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if created.Notes != "abcd…" {
+		t.Fatalf("expected truncated notes on create, got %q", created.Notes)
+	}
+}
+
+func TestNotesLimitCoversImports(t *testing.T) {
+	incoming := `{
+		"categories": [
+			{"id":"cat9","name":"Gamma","tasks":[
+				{"id":"g1","name":"G1","notes":"far too long","state":"todo","size":1}
+			]}
+		]
+	}`
+
+	store := newTestStore(t, bulkBoard, WithNotesLimit(5, false))
+	if _, _, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest on merge, got %v", err)
+	}
+	if _, _, err := store.Import(ImportRequest{Mode: ImportModeReplace, Board: decodeBoard(t, incoming)}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest on replace, got %v", err)
+	}
+	if _, err := store.MergeBoardFromFile(writeMergeSource(t, incoming)); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest on file merge, got %v", err)
+	}
+	if got := len(store.GetState().Categories); got != 2 {
+		t.Fatalf("expected rejected merges to leave the board alone, got %d categories", got)
+	}
+
+	store = newTestStore(t, bulkBoard, WithNotesLimit(5, true))
+	_, board, err := store.Import(ImportRequest{Mode: ImportModeMerge, Board: decodeBoard(t, incoming)})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if notes := board.Categories[2].Tasks[0].Notes; notes != "far …" {
+		t.Fatalf("expected merged notes truncated, got %q", notes)
+	}
+	_, board, err = store.Import(ImportRequest{Mode: ImportModeReplace, Board: decodeBoard(t, incoming)})
+	if err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if notes := board.Categories[0].Tasks[0].Notes; notes != "far …" {
+		t.Fatalf("expected replaced notes truncated, got %q", notes)
+	}
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenSwapsServedBoard(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	other := filepath.Join(store.DataDir(), "other.json")
+	if err := os.WriteFile(other, []byte(bulkBoard), 0o644); err != nil {
+		t.Fatalf("write other board: %v", err)
+	}
+	events, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+	before := store.GetState().Revision
+
+	board, err := store.Open("other.json")
+	if err != nil {
+		t.Fatalf("open board: %v", err)
+	}
+	select {
+	case event := <-events:
+		if event.Revision <= before || event.Revision != board.Revision {
+			t.Fatalf("expected an event past revision %d for the opened board, got %d", before, event.Revision)
+		}
+	default:
+		t.Fatalf("expected subscribers told about the opened board")
+	}
+	if board.Categories[0].ID != "cat1" {
+		t.Fatalf("expected opened board to be served, got %q", board.Categories[0].ID)
+	}
+	if store.Path() != other {
+		t.Fatalf("expected path %s, got %s", other, store.Path())
+	}
+
+	name := "Renamed"
+	if _, _, err := store.UpdateTask("t1", TaskPatch{Name: &name}); err != nil {
+		t.Fatalf("update after open: %v", err)
+	}
+	reloaded, err := NewStore(other)
+	if err != nil {
+		t.Fatalf("reload other board: %v", err)
+	}
+	if reloaded.GetState().Categories[0].Tasks[0].Name != "Renamed" {
+		t.Fatalf("expected writes to go to the newly opened file")
+	}
+}
+
+func TestOpenInvalidBoardKeepsCurrent(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	original := store.Path()
+	bad := filepath.Join(store.DataDir(), "bad.json")
+	invalid := `{"categories":[{"id":"c","name":"C","tasks":[{"id":"t","name":"T","state":"sleeping","size":1}]}]}`
+	if err := os.WriteFile(bad, []byte(invalid), 0o644); err != nil {
+		t.Fatalf("write bad board: %v", err)
+	}
+
+	if _, err := store.Open(bad); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState, got %v", err)
+	}
+	if _, err := store.Open("missing.json"); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest for missing file, got %v", err)
+	}
+	outside := filepath.Join(t.TempDir(), "outside.json")
+	if err := os.WriteFile(outside, []byte(bulkBoard), 0o644); err != nil {
+		t.Fatalf("write outside board: %v", err)
+	}
+	if _, err := store.Open(outside); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a file outside the data dir refused, got %v", err)
+	}
+	if store.Path() != original {
+		t.Fatalf("expected original path to remain in service")
+	}
+	if store.GetState().Categories[0].ID != "active" {
+		t.Fatalf("expected original board to remain in service")
+	}
+}
+
+func TestOpenEndpointRequiresFileAdmin(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	if err := os.WriteFile(filepath.Join(store.DataDir(), "other.json"), []byte(bulkBoard), 0o644); err != nil {
+		t.Fatalf("write other board: %v", err)
+	}
+	rec := doRequest(t, NewServer(store), http.MethodPost, "/admin/open", `{"path":"other.json"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without -allow-file-admin, got %d", rec.Code)
+	}
+	rec = doRequest(t, NewServer(store, WithFileAdmin()), http.MethodPost, "/admin/open", `{"path":"other.json"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if store.GetState().Categories[0].ID != "cat1" {
+		t.Fatalf("expected the opened board served")
+	}
+}
+
+func TestSyncEndpointRequiresFileAdmin(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	if err := os.WriteFile(store.path, []byte(bulkBoard), 0o644); err != nil {
+		t.Fatalf("write board: %v", err)
+	}
+	rec := doRequest(t, NewServer(store), http.MethodPost, "/api/board/sync", "")
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without -allow-file-admin, got %d", rec.Code)
+	}
+	if store.GetState().Categories[0].ID != "active" {
+		t.Fatalf("expected the served board kept")
+	}
+	rec = doRequest(t, NewServer(store, WithFileAdmin()), http.MethodPost, "/api/board/sync", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if store.GetState().Categories[0].ID != "cat1" {
+		t.Fatalf("expected the board reloaded from disk")
+	}
+}
+
+func TestSyncFromFile(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	events, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+	before := store.GetState().Revision
+
+	edited := strings.Replace(bulkBoard, `"name":"Alpha"`, `"name":"Edited"`, 1)
+	edited = strings.Replace(edited, `"name":"Four",`, `"name":"Fourth",`, 1)
+	edited = strings.Replace(edited, `"urgent":true}`, `"urgent":true},{"id":"t5","name":"Five","state":"todo","size":1}`, 1)
+	if err := os.WriteFile(store.Path(), []byte(edited), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	diff, err := store.SyncFromFile()
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got := store.GetState().Categories[0].Name; got != "Edited" {
+		t.Fatalf("expected reloaded name Edited, got %q", got)
+	}
+	// renaming a category moves none of its tasks, so only t4 and t5 show
+	if strings.Join(diff.Added, ",") != "t5" || strings.Join(diff.Changed, ",") != "t4" || len(diff.Removed) != 0 {
+		t.Fatalf("expected t5 added and t4 changed, got %+v", diff)
+	}
+	select {
+	case event := <-events:
+		if event.Revision <= before || event.Revision != diff.Revision {
+			t.Fatalf("expected an event past revision %d, got %d (diff %d)", before, event.Revision, diff.Revision)
+		}
+	default:
+		t.Fatalf("expected subscribers told about the reload")
+	}
+
+	for name, data := range map[string]string{"undecodable": `{"categories":`, "empty": "  \n"} {
+		if err := os.WriteFile(store.Path(), []byte(data), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := store.SyncFromFile(); !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("%s: expected ErrInvalidRequest, got %v", name, err)
+		}
+	}
+	if err := os.Remove(store.Path()); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := store.SyncFromFile(); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected a missing file to be an error, got %v", err)
+	}
+	if _, err := os.Stat(store.Path()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected a failed sync not to seed a new file")
+	}
+	if got := store.GetState().Categories[0].Name; got != "Edited" {
+		t.Fatalf("expected board kept after failed sync, got %q", got)
+	}
+}
+
+func TestMaxFileSizeRefusesLargeWrites(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	before, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatalf("read board: %v", err)
+	}
+	encoded, err := encodeBoardFile(store.state)
+	if err != nil {
+		t.Fatalf("encode board: %v", err)
+	}
+	capped, err := NewStore(store.path, WithMaxFileSize(int64(len(encoded))+1000))
+	if err != nil {
+		t.Fatalf("open under the cap: %v", err)
+	}
+	revision := capped.GetState().Revision
+	memory := boardJSON(t, capped)
+
+	_, _, err = capped.CreateTask(CreateTaskRequest{
+		Location: LocationBackburner,
+		Task:     Task{Name: "Huge", State: "todo", Size: 1, Description: strings.Repeat("x", 2000)},
+	})
+	if !errors.Is(err, ErrBoardTooLarge) {
+		t.Fatalf("expected ErrBoardTooLarge, got %v", err)
+	}
+	after, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatalf("read board: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Fatalf("expected the board file untouched")
+	}
+	if got := boardJSON(t, capped); got != memory || capped.GetState().Revision != revision {
+		t.Fatalf("expected the refused write to leave the board as it was")
+	}
+	if _, _, err := capped.CreateTask(CreateTaskRequest{Location: LocationBackburner, Task: Task{Name: "Small", State: "todo", Size: 1}}); err != nil {
+		t.Fatalf("expected a small write to fit: %v", err)
+	}
+
+	rec := doRequest(t, NewServer(capped), http.MethodPost, "/api/v1/tasks", `{"location":"inbox","task":{"name":"`+strings.Repeat("y", 2000)+`","state":"todo","size":1}}`)
+	if rec.Code != http.StatusInsufficientStorage {
+		t.Fatalf("expected 507, got %d", rec.Code)
+	}
+
+	if _, err := NewStore(store.path, WithMaxFileSize(100)); !errors.Is(err, ErrBoardTooLarge) {
+		t.Fatalf("expected opening an oversized board to fail, got %v", err)
+	}
+}
+
+func TestOpenAndSyncRefuseOversizedBoards(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	encoded, err := encodeBoardFile(store.state)
+	if err != nil {
+		t.Fatalf("encode board: %v", err)
+	}
+	capped, err := NewStore(store.path, WithMaxFileSize(int64(len(encoded))+1000))
+	if err != nil {
+		t.Fatalf("open under the cap: %v", err)
+	}
+	memory := boardJSON(t, capped)
+	large := strings.Replace(bulkBoard, `"name":"One"`, `"name":"`+strings.Repeat("x", 2000)+`"`, 1)
+
+	other := filepath.Join(capped.DataDir(), "large.json")
+	if err := os.WriteFile(other, []byte(large), 0o644); err != nil {
+		t.Fatalf("write large board: %v", err)
+	}
+	if _, err := capped.Open("large.json"); !errors.Is(err, ErrBoardTooLarge) {
+		t.Fatalf("expected Open to refuse an oversized board, got %v", err)
+	}
+	if capped.Path() != store.path {
+		t.Fatalf("expected the current file kept in service")
+	}
+
+	if err := os.WriteFile(store.path, []byte(large), 0o644); err != nil {
+		t.Fatalf("write large board: %v", err)
+	}
+	if _, err := capped.SyncFromFile(); !errors.Is(err, ErrBoardTooLarge) {
+		t.Fatalf("expected SyncFromFile to refuse an oversized board, got %v", err)
+	}
+	if boardJSON(t, capped) != memory {
+		t.Fatalf("expected the served board left in place")
+	}
+}
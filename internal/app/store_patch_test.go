@@ -0,0 +1,248 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+const clearBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"full","name":"Full","description":"About it","notes":"Some notes","state":"blocked","size":1,
+				"links":[{"text":"Spec","url":"https://example.com/spec"}],
+				"checklist":[{"text":"Draft","done":true}],
+				"tags":["sprint"],"blockedReason":"waiting","blockedBy":["other"],
+				"urgent":true,"dueDate":"2025-04-01T00:00:00Z"},
+			{"id":"other","name":"Other","description":"","notes":"","state":"todo","size":1}
+		]},
+		{"id":"cat2","name":"Beta","tasks":[
+			{"id":"handed","name":"Handed","description":"","notes":"","state":"delegated","size":1,"delegatedTo":"sam"}
+		]}
+	]
+}`
+
+func TestPatchClearFields(t *testing.T) {
+	cases := []struct {
+		field   string
+		taskID  string
+		cleared func(Task) bool
+	}{
+		{"description", "full", func(task Task) bool { return task.Description == "" }},
+		{"notes", "full", func(task Task) bool { return task.Notes == "" }},
+		{"links", "full", func(task Task) bool { return task.Links == nil }},
+		{"checklist", "full", func(task Task) bool { return task.Checklist == nil }},
+		{"tags", "full", func(task Task) bool { return task.Tags == nil }},
+		{"blockedReason", "full", func(task Task) bool { return task.BlockedReason == "" && task.State == "blocked" }},
+		{"blockedBy", "full", func(task Task) bool { return task.BlockedBy == nil }},
+		{"delegatedTo", "handed", func(task Task) bool { return task.DelegatedTo == "" && task.State == "delegated" }},
+		{"urgent", "full", func(task Task) bool { return !task.Urgent }},
+		{"dueDate", "full", func(task Task) bool { return task.DueDate == nil }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.field, func(t *testing.T) {
+			store := newTestStore(t, clearBoard)
+			task, board, err := store.UpdateTask(tc.taskID, TaskPatch{ClearFields: []string{tc.field}})
+			if err != nil {
+				t.Fatalf("clear %s: %v", tc.field, err)
+			}
+			if !tc.cleared(task) {
+				t.Fatalf("expected %s cleared, got %+v", tc.field, task)
+			}
+			// the clear goes through the urgent normalization, so clearing
+			// urgent leaves the category with none and any other clear
+			// leaves the urgent task as it was
+			stored := board.Categories[0].Tasks[0]
+			if stored.Urgent != (tc.field != "urgent") {
+				t.Fatalf("expected stored urgent=%v after clearing %s, got %+v", tc.field != "urgent", tc.field, stored)
+			}
+		})
+	}
+}
+
+func TestPatchClearFieldsRunsAfterSets(t *testing.T) {
+	store := newTestStore(t, clearBoard)
+	notes := "replaced"
+	task, _, err := store.UpdateTask("full", TaskPatch{Notes: &notes, ClearFields: []string{"notes", "checklist"}})
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if task.Notes != "" {
+		t.Fatalf("expected the clear to win over the set, got %q", task.Notes)
+	}
+
+	// a cleared checklist is gone from the stored task, not left as []
+	data, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatalf("read board: %v", err)
+	}
+	if strings.Contains(string(data), `"checklist"`) {
+		t.Fatalf("expected no checklist key in the stored board, got %s", data)
+	}
+
+	before := boardJSON(t, store)
+	for _, field := range []string{"name", "state", "size", "bogus"} {
+		if _, _, err := store.UpdateTask("full", TaskPatch{ClearFields: []string{field}}); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("clear %s: expected ErrInvalidRequest, got %v", field, err)
+		}
+	}
+	if after := boardJSON(t, store); after != before {
+		t.Fatalf("expected rejected clears to leave the board unchanged")
+	}
+}
+
+func TestPatchClearFieldsEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, clearBoard))
+	rec := doRequest(t, srv, http.MethodPatch, "/api/tasks/full", `{"clearFields":["links","dueDate","urgent"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task map[string]any `json:"task"`
+	}
+	decodeBody(t, rec, &resp)
+	for _, key := range []string{"links", "dueDate", "urgent"} {
+		if _, ok := resp.Task[key]; ok {
+			t.Errorf("expected %s dropped from the task, got %v", key, resp.Task[key])
+		}
+	}
+
+	rec = doRequest(t, srv, http.MethodPatch, "/api/tasks/full", `{"clearFields":["name"]}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unclearable field, got %d", rec.Code)
+	}
+}
+
+func TestReplaceTask(t *testing.T) {
+	store := newTestStore(t, clearBoard)
+	before := store.GetState().Categories[0].Tasks[0]
+
+	update, board, err := store.ReplaceTask("full", Task{
+		Name:  "Full again",
+		State: "doing",
+		Size:  2,
+		Tags:  []string{"sync"},
+		// server-owned fields in the body are ignored
+		Source:    "elsewhere",
+		MoveCount: 9,
+	}, "sync-bot")
+	if err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	task := update.Task
+	if task.Name != "Full again" || task.State != "doing" || task.Size != 2 || len(task.Tags) != 1 || task.Tags[0] != "sync" {
+		t.Fatalf("expected the set fields replaced, got %+v", task)
+	}
+	if task.Description != "" || task.Notes != "" || task.Links != nil || task.Checklist != nil ||
+		task.BlockedReason != "" || task.BlockedBy != nil || task.Urgent || task.DueDate != nil {
+		t.Fatalf("expected fields missing from the body cleared, got %+v", task)
+	}
+	if task.Source != before.Source || task.MoveCount != before.MoveCount || !task.CreatedAt.Equal(before.CreatedAt) {
+		t.Fatalf("expected server-owned fields kept, got %+v", task)
+	}
+	if update.PreviousState != "blocked" {
+		t.Fatalf("expected previous state blocked, got %q", update.PreviousState)
+	}
+	if last := task.History[len(task.History)-1]; last.Actor != "sync-bot" {
+		t.Fatalf("expected the change recorded for sync-bot, got %+v", last)
+	}
+
+	// marking another task urgent through a replace unmarks the rest
+	if _, board, err = store.ReplaceTask("other", Task{ID: "other", Name: "Other", State: "todo", Size: 1, Urgent: true}, ""); err != nil {
+		t.Fatalf("replace urgent: %v", err)
+	}
+	for _, other := range board.Categories[0].Tasks {
+		if other.Urgent != (other.ID == "other") {
+			t.Fatalf("expected only other urgent, got %s urgent=%v", other.ID, other.Urgent)
+		}
+	}
+
+	cases := []struct {
+		name string
+		id   string
+		task Task
+		want error
+	}{
+		{"mismatched id", "other", Task{ID: "full", Name: "Other", State: "todo", Size: 1}, ErrInvalidRequest},
+		{"no size", "other", Task{Name: "Other", State: "todo"}, ErrInvalidTaskSize},
+		{"blocked without reason", "other", Task{Name: "Other", State: "blocked", Size: 1}, ErrInvalidRequest},
+		{"over capacity", "other", Task{Name: "Other", State: "todo", Size: 5}, ErrCapacityExceeded},
+		{"missing", "nope", Task{Name: "Nope", State: "todo", Size: 1}, ErrTaskNotFound},
+	}
+	snapshot := boardJSON(t, store)
+	for _, tc := range cases {
+		if _, _, err := store.ReplaceTask(tc.id, tc.task, ""); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+	if boardJSON(t, store) != snapshot {
+		t.Fatalf("expected rejected replaces to leave the board unchanged")
+	}
+}
+
+func TestReplaceTaskEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, clearBoard))
+	rec := doRequest(t, srv, http.MethodPut, "/api/tasks/other", `{"id":"other","name":"Put","state":"doing","size":1,"notes":"synced"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task          Task   `json:"task"`
+		PreviousState string `json:"previousState"`
+	}
+	decodeBody(t, rec, &resp)
+	if resp.Task.Name != "Put" || resp.Task.Notes != "synced" || resp.PreviousState != "todo" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	rec = doRequest(t, srv, http.MethodPut, "/api/tasks/other", `{"id":"full","name":"Put","state":"doing","size":1}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a mismatched id, got %d", rec.Code)
+	}
+}
+
+func TestPatchExpectedValues(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	before := boardJSON(t, store)
+
+	todo, doing, done := "todo", "doing", "done"
+	_, _, err := store.UpdateTask("t1", TaskPatch{State: &done, ExpectedState: &todo})
+	var conflict *PatchConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrPatchConflict) {
+		t.Fatalf("expected a PatchConflictError, got %v", err)
+	}
+	if conflict.Field != "state" || conflict.Expected != "todo" || conflict.Actual != "doing" {
+		t.Fatalf("unexpected conflict %+v", conflict)
+	}
+	if after := boardJSON(t, store); after != before {
+		t.Fatalf("expected a conflicting patch to leave the board unchanged")
+	}
+
+	size, urgent, name := 1, false, "One"
+	task, _, err := store.UpdateTask("t1", TaskPatch{State: &done, ExpectedState: &doing, ExpectedSize: &size, ExpectedUrgent: &urgent, ExpectedName: &name})
+	if err != nil {
+		t.Fatalf("patch with matching expectations: %v", err)
+	}
+	if task.State != "done" {
+		t.Fatalf("expected t1 done, got %s", task.State)
+	}
+}
+
+func TestPatchExpectedValuesEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, bulkBoard))
+	rec := doRequest(t, srv, http.MethodPatch, "/api/tasks/t4", `{"urgent":false,"expectedUrgent":false}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Field    string `json:"field"`
+		Expected bool   `json:"expected"`
+		Actual   bool   `json:"actual"`
+	}
+	decodeBody(t, rec, &body)
+	if body.Field != "urgent" || body.Expected || !body.Actual {
+		t.Fatalf("unexpected conflict body %+v", body)
+	}
+}
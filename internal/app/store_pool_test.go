@@ -0,0 +1,258 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T, initial string, opts ...StoreOption) *Store {
+	t.Helper()
+	dataPath := filepath.Join(t.TempDir(), "board.json")
+	if err := os.WriteFile(dataPath, []byte(initial), 0o644); err != nil {
+		t.Fatalf("write data: %v", err)
+	}
+	store, err := NewStore(dataPath, opts...)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	return store
+}
+
+const poolBoard = `{
+	"categories": [
+		{"id":"active","name":"Active","tasks":[
+			{"id":"a1","name":"A1","description":"","notes":"","state":"todo","size":1,"urgent":true}
+		]}
+	],
+	"backburner": [
+		{"id":"b1","name":"B1","description":"","notes":"","state":"todo","size":1,"sourceId":"active","source":"Active"},
+		{"id":"b2","name":"B2","description":"","notes":"","state":"todo","size":1,"sourceId":"cb","source":"Shelved"},
+		{"id":"b3","name":"B3","description":"","notes":"","state":"todo","size":1,"sourceId":"ca","source":"Retired"}
+	],
+	"archives": [
+		{"id":"r1","name":"R1","description":"","notes":"","state":"done","size":1,"sourceId":"active","source":"Active"},
+		{"id":"r2","name":"R2","description":"","notes":"","state":"done","size":1,"sourceId":"cb","source":"Shelved"},
+		{"id":"r3","name":"R3","description":"","notes":"","state":"done","size":1,"sourceId":"ca","source":"Retired"}
+	],
+	"categoryBackburner": [
+		{"id":"cb","name":"Shelved","tasks":[
+			{"id":"cb1","name":"CB1","description":"","notes":"","state":"todo","size":1}
+		]}
+	],
+	"categoryArchives": [
+		{"id":"ca","name":"Retired","tasks":[
+			{"id":"ca1","name":"CA1","description":"","notes":"","state":"todo","size":1}
+		]}
+	]
+}`
+
+func TestMoveTaskBetweenPools(t *testing.T) {
+	cases := []struct {
+		task       string
+		from, to   string
+		wantSource string
+	}{
+		{"a1", PoolActive, PoolBackburner, "active"},
+		{"a1", PoolActive, PoolArchive, "active"},
+		{"b1", PoolBackburner, PoolActive, ""},
+		{"b1", PoolBackburner, PoolArchive, "active"},
+		{"b2", PoolBackburner, PoolCategoryBackburner, ""},
+		{"b3", PoolBackburner, PoolCategoryArchive, ""},
+		{"r1", PoolArchive, PoolActive, ""},
+		{"r1", PoolArchive, PoolBackburner, "active"},
+		{"r2", PoolArchive, PoolCategoryBackburner, ""},
+		{"r3", PoolArchive, PoolCategoryArchive, ""},
+		{"cb1", PoolCategoryBackburner, PoolBackburner, "cb"},
+		{"cb1", PoolCategoryBackburner, PoolArchive, "cb"},
+		{"ca1", PoolCategoryArchive, PoolBackburner, "ca"},
+		{"ca1", PoolCategoryArchive, PoolArchive, "ca"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.from+"->"+tc.to, func(t *testing.T) {
+			store := newTestStore(t, poolBoard)
+			task, board, err := store.MoveTaskBetweenPools(tc.task, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("move task: %v", err)
+			}
+			if task.ID != tc.task {
+				t.Fatalf("expected task %s, got %q", tc.task, task.ID)
+			}
+			if task.SourceID != tc.wantSource {
+				t.Fatalf("expected source %q, got %q", tc.wantSource, task.SourceID)
+			}
+			if tc.to != PoolActive && task.Urgent {
+				t.Fatalf("expected urgent flag cleared outside active pool")
+			}
+			if list, _, _ := locateTaskInPool(&board, tc.from, tc.task); list != nil {
+				t.Fatalf("expected task removed from %s", tc.from)
+			}
+			if list, _, _ := locateTaskInPool(&board, tc.to, tc.task); list == nil {
+				t.Fatalf("expected task placed in %s", tc.to)
+			}
+		})
+	}
+}
+
+func TestMoveTaskBetweenPoolsAllPairs(t *testing.T) {
+	board := strings.Replace(poolBoard, `"categoryBackburner": [`,
+		`"inbox": [{"id":"i1","name":"I1","description":"","notes":"","state":"todo","size":1}],
+	"categoryBackburner": [`, 1)
+	// one task per pool; b2 came from Shelved and r1 from the active category
+	taskIn := map[string]string{
+		PoolActive:             "a1",
+		PoolBackburner:         "b2",
+		PoolArchive:            "r1",
+		PoolCategoryBackburner: "cb1",
+		PoolCategoryArchive:    "ca1",
+		PoolInbox:              "i1",
+	}
+	// category-backed destinations take a task only into its own category,
+	// and a missing entry means the move has no category to go to
+	categoryFor := map[string]map[string]string{
+		PoolActive:             {"r1": "Active"},
+		PoolCategoryBackburner: {"b2": "Shelved"},
+		PoolCategoryArchive:    {},
+	}
+	for _, from := range taskPools {
+		for _, to := range taskPools {
+			if from == to {
+				continue
+			}
+			id := taskIn[from]
+			t.Run(from+"->"+to, func(t *testing.T) {
+				store := newTestStore(t, board)
+				before := boardJSON(t, store)
+				_, moved, err := store.MoveTaskBetweenPools(id, from, to)
+				want, categoryBacked := categoryFor[to]
+				if categoryBacked && want[id] == "" {
+					if !errors.Is(err, ErrCategoryNotFound) {
+						t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+					}
+					if boardJSON(t, store) != before {
+						t.Fatalf("expected the failed move to leave the board as it was")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("move %s: %v", id, err)
+				}
+				if list, _, _ := locateTaskInPool(&moved, from, id); list != nil {
+					t.Fatalf("expected %s removed from %s", id, from)
+				}
+				list, _, owner := locateTaskInPool(&moved, to, id)
+				if list == nil {
+					t.Fatalf("expected %s placed in %s", id, to)
+				}
+				if categoryBacked && owner.Name != want[id] {
+					t.Fatalf("expected %s in %q, got %q", id, want[id], owner.Name)
+				}
+			})
+		}
+	}
+}
+
+func TestMoveTaskBetweenPoolsRecordsEvent(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	task, board, err := store.MoveTaskBetweenPools("b2", PoolBackburner, PoolCategoryBackburner)
+	if err != nil {
+		t.Fatalf("move b2: %v", err)
+	}
+	want := AuditEvent{Actor: DefaultActor, TaskID: "b2", Action: "move", From: PoolBackburner, To: PoolCategoryBackburner}
+	if len(task.History) != 1 {
+		t.Fatalf("expected one history entry, got %+v", task.History)
+	}
+	got := task.History[0]
+	got.At = time.Time{}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if n := len(board.AuditLog); n != 1 || board.AuditLog[0].TaskID != "b2" {
+		t.Fatalf("expected the move in the audit log, got %+v", board.AuditLog)
+	}
+}
+
+func TestMoveTaskBetweenPoolsRejectsWrongSource(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+
+	if _, _, err := store.MoveTaskBetweenPools("b1", PoolArchive, PoolActive); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if _, _, err := store.MoveTaskBetweenPools("cb1", PoolActive, PoolArchive); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if _, _, err := store.MoveTaskBetweenPools("a1", PoolActive, PoolActive); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest for same pool, got %v", err)
+	}
+	if _, _, err := store.MoveTaskBetweenPools("missing", PoolActive, PoolArchive); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+	if _, _, err := store.MoveTaskBetweenPools("a1", "elsewhere", PoolArchive); !errors.Is(err, ErrInvalidLocation) {
+		t.Fatalf("expected ErrInvalidLocation, got %v", err)
+	}
+}
+
+func TestMoveTaskBetweenPoolsRestoresOnFailure(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+
+	if _, _, err := store.MoveTaskBetweenPools("cb1", PoolCategoryBackburner, PoolActive); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+	board := store.GetState()
+	if len(board.CategoryBackburner[0].Tasks) != 1 || board.CategoryBackburner[0].Tasks[0].ID != "cb1" {
+		t.Fatalf("expected task to remain in its category after failed move")
+	}
+}
+
+func TestGetTaskAtPosition(t *testing.T) {
+	store := newTestStore(t, poolBoard)
+	for _, tc := range []struct {
+		category string
+		position int
+		want     string
+		wantErr  error
+	}{
+		{"active", 0, "a1", nil},
+		{"cb", 0, "cb1", nil},
+		{"ca", 0, "ca1", nil},
+		{"active", 1, "", ErrInvalidRequest},
+		{"cb", -1, "", ErrInvalidRequest},
+		{"ca", 5, "", ErrInvalidRequest},
+		{"missing", 0, "", ErrCategoryNotFound},
+	} {
+		task, err := store.GetTaskAtPosition(tc.category, tc.position)
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("%s[%d]: expected %v, got %v", tc.category, tc.position, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || task.ID != tc.want {
+			t.Fatalf("%s[%d]: expected %s, got %q (%v)", tc.category, tc.position, tc.want, task.ID, err)
+		}
+	}
+}
+
+func TestTaskAtPositionEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, poolBoard))
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	decodeBody(t, doRequest(t, srv, http.MethodGet, "/api/v1/categories/cb/tasks/0", ""), &resp)
+	if resp.Task.ID != "cb1" {
+		t.Fatalf("expected cb1, got %q", resp.Task.ID)
+	}
+	for target, want := range map[string]int{
+		"/api/v1/categories/cb/tasks/1":     http.StatusBadRequest,
+		"/api/v1/categories/cb/tasks/first": http.StatusBadRequest,
+		"/api/v1/categories/nope/tasks/0":   http.StatusNotFound,
+	} {
+		if rec := doRequest(t, srv, http.MethodGet, target, ""); rec.Code != want {
+			t.Fatalf("%s: expected %d, got %d", target, want, rec.Code)
+		}
+	}
+}
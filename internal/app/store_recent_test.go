@@ -0,0 +1,96 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const recentBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"old","name":"Old","description":"","notes":"","state":"todo","size":1,
+				"createdAt":"2024-01-01T09:00:00Z","updatedAt":"2024-01-01T09:00:00Z"},
+			{"id":"tieOlder","name":"Tie older","description":"","notes":"","state":"todo","size":1,
+				"createdAt":"2024-01-02T09:00:00Z","updatedAt":"2024-01-05T09:00:00Z"}
+		]}
+	],
+	"backburner": [
+		{"id":"tieNewer","name":"Tie newer","description":"","notes":"","state":"todo","size":1,
+			"createdAt":"2024-01-03T09:00:00Z","updatedAt":"2024-01-05T09:00:00Z"}
+	],
+	"archives": [
+		{"id":"newest","name":"Newest","description":"","notes":"","state":"done","size":1,
+			"createdAt":"2024-01-01T09:00:00Z","updatedAt":"2024-01-09T09:00:00Z"}
+	],
+	"categoryBackburner": [
+		{"id":"cat2","name":"Shelved","tasks":[
+			{"id":"shelved","name":"Shelved","description":"","notes":"","state":"todo","size":1,
+				"createdAt":"2024-01-01T09:00:00Z","updatedAt":"2024-01-04T09:00:00Z"}
+		]}
+	],
+	"categoryArchives": []
+}`
+
+func TestGetRecentlyModifiedTasksOrdering(t *testing.T) {
+	store := newTestStore(t, recentBoard)
+
+	results, err := store.GetRecentlyModifiedTasks(10)
+	if err != nil {
+		t.Fatalf("recent tasks: %v", err)
+	}
+	want := []string{"newest", "tieNewer", "tieOlder", "shelved", "old"}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for i, id := range want {
+		if results[i].Task.ID != id {
+			t.Fatalf("expected %s at position %d, got %s", id, i, results[i].Task.ID)
+		}
+	}
+	if results[3].Pool != PoolCategoryBackburner || results[3].CategoryID != "cat2" {
+		t.Fatalf("expected shelved task to report its category pool, got %+v", results[3])
+	}
+}
+
+func TestGetRecentlyModifiedTasksTracksUpdates(t *testing.T) {
+	clock := func() time.Time { return time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC) }
+	store := newTestStore(t, recentBoard, WithClock(clock))
+
+	name := "Renamed"
+	if _, _, err := store.UpdateTask("old", TaskPatch{Name: &name}); err != nil {
+		t.Fatalf("update task: %v", err)
+	}
+	results, err := store.GetRecentlyModifiedTasks(2)
+	if err != nil {
+		t.Fatalf("recent tasks: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected limit of 2 results, got %d", len(results))
+	}
+	if results[0].Task.ID != "old" {
+		t.Fatalf("expected updated task first, got %s", results[0].Task.ID)
+	}
+}
+
+func TestGetRecentlyModifiedTasksLimits(t *testing.T) {
+	store := newTestStore(t, recentBoard)
+
+	for _, n := range []int{0, -1, MaxRecentTasks + 1} {
+		if _, err := store.GetRecentlyModifiedTasks(n); !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("expected ErrInvalidRequest for %d, got %v", n, err)
+		}
+	}
+	if _, err := store.GetRecentlyModifiedTasks(MaxRecentTasks); err != nil {
+		t.Fatalf("expected limit of %d to be accepted: %v", MaxRecentTasks, err)
+	}
+
+	srv := NewServer(store)
+	if rec := doRequest(t, srv, http.MethodGet, "/api/tasks/recent?limit=101", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for limit over cap, got %d", rec.Code)
+	}
+	if rec := doRequest(t, srv, http.MethodGet, "/api/tasks/recent?limit=3", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
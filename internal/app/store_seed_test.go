@@ -0,0 +1,53 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSeedFromEnv(t *testing.T) {
+	t.Setenv(SeedEnv, `{"categories":[{"id":"env","name":"From Env","tasks":[]}]}`)
+
+	store, err := NewStore(filepath.Join(t.TempDir(), "board.json"))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	board := store.GetState()
+	if len(board.Categories) != 1 || board.Categories[0].ID != "env" {
+		t.Fatalf("expected board seeded from env, got %+v", board.Categories)
+	}
+}
+
+func TestSeedFromInvalidEnvFallsBack(t *testing.T) {
+	for name, seed := range map[string]string{
+		"malformed": `{"categories":`,
+		"invalid":   `{"categories":[{"id":"x","name":"X","tasks":[{"id":"t","name":"T","state":"bogus","size":1}]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(SeedEnv, seed)
+			store, err := NewStore(filepath.Join(t.TempDir(), "board.json"))
+			if err != nil {
+				t.Fatalf("new store: %v", err)
+			}
+			want := seedBoard(time.Now())
+			if got := store.GetState(); len(got.Categories) != len(want.Categories) || got.Categories[0].Name != want.Categories[0].Name {
+				t.Fatalf("expected demo seed fallback, got %+v", got.Categories)
+			}
+		})
+	}
+}
+
+func TestSeedStampedWithStoreClock(t *testing.T) {
+	t.Setenv(SeedEnv, "")
+	store, err := NewStore(filepath.Join(t.TempDir(), "board.json"), WithClock(fixedClock("2025-03-10T13:00:00Z")))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	want := fixedClock("2025-03-10T13:00:00Z")()
+	for _, task := range store.GetState().Categories[0].Tasks {
+		if !task.CreatedAt.Equal(want) || !task.UpdatedAt.Equal(want) {
+			t.Fatalf("expected %s stamped by the store clock, got %v/%v", task.ID, task.CreatedAt, task.UpdatedAt)
+		}
+	}
+}
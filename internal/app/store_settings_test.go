@@ -0,0 +1,142 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStateTransitionsEnforced(t *testing.T) {
+	store := newTestStore(t, matrixBoard)
+	workflow := map[string][]string{
+		"todo":  {"doing", "blocked", "delegated"},
+		"doing": {"done", "blocked", "delegated", "todo"},
+	}
+	if _, err := store.UpdateSettings(BoardSettingsPatch{Transitions: &workflow}); err != nil {
+		t.Fatalf("set transitions: %v", err)
+	}
+
+	done := "done"
+	_, _, err := store.UpdateTask("a1", TaskPatch{State: &done})
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition for todo->done, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "todo -> done") || !strings.Contains(err.Error(), "doing, blocked, delegated") {
+		t.Fatalf("expected attempted and allowed transitions in error, got %q", err)
+	}
+	if _, _, err := store.UpdateTask("a3", TaskPatch{State: &done}); err != nil {
+		t.Fatalf("doing->done: %v", err)
+	}
+	// states without an entry are unrestricted
+	todo := "todo"
+	if _, _, err := store.UpdateTask("a3", TaskPatch{State: &todo}); err != nil {
+		t.Fatalf("done->todo: %v", err)
+	}
+
+	srv := NewServer(store)
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/tasks/a1", `{"state":"done"}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+
+	empty := map[string][]string{}
+	if _, err := store.UpdateSettings(BoardSettingsPatch{Transitions: &empty}); err != nil {
+		t.Fatalf("clear transitions: %v", err)
+	}
+	if _, _, err := store.UpdateTask("a1", TaskPatch{State: &done}); err != nil {
+		t.Fatalf("expected anything-goes after clearing, got %v", err)
+	}
+}
+
+func TestStateTransitionsValidated(t *testing.T) {
+	srv := NewServer(newTestStore(t, matrixBoard))
+	for _, body := range []string{
+		`{"transitions":{"todo":["finished"]}}`,
+		`{"transitions":{"someday":["todo"]}}`,
+	} {
+		if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/board/settings", body); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, rec.Code)
+		}
+	}
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/board/settings", `{"transitions":{"todo":["doing"]}}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCustomTaskStates(t *testing.T) {
+	store := newTestStore(t, matrixBoard)
+	if got := store.GetState().States; len(got) != len(DefaultStates) {
+		t.Fatalf("expected default states in board payload, got %+v", got)
+	}
+
+	states := []StateDef{
+		{ID: "todo"}, {ID: "doing"}, {ID: "review", Label: "In review", Color: "#b58900"}, {ID: "blocked"}, {ID: "done"},
+	}
+	board, err := store.UpdateSettings(BoardSettingsPatch{States: &states})
+	if err != nil {
+		t.Fatalf("set states: %v", err)
+	}
+	if len(board.States) != 5 || board.States[2].Label != "In review" {
+		t.Fatalf("expected configured states returned, got %+v", board.States)
+	}
+
+	review := "review"
+	if _, _, err := store.UpdateTask("a3", TaskPatch{State: &review}); err != nil {
+		t.Fatalf("move to custom state: %v", err)
+	}
+	delegated := "delegated"
+	if _, _, err := store.UpdateTask("a1", TaskPatch{State: &delegated, DelegatedTo: &delegated}); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected removed state rejected, got %v", err)
+	}
+	if _, _, err := store.CreateTask(CreateTaskRequest{Location: LocationBackburner, Task: Task{Name: "New", State: "delegated", DelegatedTo: "sam", Size: 1}}); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected create with removed state rejected, got %v", err)
+	}
+}
+
+func TestRemovingUsedStateRejected(t *testing.T) {
+	store := newTestStore(t, matrixBoard)
+	states := []StateDef{{ID: "todo"}, {ID: "doing"}, {ID: "done"}}
+	_, err := store.UpdateSettings(BoardSettingsPatch{States: &states})
+	if !errors.Is(err, ErrStateInUse) {
+		t.Fatalf("expected ErrStateInUse, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"blocked" is used by 1 tasks`) {
+		t.Fatalf("expected affected task count in error, got %q", err)
+	}
+	if got := store.GetState().States; len(got) != len(DefaultStates) {
+		t.Fatalf("expected states unchanged, got %+v", got)
+	}
+
+	srv := NewServer(store)
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/board/settings", `{"states":[{"id":"todo"},{"id":"doing"},{"id":"done"}]}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+	// transitions may not keep naming a state once it is gone
+	workflow := map[string][]string{"todo": {"delegated"}}
+	if _, err := store.UpdateSettings(BoardSettingsPatch{Transitions: &workflow}); err != nil {
+		t.Fatalf("set transitions: %v", err)
+	}
+	states = []StateDef{{ID: "todo"}, {ID: "doing"}, {ID: "blocked"}, {ID: "done"}}
+	if _, err := store.UpdateSettings(BoardSettingsPatch{States: &states}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected dangling transition rejected, got %v", err)
+	}
+}
+
+func TestTaskStatesValidated(t *testing.T) {
+	srv := NewServer(newTestStore(t, matrixBoard))
+	for _, body := range []string{
+		`{"states":[]}`,
+		`{"states":[{"id":"Todo"}]}`,
+		`{"states":[{"id":"in review"}]}`,
+		`{"states":[{"id":"a-very-long-state-name-indeed"}]}`,
+		`{"states":[{"id":"todo"},{"id":"todo"}]}`,
+	} {
+		if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/board/settings", body); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, rec.Code)
+		}
+	}
+	rec := doRequest(t, srv, http.MethodGet, "/api/v1/board/settings", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"states":[{"id":"todo","label":"To do"}`) {
+		t.Fatalf("expected default states in settings, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const sizeBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Work","tasks":[
+			{"id":"t1","name":"One","description":"","notes":"","state":"todo","size":3},
+			{"id":"t2","name":"Two","description":"","notes":"","state":"todo","size":1}
+		]}
+	],
+	"backburner": [
+		{"id":"b1","name":"Parked","description":"","notes":"","state":"todo","size":1}
+	],
+	"archives": [],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func TestSetTaskSize(t *testing.T) {
+	cases := []struct {
+		name    string
+		id      string
+		size    int
+		wantErr error
+	}{
+		{"reduce", "t1", 1, nil},
+		{"exact capacity", "t1", 4, nil},
+		{"over capacity", "t1", 5, ErrCapacityExceeded},
+		{"backburner", "b1", 5, nil},
+		{"too small", "t1", 0, ErrInvalidTaskSize},
+		{"too large", "b1", 6, ErrInvalidTaskSize},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newTestStore(t, sizeBoard)
+			task, _, err := store.SetTaskSize(tc.id, tc.size)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+				if store.GetState().Categories[0].Tasks[0].Size != 3 {
+					t.Fatalf("expected rejected resize not persisted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("set size: %v", err)
+			}
+			if task.Size != tc.size {
+				t.Fatalf("expected size %d, got %d", tc.size, task.Size)
+			}
+		})
+	}
+}
+
+func TestSetTaskSizeEndpoint(t *testing.T) {
+	srv := NewServer(newTestStore(t, sizeBoard))
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/tasks/t2/size", `{"size":2}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/tasks/t2/size", `{"size":3}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 over capacity, got %d", rec.Code)
+	}
+}
+
+func TestCustomSizeScale(t *testing.T) {
+	store := newTestStore(t, sizeBoard)
+	scale, capacity := []int{1, 2, 3, 5, 8}, 13
+	board, err := store.UpdateSettings(BoardSettingsPatch{SizeScale: &scale, Capacity: &capacity})
+	if err != nil {
+		t.Fatalf("set scale: %v", err)
+	}
+	if board.Capacity != 13 || len(board.SizeScale) != 5 {
+		t.Fatalf("expected configured scale in board payload, got %v/%d", board.SizeScale, board.Capacity)
+	}
+
+	if _, _, err := store.SetTaskSize("t2", 8); err != nil {
+		t.Fatalf("resize to 8: %v", err)
+	}
+	if _, _, err := store.SetTaskSize("t2", 4); !errors.Is(err, ErrInvalidTaskSize) {
+		t.Fatalf("expected 4 rejected off the scale, got %v", err)
+	}
+	// 3 + 8 + 2 = 13 fits; one more point does not
+	if _, _, err := store.CreateTask(CreateTaskRequest{CategoryID: "cat1", Task: Task{Name: "Fits", State: "todo", Size: 2}}); err != nil {
+		t.Fatalf("create within capacity: %v", err)
+	}
+	if _, _, err := store.CreateTask(CreateTaskRequest{CategoryID: "cat1", Task: Task{Name: "Over", State: "todo", Size: 1}}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("expected ErrCapacityExceeded, got %v", err)
+	}
+}
+
+func TestSizeScaleChangeRejectsStrandedSizes(t *testing.T) {
+	store := newTestStore(t, sizeBoard)
+	scale := []int{1, 2, 5, 8}
+	_, err := store.UpdateSettings(BoardSettingsPatch{SizeScale: &scale})
+	if !errors.Is(err, ErrSizeInUse) {
+		t.Fatalf("expected ErrSizeInUse, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "t1 (3)") {
+		t.Fatalf("expected offending task listed, got %q", err)
+	}
+	if got := store.GetState().SizeScale; len(got) != len(DefaultSizeScale) {
+		t.Fatalf("expected default scale kept, got %v", got)
+	}
+
+	srv := NewServer(store)
+	for body, want := range map[string]int{
+		`{"sizeScale":[1,2,5,8]}`: http.StatusConflict,
+		`{"sizeScale":[]}`:        http.StatusBadRequest,
+		`{"sizeScale":[3,2,1]}`:   http.StatusBadRequest,
+		`{"sizeScale":[0,1]}`:     http.StatusBadRequest,
+		`{"capacity":0}`:          http.StatusBadRequest,
+		`{"sizeScale":[1,3,5]}`:   http.StatusOK,
+	} {
+		if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/board/settings", body); rec.Code != want {
+			t.Fatalf("%s: expected %d, got %d: %s", body, want, rec.Code, rec.Body.String())
+		}
+	}
+}
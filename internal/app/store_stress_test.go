@@ -0,0 +1,121 @@
+package app
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// checkBoardConsistent fails when a task ID appears twice on the board or an
+// active category holds more than the board's capacity.
+func checkBoardConsistent(t *testing.T, board BoardState) {
+	t.Helper()
+	seen := map[string]bool{}
+	forEachPoolTask(&board, func(task *Task, _ bool) {
+		if seen[task.ID] {
+			t.Errorf("task %s appears more than once", task.ID)
+		}
+		seen[task.ID] = true
+	})
+	for _, cat := range board.Categories {
+		if size := categorySize(cat); size > board.ColumnLimit() {
+			t.Errorf("category %s holds %d, over the capacity of %d", cat.ID, size, board.ColumnLimit())
+		}
+	}
+}
+
+func TestConcurrentMutationsKeepBoardConsistent(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	const workers, rounds = 16, 40
+	locations := []MoveTaskRequest{
+		{Location: LocationCategory, CategoryID: "cat1"},
+		{Location: LocationCategory, CategoryID: "cat2"},
+		{Location: LocationBackburner},
+		{Location: LocationInbox},
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(w)))
+			created := []string{}
+			for i := 0; i < rounds; i++ {
+				switch rng.Intn(3) {
+				case 0:
+					to := locations[rng.Intn(len(locations))]
+					task, _, err := store.CreateTask(CreateTaskRequest{
+						Location:   to.Location,
+						CategoryID: to.CategoryID,
+						Task:       Task{Name: fmt.Sprintf("w%d-%d", w, i), State: "todo", Size: 1},
+					})
+					if err == nil {
+						created = append(created, task.ID)
+					}
+				case 1:
+					if len(created) == 0 {
+						continue
+					}
+					// failed moves are expected once categories fill up
+					store.MoveTask(created[rng.Intn(len(created))], locations[rng.Intn(len(locations))])
+				default:
+					checkBoardConsistent(t, store.GetState())
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	board := store.GetState()
+	checkBoardConsistent(t, board)
+	reopened, err := NewStore(store.path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, want := boardJSON(t, reopened), boardJSON(t, store); got != want {
+		t.Fatalf("expected the saved board to match memory\nsaved  %s\nmemory %s", got, want)
+	}
+}
+
+func TestConcurrentRequestsKeepBoardConsistent(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	srv := NewServer(store)
+	const workers, rounds = 16, 25
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				switch i % 4 {
+				case 0:
+					doRequest(t, srv, http.MethodPost, "/api/v1/tasks", fmt.Sprintf(`{"location":"category","categoryId":"cat%d","task":{"name":"w%d-%d","state":"todo","size":1}}`, w%2+1, w, i))
+				case 1:
+					doRequest(t, srv, http.MethodPost, "/api/v1/tasks/t1/move", fmt.Sprintf(`{"location":"category","categoryId":"cat%d"}`, i%2+1))
+				case 2:
+					// sorting works on the coalesced snapshot other requests share
+					if rec := doRequest(t, srv, http.MethodGet, "/api/v1/board?sortCategories=name", ""); rec.Code != http.StatusOK {
+						t.Errorf("get board: expected 200, got %d", rec.Code)
+					}
+				default:
+					if rec := doRequest(t, srv, http.MethodGet, "/api/v1/board", ""); rec.Code != http.StatusOK {
+						t.Errorf("get board: expected 200, got %d", rec.Code)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	board := store.GetState()
+	checkBoardConsistent(t, board)
+	// far more creates were sent than fit, so both categories end up full
+	for _, cat := range board.Categories {
+		if size := categorySize(cat); size != board.ColumnLimit() {
+			t.Fatalf("expected category %s to fill up, holds %d", cat.ID, size)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+const todayBoard = `{
+	"categories": [
+		{"id":"cat1","name":"Alpha","tasks":[
+			{"id":"yesterday","name":"Yesterday","description":"","notes":"","state":"todo","size":1,
+				"createdAt":"2024-03-09T23:30:00Z","updatedAt":"2024-03-09T23:30:00Z"},
+			{"id":"today","name":"Today","description":"","notes":"","state":"todo","size":1,
+				"createdAt":"2024-03-10T08:00:00Z","updatedAt":"2024-03-10T08:00:00Z"}
+		]}
+	],
+	"backburner": [
+		{"id":"lastWeek","name":"Last week","description":"","notes":"","state":"todo","size":1,
+			"createdAt":"2024-03-03T12:00:00Z","updatedAt":"2024-03-03T12:00:00Z"}
+	],
+	"archives": [
+		{"id":"tomorrow","name":"Tomorrow","description":"","notes":"","state":"done","size":1,
+			"createdAt":"2024-03-11T00:00:00Z","updatedAt":"2024-03-11T00:00:00Z"}
+	],
+	"categoryBackburner": [],
+	"categoryArchives": []
+}`
+
+func fixedClock(ts string) func() time.Time {
+	at, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		panic(err)
+	}
+	return func() time.Time { return at }
+}
+
+func taskIDs(results []SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Task.ID
+	}
+	return ids
+}
+
+func TestGetTasksCreatedTodayUTC(t *testing.T) {
+	store := newTestStore(t, todayBoard, WithClock(fixedClock("2024-03-10T15:00:00Z")), WithLocation(time.UTC))
+
+	results, err := store.GetTasksCreatedToday()
+	if err != nil {
+		t.Fatalf("tasks today: %v", err)
+	}
+	if ids := taskIDs(results); len(ids) != 1 || ids[0] != "today" {
+		t.Fatalf("expected only today's task, got %v", ids)
+	}
+}
+
+func TestGetTasksCreatedTodayTimezoneBoundary(t *testing.T) {
+	// UTC-5: 2024-03-10T02:00Z is still March 9th locally, so the task
+	// created at 23:30Z on the 9th counts as "today" while 08:00Z on the
+	// 10th (03:00 local on the 10th) does not.
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	store := newTestStore(t, todayBoard, WithClock(fixedClock("2024-03-10T02:00:00Z")), WithLocation(loc))
+
+	results, err := store.GetTasksCreatedToday()
+	if err != nil {
+		t.Fatalf("tasks today: %v", err)
+	}
+	if ids := taskIDs(results); len(ids) != 1 || ids[0] != "yesterday" {
+		t.Fatalf("expected the UTC-yesterday task in local today, got %v", ids)
+	}
+}
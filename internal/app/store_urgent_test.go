@@ -0,0 +1,65 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestSetTaskUrgentClearsOthersInCategory(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+
+	if _, _, err := store.SetTaskUrgent("t1", true); err != nil {
+		t.Fatalf("mark t1 urgent: %v", err)
+	}
+	task, board, err := store.SetTaskUrgent("t2", true)
+	if err != nil {
+		t.Fatalf("mark t2 urgent: %v", err)
+	}
+	if !task.Urgent {
+		t.Fatalf("expected returned task urgent")
+	}
+	if board.Categories[0].Tasks[0].Urgent || !board.Categories[0].Tasks[1].Urgent {
+		t.Fatalf("expected t2 to take urgency from t1, got %+v", board.Categories[0].Tasks)
+	}
+	if !board.Categories[1].Tasks[0].Urgent {
+		t.Fatalf("expected urgency in another category untouched")
+	}
+
+	_, board, err = store.SetTaskUrgent("t2", false)
+	if err != nil {
+		t.Fatalf("clear t2: %v", err)
+	}
+	for _, task := range board.Categories[0].Tasks {
+		if task.Urgent {
+			t.Fatalf("expected no urgent task in Alpha, got %s", task.ID)
+		}
+	}
+}
+
+func TestSetTaskUrgentOutsideActiveCategory(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationBackburner}); err != nil {
+		t.Fatalf("backburner: %v", err)
+	}
+	if _, _, err := store.SetTaskUrgent("t3", true); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if _, _, err := store.SetTaskUrgent("missing", true); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestSetTaskUrgentEndpoint(t *testing.T) {
+	store := newTestStore(t, bulkBoard)
+	srv := NewServer(store)
+	if rec := doRequest(t, srv, http.MethodPatch, "/api/v1/tasks/t3/urgent", `{"urgent":true}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !store.GetState().Categories[0].Tasks[2].Urgent {
+		t.Fatalf("expected t3 urgent")
+	}
+	if rec := doRequest(t, srv, http.MethodPost, "/api/v1/tasks/t3/urgent", `{"urgent":true}`); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,386 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+	// request body keyed with the endpoint's secret.
+	WebhookSignatureHeader = "X-TwentyFive-Signature"
+	// WebhookDeliveryHeader identifies a delivery. Retries reuse it so
+	// receivers can drop duplicates.
+	WebhookDeliveryHeader = "X-TwentyFive-Delivery"
+
+	// recentWebhookFailures is how many failed attempts each endpoint keeps
+	// for the status report.
+	recentWebhookFailures = 20
+)
+
+// WebhookEndpoint is a receiver of board events.
+type WebhookEndpoint struct {
+	URL    string
+	Secret string
+}
+
+// WebhookConfig tunes delivery. Zero fields take the defaults noted.
+type WebhookConfig struct {
+	// MaxAttempts per delivery before it is dropped (5).
+	MaxAttempts int
+	// BaseBackoff is the wait after the first failure, doubling on each
+	// further failure up to MaxBackoff (1s, 5m).
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// QueueSize bounds pending deliveries per endpoint; when full the oldest
+	// is dropped (100).
+	QueueSize int
+	// BreakerThreshold consecutive failed attempts open the endpoint's
+	// circuit for BreakerCooldown, pausing deliveries to it (5, 1m).
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// Timeout bounds each attempt (10s).
+	Timeout time.Duration
+	Client  *http.Client
+}
+
+func (c WebhookConfig) withDefaults() WebhookConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 5 * time.Minute
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = 100
+	}
+	if c.BreakerThreshold <= 0 {
+		c.BreakerThreshold = 5
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = time.Minute
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	if c.Client == nil {
+		c.Client = http.DefaultClient
+	}
+	return c
+}
+
+// WebhookFailure is one failed delivery attempt.
+type WebhookFailure struct {
+	DeliveryID string    `json:"deliveryId"`
+	At         time.Time `json:"at"`
+	Attempt    int       `json:"attempt"`
+	Error      string    `json:"error"`
+	// GaveUp is set on the attempt after which the delivery was dropped.
+	GaveUp bool `json:"gaveUp,omitempty"`
+}
+
+// WebhookStatus reports delivery health for one endpoint.
+type WebhookStatus struct {
+	URL                 string           `json:"url"`
+	LastSuccess         *time.Time       `json:"lastSuccess,omitempty"`
+	Pending             int              `json:"pending"`
+	Delivered           int64            `json:"delivered"`
+	Dropped             int64            `json:"dropped"`
+	ConsecutiveFailures int              `json:"consecutiveFailures"`
+	CircuitOpenUntil    *time.Time       `json:"circuitOpenUntil,omitempty"`
+	RecentFailures      []WebhookFailure `json:"recentFailures"`
+}
+
+type webhookDelivery struct {
+	id       string
+	body     []byte
+	attempts int
+	nextAt   time.Time
+}
+
+// webhookTarget is one endpoint's queue and delivery state. Deliveries go
+// out in order; a failing head delays those behind it.
+type webhookTarget struct {
+	endpoint WebhookEndpoint
+	cfg      WebhookConfig
+	wake     chan struct{}
+
+	mu          sync.Mutex
+	queue       []*webhookDelivery
+	lastSuccess time.Time
+	delivered   int64
+	dropped     int64
+	failures    int
+	openUntil   time.Time
+	recent      []WebhookFailure
+}
+
+// WebhookDispatcher delivers board events to webhook endpoints with
+// retries, backoff and a per-endpoint circuit breaker. Queues are in memory,
+// so deliveries pending at shutdown are lost.
+type WebhookDispatcher struct {
+	cfg     WebhookConfig
+	now     func() time.Time
+	targets []*webhookTarget
+}
+
+// NewWebhookDispatcher returns a dispatcher for endpoints. Call Run to start
+// delivering.
+func NewWebhookDispatcher(endpoints []WebhookEndpoint, cfg WebhookConfig) *WebhookDispatcher {
+	cfg = cfg.withDefaults()
+	d := &WebhookDispatcher{cfg: cfg, now: time.Now}
+	for _, ep := range endpoints {
+		d.targets = append(d.targets, &webhookTarget{
+			endpoint: ep,
+			cfg:      cfg,
+			wake:     make(chan struct{}, 1),
+		})
+	}
+	return d
+}
+
+// Run delivers every event published by store until ctx is done. The event
+// stream coalesces, so a burst of writes may arrive as its latest revision
+// only; receivers should treat an event as "fetch the board".
+func (d *WebhookDispatcher) Run(ctx context.Context, store *Store) {
+	events, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+
+	var wg sync.WaitGroup
+	for _, t := range d.targets {
+		wg.Add(1)
+		go func(t *webhookTarget) {
+			defer wg.Done()
+			d.deliverLoop(ctx, t)
+		}(t)
+	}
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			if err := d.Enqueue(event); err != nil {
+				logWriteError("webhook", err)
+			}
+		}
+	}
+}
+
+// Enqueue queues event for every endpoint.
+func (d *WebhookDispatcher) Enqueue(event BoardEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	for _, t := range d.targets {
+		id, err := newDeliveryID()
+		if err != nil {
+			return err
+		}
+		t.mu.Lock()
+		if len(t.queue) >= d.cfg.QueueSize {
+			t.queue = t.queue[1:]
+			t.dropped++
+		}
+		t.queue = append(t.queue, &webhookDelivery{id: id, body: body})
+		t.mu.Unlock()
+		select {
+		case t.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Status reports every endpoint in configuration order.
+func (d *WebhookDispatcher) Status() []WebhookStatus {
+	now := d.now()
+	out := make([]WebhookStatus, 0, len(d.targets))
+	for _, t := range d.targets {
+		t.mu.Lock()
+		st := WebhookStatus{
+			URL:                 t.endpoint.URL,
+			Pending:             len(t.queue),
+			Delivered:           t.delivered,
+			Dropped:             t.dropped,
+			ConsecutiveFailures: t.failures,
+			RecentFailures:      append([]WebhookFailure{}, t.recent...),
+		}
+		if !t.lastSuccess.IsZero() {
+			last := t.lastSuccess
+			st.LastSuccess = &last
+		}
+		if t.openUntil.After(now) {
+			until := t.openUntil
+			st.CircuitOpenUntil = &until
+		}
+		t.mu.Unlock()
+		out = append(out, st)
+	}
+	return out
+}
+
+func (d *WebhookDispatcher) deliverLoop(ctx context.Context, t *webhookTarget) {
+	for {
+		delivery, wait := t.next(d.now())
+		if delivery == nil || wait > 0 {
+			var timer *time.Timer
+			var fire <-chan time.Time
+			if delivery != nil {
+				timer = time.NewTimer(wait)
+				fire = timer.C
+			}
+			select {
+			case <-ctx.Done():
+			case <-t.wake:
+			case <-fire:
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		err := d.send(ctx, t.endpoint, delivery)
+		if ctx.Err() != nil {
+			return
+		}
+		t.record(delivery, err, d.now())
+	}
+}
+
+// next returns the head delivery and how long to wait before sending it,
+// or nil when the queue is empty.
+func (t *webhookTarget) next(now time.Time) (*webhookDelivery, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.queue) == 0 {
+		return nil, 0
+	}
+	ready := t.queue[0].nextAt
+	if t.openUntil.After(ready) {
+		ready = t.openUntil
+	}
+	return t.queue[0], ready.Sub(now)
+}
+
+// record settles an attempt: success pops the delivery and closes the
+// circuit, failure schedules a retry or drops the delivery once it is out
+// of attempts.
+func (t *webhookTarget) record(delivery *webhookDelivery, err error, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delivery.attempts++
+	if err == nil {
+		t.pop(delivery)
+		t.lastSuccess = now
+		t.delivered++
+		t.failures = 0
+		t.openUntil = time.Time{}
+		return
+	}
+
+	failure := WebhookFailure{DeliveryID: delivery.id, At: now, Attempt: delivery.attempts, Error: err.Error()}
+	if delivery.attempts >= t.cfg.MaxAttempts {
+		failure.GaveUp = true
+		t.pop(delivery)
+		t.dropped++
+	} else {
+		delivery.nextAt = now.Add(backoff(t.cfg, delivery.attempts))
+	}
+	t.recent = append(t.recent, failure)
+	if len(t.recent) > recentWebhookFailures {
+		t.recent = t.recent[len(t.recent)-recentWebhookFailures:]
+	}
+	t.failures++
+	// a failure after the cooldown (the half-open probe) reopens at once
+	if t.failures >= t.cfg.BreakerThreshold {
+		t.openUntil = now.Add(t.cfg.BreakerCooldown)
+	}
+}
+
+func (t *webhookTarget) pop(delivery *webhookDelivery) {
+	// the queue may have shed its head while the attempt was in flight
+	if len(t.queue) > 0 && t.queue[0] == delivery {
+		t.queue = t.queue[1:]
+	}
+}
+
+func backoff(cfg WebhookConfig, attempts int) time.Duration {
+	wait := cfg.BaseBackoff
+	for i := 1; i < attempts && wait < cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > cfg.MaxBackoff {
+		wait = cfg.MaxBackoff
+	}
+	return wait
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, ep WebhookEndpoint, delivery *webhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(delivery.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookDeliveryHeader, delivery.id)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(ep.Secret, delivery.body))
+
+	resp, err := d.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return nil
+}
+
+// SignWebhook returns the signature header value for body.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newDeliveryID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+func (s *Server) handleWebhookStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	statuses := []WebhookStatus{}
+	if s.webhooks != nil {
+		statuses = s.webhooks.Status()
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"webhooks": statuses,
+	})
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type hookReceiver struct {
+	mu         sync.Mutex
+	failFirst  int
+	calls      int
+	deliveries []string
+	signatures []string
+	bodies     []string
+}
+
+func (h *hookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	h.deliveries = append(h.deliveries, r.Header.Get(WebhookDeliveryHeader))
+	h.signatures = append(h.signatures, r.Header.Get(WebhookSignatureHeader))
+	h.bodies = append(h.bodies, string(body))
+	if h.calls <= h.failFirst {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *hookReceiver) callCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebhookRetriesWithSignatureAndDeliveryID(t *testing.T) {
+	receiver := &hookReceiver{failFirst: 2}
+	hook := httptest.NewServer(receiver)
+	defer hook.Close()
+
+	store := newTestStore(t, bulkBoard)
+	d := NewWebhookDispatcher([]WebhookEndpoint{{URL: hook.URL, Secret: "s3cret"}}, WebhookConfig{BaseBackoff: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx, store)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	waitFor(t, "dispatcher to subscribe", func() bool { return store.ObserverCount() == 1 })
+
+	if _, _, err := store.MoveTask("t3", MoveTaskRequest{Location: LocationBackburner}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	waitFor(t, "delivery", func() bool { return d.Status()[0].Delivered == 1 })
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	if receiver.calls != 3 {
+		t.Fatalf("expected 2 failures then success, got %d calls", receiver.calls)
+	}
+	for i := range receiver.deliveries {
+		if receiver.deliveries[i] == "" || receiver.deliveries[i] != receiver.deliveries[0] {
+			t.Fatalf("expected one delivery ID across retries, got %v", receiver.deliveries)
+		}
+		if want := SignWebhook("s3cret", []byte(receiver.bodies[i])); receiver.signatures[i] != want {
+			t.Fatalf("expected signature %s, got %s", want, receiver.signatures[i])
+		}
+	}
+	status := d.Status()[0]
+	if status.LastSuccess == nil || status.Pending != 0 || status.ConsecutiveFailures != 0 || len(status.RecentFailures) != 2 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestWebhookGivesUpAfterMaxAttempts(t *testing.T) {
+	receiver := &hookReceiver{failFirst: 100}
+	hook := httptest.NewServer(receiver)
+	defer hook.Close()
+
+	d := NewWebhookDispatcher([]WebhookEndpoint{{URL: hook.URL}}, WebhookConfig{MaxAttempts: 3, BaseBackoff: time.Millisecond, BreakerThreshold: 100})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.deliverLoop(ctx, d.targets[0])
+	if err := d.Enqueue(BoardEvent{Revision: 1}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	waitFor(t, "delivery dropped", func() bool { return d.Status()[0].Dropped == 1 })
+
+	status := d.Status()[0]
+	if receiver.callCount() != 3 || status.Pending != 0 {
+		t.Fatalf("expected 3 attempts and an empty queue, got %d calls, %+v", receiver.callCount(), status)
+	}
+	if last := status.RecentFailures[len(status.RecentFailures)-1]; !last.GaveUp || last.Attempt != 3 {
+		t.Fatalf("expected final failure marked gave up, got %+v", last)
+	}
+}
+
+func TestWebhookCircuitBreakerPausesEndpoint(t *testing.T) {
+	receiver := &hookReceiver{failFirst: 100}
+	hook := httptest.NewServer(receiver)
+	defer hook.Close()
+
+	d := NewWebhookDispatcher([]WebhookEndpoint{{URL: hook.URL}}, WebhookConfig{
+		BaseBackoff:      time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.deliverLoop(ctx, d.targets[0])
+	if err := d.Enqueue(BoardEvent{Revision: 1}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	waitFor(t, "circuit to open", func() bool { return d.Status()[0].CircuitOpenUntil != nil })
+	time.Sleep(20 * time.Millisecond)
+
+	if got := receiver.callCount(); got != 2 {
+		t.Fatalf("expected no attempts while the circuit is open, got %d", got)
+	}
+	if status := d.Status()[0]; status.Pending != 1 || status.ConsecutiveFailures != 2 {
+		t.Fatalf("expected delivery held for later, got %+v", status)
+	}
+}
+
+func TestWebhookQueueIsBounded(t *testing.T) {
+	d := NewWebhookDispatcher([]WebhookEndpoint{{URL: "http://127.0.0.1:0"}}, WebhookConfig{QueueSize: 2})
+	for rev := int64(1); rev <= 3; rev++ {
+		if err := d.Enqueue(BoardEvent{Revision: rev}); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	if status := d.Status()[0]; status.Pending != 2 || status.Dropped != 1 {
+		t.Fatalf("expected oldest dropped, got %+v", status)
+	}
+	if got := string(d.targets[0].queue[0].body); got != `{"revision":2}` {
+		t.Fatalf("expected revision 2 at the head, got %s", got)
+	}
+}
+
+func TestWebhookStatusEndpoint(t *testing.T) {
+	d := NewWebhookDispatcher([]WebhookEndpoint{{URL: "http://example.test/hook", Secret: "x"}}, WebhookConfig{})
+	if err := d.Enqueue(BoardEvent{Revision: 1}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	srv := NewServer(newTestStore(t, bulkBoard), WithWebhooks(d))
+	rec := doRequest(t, srv, http.MethodGet, "/admin/webhooks", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Webhooks []WebhookStatus `json:"webhooks"`
+	}
+	decodeBody(t, rec, &resp)
+	if len(resp.Webhooks) != 1 || resp.Webhooks[0].URL != "http://example.test/hook" || resp.Webhooks[0].Pending != 1 {
+		t.Fatalf("unexpected status %+v", resp.Webhooks)
+	}
+
+	// without webhooks configured the report is empty rather than missing
+	rec = doRequest(t, NewServer(newTestStore(t, bulkBoard)), http.MethodGet, "/admin/webhooks", "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "{\"webhooks\":[]}\n" {
+		t.Fatalf("expected empty list, got %d %q", rec.Code, rec.Body.String())
+	}
+}